@@ -44,12 +44,44 @@ func echoHandlerFrom(h func(context.Context) error) echo.HandlerFunc {
 	}
 }
 
+// ipExtractorFrom Returns the extractor of the client ips, which only trusts the
+// X-Forwarded-For headers set by the proxies in the provided CIDRs.
+func ipExtractorFrom(proxies []string) (echo.IPExtractor, error) {
+	if len(proxies) == 0 {
+		return echo.ExtractIPDirect(), nil
+	}
+
+	options := []echo.TrustOption{
+		echo.TrustLoopback(false),
+		echo.TrustLinkLocal(false),
+		echo.TrustPrivateNet(false),
+	}
+
+	for _, proxy := range proxies {
+		_, ipRange, err := net.ParseCIDR(proxy)
+		if err != nil {
+			return nil, err
+		}
+
+		options = append(options, echo.TrustIPRange(ipRange))
+	}
+
+	return echo.ExtractIPFromXFFHeader(options...), nil
+}
+
 // EchoOpt
 var EchoOpt = fx.Invoke(registerEchoLifecycle)
 
 // registerEchoLifecycle
 func registerEchoLifecycle(lc fx.Lifecycle, schemaConfig schema.Config, repoController *controller.Repo) {
 	ee := echo.New()
+
+	if extractor, err := ipExtractorFrom(cfg.Cog.App.TrustedProxies); err != nil {
+		cfg.Log.Fatal("cannot parse the trusted proxies", zap.Error(err))
+	} else {
+		ee.IPExtractor = extractor
+	}
+
 	ee.Use(util.ContextWrapper())
 	ee.Use(middleware.Recover())
 
@@ -58,8 +90,17 @@ func registerEchoLifecycle(lc fx.Lifecycle, schemaConfig schema.Config, repoCont
 
 	eg := ee.Group("/:domain/:repo")
 	eg.GET("/info/refs", echoHandlerFrom(repoController.InfoRefs))
+	eg.GET("/archive/*", echoHandlerFrom(repoController.Archive))
 	eg.POST("/:service", echoHandlerFrom(repoController.ServePack))
 
+	//
+	// Register Git LFS
+
+	eg.POST("/info/lfs/objects/batch", echoHandlerFrom(repoController.LfsBatch))
+	eg.GET("/info/lfs/objects/:oid", echoHandlerFrom(repoController.LfsDownload))
+	eg.PUT("/info/lfs/objects/:oid", echoHandlerFrom(repoController.LfsUpload))
+	eg.POST("/info/lfs/verify", echoHandlerFrom(repoController.LfsVerify))
+
 	//
 	// Register GraphQL
 
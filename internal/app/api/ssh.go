@@ -36,6 +36,8 @@ func registerSshServerLifecycle(lc fx.Lifecycle, repoController *controller.Repo
 
 	srv.Use(facade.GitReceivePack, repoController.ServePack)
 	srv.Use(facade.GitUploadPack, repoController.ServePack)
+	srv.Use(facade.GitUploadArchive, repoController.ServePack)
+	srv.Use(facade.GitLfsAuthenticate, repoController.LfsAuthenticate)
 
 	lc.Append(fx.Hook{
 		OnStart: func(ctx context.Context) error {
@@ -26,6 +26,7 @@ import (
 	"bitban.io/server/internal/pkg/dto"
 	"bitban.io/server/internal/pkg/facade"
 	"bitban.io/server/internal/pkg/fault"
+	"bitban.io/server/internal/pkg/validate"
 )
 
 // Account
@@ -65,6 +66,97 @@ func (c *Account) GetUser(ctx context.Context, id int64) (*dto.User, error) {
 	}
 }
 
+// GetViewer
+//
+// Errors:
+//   - fault.ErrUnauthenticated, if the request is not authorized
+func (c *Account) GetViewer(ctx context.Context) (*dto.User, error) {
+	if currAccount, err := facade.GetAccountByAccessToken(ctx); err != nil {
+		return nil, fault.ErrUnauthenticated
+	} else {
+		return dto.UserFrom(
+			currAccount.GetUser(),
+		), nil
+	}
+}
+
+// GetSshKeys
+//
+// Errors:
+//   - fault.ErrUnauthenticated, if the request is not authorized
+//   - fault.ErrForbidden, if the authorized user does not have access to the resource
+func (c *Account) GetSshKeys(ctx context.Context, id int64) ([]*dto.SshKey, error) {
+	//
+	// Check Permission
+
+	if currAccount, err := facade.GetAccountByAccessToken(ctx); err != nil {
+		return nil, fault.ErrUnauthenticated
+	} else {
+		if err := currAccount.CheckPermission(
+			fmt.Sprintf("/users/%d/ssh-keys", id),
+			"read",
+		); err != nil {
+			return nil, err
+		}
+	}
+
+	//
+	// Retrieve the Keys
+
+	if account, err := facade.GetAccountByUserId(ctx, id); err != nil {
+		return nil, err
+	} else {
+		if sshKeys, err := account.GetSshKeys(); err != nil {
+			return nil, err
+		} else {
+			return dto.SshKeysFrom(sshKeys), nil
+		}
+	}
+}
+
+// AddSshKey
+//
+// Errors:
+//   - fault.ErrUnauthenticated, if the request is not authorized
+//   - fault.UserInputError, if the provided input is invalid
+// ErrorsRef:
+//   - facade.Account.AddSshKey
+func (c *Account) AddSshKey(ctx context.Context, input dto.AddSshKeyInput) (*dto.SshKey, error) {
+	if currAccount, err := facade.GetAccountByAccessToken(ctx); err != nil {
+		return nil, fault.ErrUnauthenticated
+	} else {
+		if err := validate.
+			GetValidateInstance().
+			Struct(input); err != nil {
+			return nil, fault.UserInputErrorFrom(err)
+		}
+
+		if sshKey, err := currAccount.AddSshKey(input); err != nil {
+			return nil, err
+		} else {
+			return dto.SshKeyFrom(sshKey), nil
+		}
+	}
+}
+
+// RemoveSshKey
+//
+// Errors:
+//   - fault.ErrUnauthenticated, if the request is not authorized
+// ErrorsRef:
+//   - facade.Account.RemoveSshKey
+func (c *Account) RemoveSshKey(ctx context.Context, id int64) (*dto.SshKey, error) {
+	if currAccount, err := facade.GetAccountByAccessToken(ctx); err != nil {
+		return nil, fault.ErrUnauthenticated
+	} else {
+		if sshKey, err := currAccount.RemoveSshKey(id); err != nil {
+			return nil, err
+		} else {
+			return dto.SshKeyFrom(sshKey), nil
+		}
+	}
+}
+
 // AccountOpt
 var AccountOpt = fx.Provide(newAccount)
 
@@ -0,0 +1,180 @@
+/*
+ * Copyright 2021 Meraj Sahebdar
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controller
+
+import (
+	"golang.org/x/net/context"
+	"bitban.io/server/internal/pkg/dto"
+	"bitban.io/server/internal/pkg/facade"
+	"bitban.io/server/internal/pkg/fault"
+	"bitban.io/server/internal/pkg/validate"
+)
+
+// collaboratorErrorFrom Maps the errors of collaborators to input errors.
+func collaboratorErrorFrom(err error) error {
+	switch err {
+	case facade.ErrInvalidCollaboratorRole:
+		ret := fault.UserInputErrorFrom(err)
+		ret.AddError("role", "oneof", err.Error())
+
+		return ret
+	case facade.ErrCollaboratorIsOwner:
+		ret := fault.UserInputErrorFrom(err)
+		ret.AddError("userId", "owner", err.Error())
+
+		return ret
+	case facade.ErrCollaboratorExists:
+		ret := fault.UserInputErrorFrom(err)
+		ret.AddError("userId", "unique", err.Error())
+
+		return ret
+	}
+
+	return err
+}
+
+// getAccountByNode Returns the account of the user using its node identifier.
+//
+// Errors:
+//   - fault.ErrResourceNotFound if the identifier does not belong to a user
+// ErrorsRef:
+//   - facade.GetAccountByUserId
+func getAccountByNode(ctx context.Context, nIdentifier string) (*facade.Account, error) {
+	if nType, id, err := dto.FromNodeIdentifier(nIdentifier); err != nil || nType != dto.UserNodeType {
+		return nil, fault.ErrResourceNotFound
+	} else {
+		return facade.GetAccountByUserId(ctx, id)
+	}
+}
+
+// GetCollaborators
+//
+// ErrorsRef:
+//   - controller.Repo.getAuthorizedRepo
+func (c *Repo) GetCollaborators(ctx context.Context, id int64) ([]*dto.Collaborator, error) {
+	_, repo, err := c.getAuthorizedRepo(ctx, id, "admin")
+	if err != nil {
+		return nil, err
+	}
+
+	collaborators, err := repo.GetCollaborators()
+	if err != nil {
+		return nil, err
+	}
+
+	ret := make([]*dto.Collaborator, 0, len(collaborators))
+	for _, collaborator := range collaborators {
+		ret = append(ret, dto.CollaboratorFrom(collaborator.Account.GetUser(), collaborator.Role))
+	}
+
+	return ret, nil
+}
+
+// AddCollaborator
+//
+// Errors:
+//   - fault.UserInputError if the provided input is invalid
+// ErrorsRef:
+//   - controller.Repo.getAuthorizedRepoByNode
+//   - controller.getAccountByNode
+func (c *Repo) AddCollaborator(ctx context.Context, input dto.CollaboratorInput) (*dto.Collaborator, error) {
+	if err := validate.
+		GetValidateInstance().
+		Struct(input); err != nil {
+		return nil, fault.UserInputErrorFrom(err)
+	}
+
+	_, repo, err := c.getAuthorizedRepoByNode(ctx, input.RepositoryID, "admin")
+	if err != nil {
+		return nil, err
+	}
+
+	account, err := getAccountByNode(ctx, input.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	if collaborator, err := repo.AddCollaborator(account, input.Role); err != nil {
+		return nil, collaboratorErrorFrom(err)
+	} else {
+		return dto.CollaboratorFrom(collaborator.Account.GetUser(), collaborator.Role), nil
+	}
+}
+
+// UpdateCollaboratorRole
+//
+// Errors:
+//   - fault.UserInputError if the provided input is invalid
+// ErrorsRef:
+//   - controller.Repo.getAuthorizedRepoByNode
+//   - controller.getAccountByNode
+//   - facade.Repo.UpdateCollaboratorRole
+func (c *Repo) UpdateCollaboratorRole(ctx context.Context, input dto.CollaboratorInput) (*dto.Collaborator, error) {
+	if err := validate.
+		GetValidateInstance().
+		Struct(input); err != nil {
+		return nil, fault.UserInputErrorFrom(err)
+	}
+
+	_, repo, err := c.getAuthorizedRepoByNode(ctx, input.RepositoryID, "admin")
+	if err != nil {
+		return nil, err
+	}
+
+	account, err := getAccountByNode(ctx, input.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	if collaborator, err := repo.UpdateCollaboratorRole(account, input.Role); err != nil {
+		return nil, collaboratorErrorFrom(err)
+	} else {
+		return dto.CollaboratorFrom(collaborator.Account.GetUser(), collaborator.Role), nil
+	}
+}
+
+// RemoveCollaborator
+//
+// Errors:
+//   - fault.UserInputError if the provided input is invalid
+// ErrorsRef:
+//   - controller.Repo.getAuthorizedRepoByNode
+//   - controller.getAccountByNode
+//   - facade.Repo.RemoveCollaborator
+func (c *Repo) RemoveCollaborator(ctx context.Context, input dto.RemoveCollaboratorInput) (*dto.Collaborator, error) {
+	if err := validate.
+		GetValidateInstance().
+		Struct(input); err != nil {
+		return nil, fault.UserInputErrorFrom(err)
+	}
+
+	_, repo, err := c.getAuthorizedRepoByNode(ctx, input.RepositoryID, "admin")
+	if err != nil {
+		return nil, err
+	}
+
+	account, err := getAccountByNode(ctx, input.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	if collaborator, err := repo.RemoveCollaborator(account); err != nil {
+		return nil, err
+	} else {
+		return dto.CollaboratorFrom(collaborator.Account.GetUser(), collaborator.Role), nil
+	}
+}
@@ -0,0 +1,54 @@
+/*
+ * Copyright 2021 Meraj Sahebdar
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controller
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"bitban.io/server/internal/pkg/orm"
+	"bitban.io/server/internal/pkg/util"
+	"bitban.io/server/test"
+)
+
+func TestMain(m *testing.M) {
+	test.CreatePostgresContainer()
+	orm.MigrateUp()
+	m.Run()
+	orm.MigrateDown(0)
+}
+
+// serveTestContext Calls the function with the context of the request served
+// by echo, as the controllers read the request through it.
+func serveTestContext(t *testing.T, req *http.Request, fn func(ctx context.Context)) *httptest.ResponseRecorder {
+	t.Helper()
+
+	ee := echo.New()
+	ee.Use(util.ContextWrapper())
+	ee.Any("/*", func(ec echo.Context) error {
+		fn(ec.Request().Context())
+		return nil
+	})
+
+	rec := httptest.NewRecorder()
+	ee.ServeHTTP(rec, req)
+
+	return rec
+}
@@ -0,0 +1,131 @@
+/*
+ * Copyright 2021 Meraj Sahebdar
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controller
+
+import (
+	"fmt"
+
+	"golang.org/x/net/context"
+	"bitban.io/server/internal/pkg/dto"
+	"bitban.io/server/internal/pkg/facade"
+	"bitban.io/server/internal/pkg/fault"
+	"bitban.io/server/internal/pkg/validate"
+)
+
+// ForkRepository Copies a readable repository into a domain administered by
+// the current user, which defaults to the user's own domain.
+//
+// Errors:
+//   - fault.ErrUnauthenticated if the request is not authorized
+//   - fault.UserInputError if the provided input is invalid
+//   - fault.ErrResourceNotFound if there is no such domain
+//   - fault.ErrForbidden if the current user does not administer the domain
+// ErrorsRef:
+//   - controller.Repo.getAuthorizedRepoByNode
+//   - facade.Repo.Fork
+func (c *Repo) ForkRepository(ctx context.Context, input dto.ForkRepositoryInput) (*dto.Repository, error) {
+	currAccount, err := facade.GetAccountByAccessToken(ctx)
+	if err != nil {
+		return nil, fault.ErrUnauthenticated
+	}
+
+	if err := validate.
+		GetValidateInstance().
+		Struct(input); err != nil {
+		return nil, fault.UserInputErrorFrom(err)
+	}
+
+	_, source, err := c.getAuthorizedRepoByNode(ctx, input.ID, "read")
+	if err != nil {
+		return nil, err
+	}
+
+	domain := currAccount.GetDomain()
+	if input.Domain != nil {
+		if domain, err = facade.GetDomainByAddress(ctx, *input.Domain); err != nil {
+			return nil, err
+		}
+
+		if err := currAccount.CheckDomainPermission(domain, "admin"); err != nil {
+			return nil, err
+		}
+	}
+
+	address := source.GetEntity().Address
+	if input.Address != nil {
+		address = *input.Address
+	}
+
+	if repo, err := source.Fork(domain, address); err != nil {
+		return nil, moveErrorFrom(err, "address")
+	} else {
+		if err := currAccount.GrantOwnershipIn(
+			domain.Address,
+			fmt.Sprintf("/repositories/%d", repo.GetID()),
+		); err != nil {
+			return nil, err
+		}
+
+		return dto.RepositoryFrom(repo.GetEntity()), nil
+	}
+}
+
+// GetParent Returns the repository the given one is forked from, or nil if
+// the current user may not read it.
+//
+// ErrorsRef:
+//   - controller.Repo.getAuthorizedRepo
+func (c *Repo) GetParent(ctx context.Context, id int64) (*dto.Repository, error) {
+	currAccount, repo, err := c.getAuthorizedRepo(ctx, id, "read")
+	if err != nil {
+		return nil, err
+	}
+
+	if parent, err := repo.GetParent(); err != nil || parent == nil {
+		return nil, err
+	} else if err := facade.CheckRepoPermission(currAccount, parent, "read"); err != nil {
+		return nil, nil
+	} else {
+		return dto.RepositoryFrom(parent.GetEntity()), nil
+	}
+}
+
+// GetForks Returns the forks of the given repository which the current user
+// may read.
+//
+// ErrorsRef:
+//   - controller.Repo.getAuthorizedRepo
+func (c *Repo) GetForks(ctx context.Context, id int64) ([]*dto.Repository, error) {
+	currAccount, repo, err := c.getAuthorizedRepo(ctx, id, "read")
+	if err != nil {
+		return nil, err
+	}
+
+	forks, err := repo.GetForks()
+	if err != nil {
+		return nil, err
+	}
+
+	ret := make([]*dto.Repository, 0, len(forks))
+	for _, fork := range forks {
+		if err := facade.CheckRepoPermission(currAccount, fork, "read"); err == nil {
+			ret = append(ret, dto.RepositoryFrom(fork.GetEntity()))
+		}
+	}
+
+	return ret, nil
+}
@@ -0,0 +1,392 @@
+/*
+ * Copyright 2021 Meraj Sahebdar
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+	"golang.org/x/net/context"
+	"bitban.io/server/internal/cfg"
+	"bitban.io/server/internal/pkg/facade"
+	"bitban.io/server/internal/pkg/ssh"
+	"bitban.io/server/internal/pkg/util"
+)
+
+// lfsMediaType The content type of the git lfs api.
+const lfsMediaType = "application/vnd.git-lfs+json"
+
+// lfsObject
+type lfsObject struct {
+	Oid  string `json:"oid"`
+	Size int64  `json:"size"`
+}
+
+// lfsAction Tells the client where and how to transfer an object.
+type lfsAction struct {
+	Href      string            `json:"href"`
+	Header    map[string]string `json:"header,omitempty"`
+	ExpiresIn int64             `json:"expires_in,omitempty"`
+}
+
+// lfsObjectError
+type lfsObjectError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// lfsObjectResult
+type lfsObjectResult struct {
+	lfsObject
+	Authenticated bool                  `json:"authenticated,omitempty"`
+	Actions       map[string]*lfsAction `json:"actions,omitempty"`
+	Error         *lfsObjectError       `json:"error,omitempty"`
+}
+
+// lfsBatchRequest
+type lfsBatchRequest struct {
+	Operation string       `json:"operation"`
+	Transfers []string     `json:"transfers"`
+	Objects   []*lfsObject `json:"objects"`
+	HashAlgo  string       `json:"hash_algo"`
+}
+
+// lfsBatchResponse
+type lfsBatchResponse struct {
+	Transfer string             `json:"transfer"`
+	Objects  []*lfsObjectResult `json:"objects"`
+	HashAlgo string             `json:"hash_algo"`
+}
+
+// lfsHrefOf Returns the address of the git lfs api of the repository.
+func lfsHrefOf(repo *facade.Repo) string {
+	// The configured url is used rather than the host of the request, which
+	// the clients control.
+	base := cfg.Cog.App.Url
+	if base == "" {
+		base = fmt.Sprintf("http://localhost:%d", cfg.Cog.App.Port)
+	}
+
+	return fmt.Sprintf(
+		"%s/%s/%s.git/info/lfs",
+		strings.TrimSuffix(base, "/"),
+		repo.GetDomainAddress(),
+		repo.GetEntity().Address,
+	)
+}
+
+// lfsErrorFrom Maps the lfs errors of the facade to http errors.
+func lfsErrorFrom(err error) error {
+	switch err {
+	case facade.ErrLfsObjectNotFound:
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	case facade.ErrInvalidLfsOid, facade.ErrLfsObjectMismatch:
+		return echo.NewHTTPError(http.StatusUnprocessableEntity, err.Error())
+	case facade.ErrMirrorReadOnly:
+		return echo.NewHTTPError(http.StatusForbidden, err.Error())
+	}
+
+	return err
+}
+
+// resolveLfsRepo Returns the repository the git lfs request is made to.
+// Former addresses resolve in place, since the clients do not follow the
+// redirects of the api.
+func (c *Repo) resolveLfsRepo(ec echo.Context) (*facade.Repo, error) {
+	if repo, _, err := facade.ResolveRepoByAddress(
+		ec.Request().Context(),
+		ec.Param("domain"),
+		strings.TrimSuffix(ec.Param("repo"), ".git"),
+	); err != nil {
+		return nil, echo.NewHTTPError(http.StatusNotFound)
+	} else {
+		return repo, nil
+	}
+}
+
+// authorizeByLfs Returns the account allowed to perform the git lfs operation
+// on the repository, authenticated either by a token handed out over ssh or
+// by the basic auth of the request.
+func (c *Repo) authorizeByLfs(ctx context.Context, repo *facade.Repo, operation string) (*facade.Account, error) {
+	ec := util.MustGetEchoContext(ctx)
+	res := ec.Response()
+
+	if strings.HasPrefix(ec.Request().Header.Get(echo.HeaderAuthorization), "Bearer ") {
+		account, err := facade.GetAccountByLfsToken(ctx, repo, operation)
+		if err != nil {
+			return nil, echo.NewHTTPError(http.StatusUnauthorized)
+		}
+
+		if err := facade.CheckRepoPermission(account, repo, facade.LfsActionOf(operation)); err != nil {
+			return nil, echo.NewHTTPError(http.StatusForbidden)
+		}
+
+		return account, nil
+	}
+
+	if account, err := c.authorizeByHttp(ctx, repo, facade.LfsActionOf(operation)); err != nil {
+		if he, ok := err.(*echo.HTTPError); ok && he.Code == http.StatusUnauthorized {
+			res.Header().Set("LFS-Authenticate", `Basic realm="Restricted"`)
+		}
+
+		return nil, err
+	} else {
+		return account, nil
+	}
+}
+
+// LfsBatch Tells the client how to transfer the requested objects.
+func (c *Repo) LfsBatch(ctx context.Context) error {
+	ec := util.MustGetEchoContext(ctx)
+	req := ec.Request()
+	res := ec.Response()
+
+	repo, err := c.resolveLfsRepo(ec)
+	if err != nil {
+		return err
+	}
+
+	var input lfsBatchRequest
+	if err := json.NewDecoder(req.Body).Decode(&input); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "the batch request is malformed")
+	}
+
+	if input.Operation != facade.LfsUpload && input.Operation != facade.LfsDownload {
+		return echo.NewHTTPError(http.StatusUnprocessableEntity, "the operation must be upload or download")
+	}
+
+	if input.HashAlgo != "" && input.HashAlgo != "sha256" {
+		return echo.NewHTTPError(http.StatusConflict, "the only supported hash algorithm is sha256")
+	}
+
+	if _, err := c.authorizeByLfs(ctx, repo, input.Operation); err != nil {
+		return err
+	}
+
+	if input.Operation == facade.LfsUpload {
+		if isMirror, err := repo.IsMirror(); err != nil {
+			return err
+		} else if isMirror {
+			return lfsErrorFrom(facade.ErrMirrorReadOnly)
+		}
+	}
+
+	// The transfers are authenticated the same way the batch request is.
+	var header map[string]string
+	if authorization := req.Header.Get(echo.HeaderAuthorization); authorization != "" {
+		header = map[string]string{echo.HeaderAuthorization: authorization}
+	}
+
+	href := lfsHrefOf(repo)
+	response := &lfsBatchResponse{
+		Transfer: "basic",
+		Objects:  make([]*lfsObjectResult, 0, len(input.Objects)),
+		HashAlgo: "sha256",
+	}
+
+	for _, object := range input.Objects {
+		result := &lfsObjectResult{lfsObject: *object}
+		response.Objects = append(response.Objects, result)
+
+		if !facade.IsValidLfsOid(object.Oid) || object.Size < 0 {
+			result.Error = &lfsObjectError{
+				Code:    http.StatusUnprocessableEntity,
+				Message: facade.ErrInvalidLfsOid.Error(),
+			}
+
+			continue
+		}
+
+		size, err := repo.GetLfsObjectSize(object.Oid)
+		if err != nil && err != facade.ErrLfsObjectNotFound {
+			return err
+		}
+
+		switch input.Operation {
+		case facade.LfsDownload:
+			if err == facade.ErrLfsObjectNotFound {
+				result.Error = &lfsObjectError{
+					Code:    http.StatusNotFound,
+					Message: err.Error(),
+				}
+
+				continue
+			}
+
+			result.Size = size
+			result.Authenticated = true
+			result.Actions = map[string]*lfsAction{
+				"download": {Href: href + "/objects/" + object.Oid, Header: header},
+			}
+		case facade.LfsUpload:
+			// The objects already stored need no transfer.
+			if err == nil && size == object.Size {
+				continue
+			}
+
+			result.Authenticated = true
+			result.Actions = map[string]*lfsAction{
+				"upload": {Href: href + "/objects/" + object.Oid, Header: header},
+				"verify": {Href: href + "/verify", Header: header},
+			}
+		}
+	}
+
+	res.Header().Set(echo.HeaderContentType, lfsMediaType)
+	return ec.JSON(http.StatusOK, response)
+}
+
+// LfsDownload Streams a stored object.
+func (c *Repo) LfsDownload(ctx context.Context) error {
+	ec := util.MustGetEchoContext(ctx)
+	res := ec.Response()
+
+	repo, err := c.resolveLfsRepo(ec)
+	if err != nil {
+		return err
+	}
+
+	if _, err := c.authorizeByLfs(ctx, repo, facade.LfsDownload); err != nil {
+		return err
+	}
+
+	file, size, err := repo.OpenLfsObject(ec.Param("oid"))
+	if err == facade.ErrInvalidLfsOid {
+		return echo.NewHTTPError(http.StatusNotFound, facade.ErrLfsObjectNotFound.Error())
+	} else if err != nil {
+		return lfsErrorFrom(err)
+	}
+	defer file.Close()
+
+	res.Header().Set(echo.HeaderContentType, echo.MIMEOctetStream)
+	res.Header().Set(echo.HeaderContentLength, strconv.FormatInt(size, 10))
+	res.WriteHeader(http.StatusOK)
+
+	if _, err := io.Copy(res.Writer, file); err != nil {
+		cfg.Log.Error("got an error on writing an lfs object", zap.Error(err))
+	}
+
+	return nil
+}
+
+// LfsUpload Stores the object in the request body.
+func (c *Repo) LfsUpload(ctx context.Context) error {
+	ec := util.MustGetEchoContext(ctx)
+	req := ec.Request()
+
+	repo, err := c.resolveLfsRepo(ec)
+	if err != nil {
+		return err
+	}
+
+	if _, err := c.authorizeByLfs(ctx, repo, facade.LfsUpload); err != nil {
+		return err
+	}
+
+	if req.ContentLength < 0 {
+		return echo.NewHTTPError(http.StatusLengthRequired)
+	}
+
+	if err := repo.WriteLfsObject(ec.Param("oid"), req.ContentLength, req.Body); err != nil {
+		return lfsErrorFrom(err)
+	}
+
+	return ec.NoContent(http.StatusOK)
+}
+
+// LfsVerify Confirms that an uploaded object is stored as announced.
+func (c *Repo) LfsVerify(ctx context.Context) error {
+	ec := util.MustGetEchoContext(ctx)
+	req := ec.Request()
+
+	repo, err := c.resolveLfsRepo(ec)
+	if err != nil {
+		return err
+	}
+
+	if _, err := c.authorizeByLfs(ctx, repo, facade.LfsUpload); err != nil {
+		return err
+	}
+
+	var input lfsObject
+	if err := json.NewDecoder(req.Body).Decode(&input); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "the verify request is malformed")
+	}
+
+	if err := repo.VerifyLfsObject(input.Oid, input.Size); err != nil {
+		return lfsErrorFrom(err)
+	}
+
+	return ec.NoContent(http.StatusOK)
+}
+
+// LfsAuthenticate Hands the address of the git lfs api, along with a token
+// granting the requested operation, to the clients connected over ssh.
+func (c *Repo) LfsAuthenticate(ctx context.Context) error {
+	ch, err := ssh.GetContextCh(ctx)
+	if err != nil {
+		return err
+	}
+
+	args := strings.Fields(ssh.GetContextCmd(ctx).Args)
+	if len(args) != 2 {
+		return errors.New("the requested command is wrong")
+	}
+
+	paths := strings.SplitN(args[0], "/", 2)
+	if len(paths) != 2 {
+		return errors.New("the requested repository does not exist")
+	}
+
+	repo, _, err := facade.ResolveRepoByAddress(ctx, paths[0], strings.TrimSuffix(paths[1], ".git"))
+	if err != nil {
+		return errors.New("the requested repository does not exist")
+	}
+
+	operation := args[1]
+
+	account, err := ssh.GetContextAccount(ctx)
+	if err != nil {
+		account = nil
+	}
+
+	if err := facade.CheckRepoPermission(account, repo, facade.LfsActionOf(operation)); err != nil {
+		return err
+	}
+
+	response := &lfsAction{Href: lfsHrefOf(repo)}
+
+	// Anonymous clients may only download the public objects, which needs no
+	// credentials.
+	if account != nil {
+		if token, lifetime, err := account.CreateLfsToken(repo, operation); err != nil {
+			return err
+		} else {
+			response.Header = map[string]string{echo.HeaderAuthorization: "Bearer " + token}
+			response.ExpiresIn = int64(lifetime.Seconds())
+		}
+	}
+
+	return json.NewEncoder(ch).Encode(response)
+}
@@ -0,0 +1,232 @@
+/*
+ * Copyright 2021 Meraj Sahebdar
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controller
+
+import (
+	"golang.org/x/net/context"
+	"bitban.io/server/internal/pkg/dto"
+	"bitban.io/server/internal/pkg/facade"
+	"bitban.io/server/internal/pkg/fault"
+	"bitban.io/server/internal/pkg/validate"
+)
+
+// GetMirror Returns the pull mirror configuration of the repository, or nil
+// if it is not a mirror.
+//
+// ErrorsRef:
+//   - controller.Repo.getReadableRepo
+func (c *Repo) GetMirror(ctx context.Context, id int64) (*dto.PullMirror, error) {
+	repo, err := c.getReadableRepo(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if mirror, err := repo.GetMirror(); err != nil {
+		return nil, err
+	} else {
+		return dto.PullMirrorFrom(mirror), nil
+	}
+}
+
+// SetMirror
+//
+// Errors:
+//   - fault.UserInputError if the provided input is invalid
+// ErrorsRef:
+//   - controller.Repo.getAuthorizedRepoByNode
+//   - facade.Repo.SetMirror
+func (c *Repo) SetMirror(ctx context.Context, input dto.SetMirrorInput) (*dto.PullMirror, error) {
+	if err := validate.
+		GetValidateInstance().
+		Struct(input); err != nil {
+		return nil, fault.UserInputErrorFrom(err)
+	}
+
+	_, repo, err := c.getAuthorizedRepoByNode(ctx, input.ID, "admin")
+	if err != nil {
+		return nil, err
+	}
+
+	if mirror, err := repo.SetMirror(input.URL); err == facade.ErrInvalidMirrorURL {
+		ret := fault.UserInputErrorFrom(err)
+		ret.AddError("url", "url", err.Error())
+
+		return nil, ret
+	} else if err != nil {
+		return nil, err
+	} else {
+		return dto.PullMirrorFrom(mirror), nil
+	}
+}
+
+// RemoveMirror
+//
+// ErrorsRef:
+//   - controller.Repo.getAuthorizedRepoByNode
+//   - facade.Repo.RemoveMirror
+func (c *Repo) RemoveMirror(ctx context.Context, nIdentifier string) (*dto.PullMirror, error) {
+	_, repo, err := c.getAuthorizedRepoByNode(ctx, nIdentifier, "admin")
+	if err != nil {
+		return nil, err
+	}
+
+	if mirror, err := repo.RemoveMirror(); err != nil {
+		return nil, err
+	} else {
+		return dto.PullMirrorFrom(mirror), nil
+	}
+}
+
+// SyncMirror Fetches the remote of the mirror right away.
+//
+// ErrorsRef:
+//   - controller.Repo.getAuthorizedRepoByNode
+//   - facade.Repo.SyncMirror
+func (c *Repo) SyncMirror(ctx context.Context, nIdentifier string) (*dto.PullMirror, error) {
+	_, repo, err := c.getAuthorizedRepoByNode(ctx, nIdentifier, "write")
+	if err != nil {
+		return nil, err
+	}
+
+	if mirror, err := repo.SyncMirror(); err != nil {
+		return nil, err
+	} else {
+		return dto.PullMirrorFrom(mirror), nil
+	}
+}
+
+// pushMirrorErrorFrom Maps the errors of push mirrors to input errors.
+func pushMirrorErrorFrom(err error) error {
+	switch err {
+	case facade.ErrInvalidMirrorURL:
+		ret := fault.UserInputErrorFrom(err)
+		ret.AddError("url", "url", err.Error())
+
+		return ret
+	case facade.ErrInvalidMirrorCredentials:
+		ret := fault.UserInputErrorFrom(err)
+		ret.AddError("password", "credentials", err.Error())
+
+		return ret
+	}
+
+	return err
+}
+
+// getAuthorizedRepoByPushMirror Returns the repository of the push mirror if
+// the current user is allowed to administer it.
+//
+// Errors:
+//   - fault.ErrResourceNotFound if the identifier does not belong to a push mirror
+// ErrorsRef:
+//   - facade.GetRepoByPushMirrorId
+//   - controller.Repo.getAuthorizedRepo
+func (c *Repo) getAuthorizedRepoByPushMirror(ctx context.Context, nIdentifier string) (int64, *facade.Repo, error) {
+	if nType, id, err := dto.FromNodeIdentifier(nIdentifier); err != nil || nType != dto.PushMirrorNodeType {
+		return 0, nil, fault.ErrResourceNotFound
+	} else {
+		if repo, err := facade.GetRepoByPushMirrorId(ctx, id); err != nil {
+			return 0, nil, err
+		} else {
+			_, repo, err := c.getAuthorizedRepo(ctx, repo.GetID(), "admin")
+			return id, repo, err
+		}
+	}
+}
+
+// GetPushMirrors
+//
+// ErrorsRef:
+//   - controller.Repo.getAuthorizedRepo
+func (c *Repo) GetPushMirrors(ctx context.Context, id int64) ([]*dto.PushMirror, error) {
+	_, repo, err := c.getAuthorizedRepo(ctx, id, "admin")
+	if err != nil {
+		return nil, err
+	}
+
+	if mirrors, err := repo.GetPushMirrors(); err != nil {
+		return nil, err
+	} else {
+		return dto.PushMirrorsFrom(mirrors), nil
+	}
+}
+
+// AddPushMirror
+//
+// Errors:
+//   - fault.UserInputError if the provided input is invalid
+// ErrorsRef:
+//   - controller.Repo.getAuthorizedRepoByNode
+func (c *Repo) AddPushMirror(ctx context.Context, input dto.AddPushMirrorInput) (*dto.PushMirror, error) {
+	if err := validate.
+		GetValidateInstance().
+		Struct(input); err != nil {
+		return nil, fault.UserInputErrorFrom(err)
+	}
+
+	_, repo, err := c.getAuthorizedRepoByNode(ctx, input.RepositoryID, "admin")
+	if err != nil {
+		return nil, err
+	}
+
+	if mirror, err := repo.AddPushMirror(&facade.PushMirrorConfig{
+		URL:      input.URL,
+		Username: input.Username,
+		Password: input.Password,
+		IsActive: input.IsActive,
+	}); err != nil {
+		return nil, pushMirrorErrorFrom(err)
+	} else {
+		return dto.PushMirrorFrom(mirror), nil
+	}
+}
+
+// RemovePushMirror
+//
+// ErrorsRef:
+//   - controller.Repo.getAuthorizedRepoByPushMirror
+//   - facade.Repo.RemovePushMirror
+func (c *Repo) RemovePushMirror(ctx context.Context, nIdentifier string) (*dto.PushMirror, error) {
+	id, repo, err := c.getAuthorizedRepoByPushMirror(ctx, nIdentifier)
+	if err != nil {
+		return nil, err
+	}
+
+	if mirror, err := repo.RemovePushMirror(id); err != nil {
+		return nil, err
+	} else {
+		return dto.PushMirrorFrom(mirror), nil
+	}
+}
+
+// SyncPushMirror Pushes every reference of the repository to the mirror right away.
+//
+// ErrorsRef:
+//   - controller.Repo.getAuthorizedRepoByPushMirror
+//   - facade.Repo.SyncPushMirror
+func (c *Repo) SyncPushMirror(ctx context.Context, nIdentifier string) (*dto.PushMirror, error) {
+	id, repo, err := c.getAuthorizedRepoByPushMirror(ctx, nIdentifier)
+	if err != nil {
+		return nil, err
+	}
+
+	if mirror, err := repo.SyncPushMirror(id); err != nil {
+		return nil, err
+	} else {
+		return dto.PushMirrorFrom(mirror), nil
+	}
+}
@@ -0,0 +1,277 @@
+/*
+ * Copyright 2021 Meraj Sahebdar
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controller
+
+import (
+	"context"
+
+	"go.uber.org/fx"
+	"bitban.io/server/internal/pkg/dto"
+	"bitban.io/server/internal/pkg/facade"
+	"bitban.io/server/internal/pkg/fault"
+	"bitban.io/server/internal/pkg/validate"
+)
+
+// Organization
+type Organization struct{}
+
+// memberErrorFrom Maps the errors of organization members to input errors.
+func memberErrorFrom(err error) error {
+	switch err {
+	case facade.ErrInvalidMemberRole:
+		ret := fault.UserInputErrorFrom(err)
+		ret.AddError("role", "oneof", err.Error())
+
+		return ret
+	case facade.ErrMemberExists:
+		ret := fault.UserInputErrorFrom(err)
+		ret.AddError("userId", "unique", err.Error())
+
+		return ret
+	case facade.ErrLastOwner:
+		ret := fault.UserInputErrorFrom(err)
+		ret.AddError("userId", "owner", err.Error())
+
+		return ret
+	}
+
+	return err
+}
+
+// getAuthorizedOrganization Returns the organization if the current user is
+// allowed to perform the action on it.
+//
+// Errors:
+//   - fault.ErrUnauthenticated if the request is not authorized
+//   - fault.ErrForbidden if the authorized user does not have access to the organization
+// ErrorsRef:
+//   - facade.GetOrganizationById
+func (c *Organization) getAuthorizedOrganization(ctx context.Context, id int64, act string) (*facade.Account, *facade.Organization, error) {
+	currAccount, err := facade.GetAccountByAccessToken(ctx)
+	if err != nil {
+		return nil, nil, fault.ErrUnauthenticated
+	}
+
+	if org, err := facade.GetOrganizationById(ctx, id); err != nil {
+		return nil, nil, err
+	} else {
+		if err := currAccount.CheckDomainPermission(org.GetDomain(), act); err != nil {
+			return nil, nil, err
+		}
+
+		return currAccount, org, nil
+	}
+}
+
+// getAuthorizedOrganizationByNode Same as `getAuthorizedOrganization` using a
+// node identifier.
+//
+// Errors:
+//   - fault.ErrResourceNotFound if the identifier does not belong to an organization
+// ErrorsRef:
+//   - controller.Organization.getAuthorizedOrganization
+func (c *Organization) getAuthorizedOrganizationByNode(ctx context.Context, nIdentifier string, act string) (*facade.Account, *facade.Organization, error) {
+	if nType, id, err := dto.FromNodeIdentifier(nIdentifier); err != nil || nType != dto.OrganizationNodeType {
+		return nil, nil, fault.ErrResourceNotFound
+	} else {
+		return c.getAuthorizedOrganization(ctx, id, act)
+	}
+}
+
+// GetOrganization
+//
+// ErrorsRef:
+//   - controller.Organization.getAuthorizedOrganization
+func (c *Organization) GetOrganization(ctx context.Context, id int64) (*dto.Organization, error) {
+	if _, org, err := c.getAuthorizedOrganization(ctx, id, "read"); err != nil {
+		return nil, err
+	} else {
+		return dto.OrganizationFrom(org.GetEntity()), nil
+	}
+}
+
+// GetOrganizationByAddress
+//
+// ErrorsRef:
+//   - facade.GetOrganizationByAddress
+//   - controller.Organization.getAuthorizedOrganization
+func (c *Organization) GetOrganizationByAddress(ctx context.Context, address string) (*dto.Organization, error) {
+	if org, err := facade.GetOrganizationByAddress(ctx, address); err != nil {
+		return nil, err
+	} else {
+		return c.GetOrganization(ctx, org.GetID())
+	}
+}
+
+// GetMembers
+//
+// ErrorsRef:
+//   - controller.Organization.getAuthorizedOrganization
+func (c *Organization) GetMembers(ctx context.Context, id int64) ([]*dto.OrganizationMember, error) {
+	_, org, err := c.getAuthorizedOrganization(ctx, id, "read")
+	if err != nil {
+		return nil, err
+	}
+
+	members, err := org.GetMembers()
+	if err != nil {
+		return nil, err
+	}
+
+	ret := make([]*dto.OrganizationMember, 0, len(members))
+	for _, member := range members {
+		ret = append(ret, dto.OrganizationMemberFrom(member.Account.GetUser(), member.Role))
+	}
+
+	return ret, nil
+}
+
+// CreateOrganization Creates an organization owned by the current user.
+//
+// Errors:
+//   - fault.ErrUnauthenticated if the request is not authorized
+//   - fault.UserInputError if the provided input is invalid
+// ErrorsRef:
+//   - facade.CreateOrganization
+func (c *Organization) CreateOrganization(ctx context.Context, input dto.CreateOrganizationInput) (*dto.Organization, error) {
+	currAccount, err := facade.GetAccountByAccessToken(ctx)
+	if err != nil {
+		return nil, fault.ErrUnauthenticated
+	}
+
+	if err := validate.
+		GetValidateInstance().
+		Struct(input); err != nil {
+		return nil, fault.UserInputErrorFrom(err)
+	}
+
+	if org, err := facade.CreateOrganization(ctx, currAccount, input.Name, input.Address); err != nil {
+		return nil, err
+	} else {
+		return dto.OrganizationFrom(org.GetEntity()), nil
+	}
+}
+
+// AddMember
+//
+// Errors:
+//   - fault.UserInputError if the provided input is invalid
+// ErrorsRef:
+//   - controller.Organization.getAuthorizedOrganizationByNode
+//   - controller.getAccountByNode
+func (c *Organization) AddMember(ctx context.Context, input dto.OrganizationMemberInput) (*dto.OrganizationMember, error) {
+	if err := validate.
+		GetValidateInstance().
+		Struct(input); err != nil {
+		return nil, fault.UserInputErrorFrom(err)
+	}
+
+	_, org, err := c.getAuthorizedOrganizationByNode(ctx, input.OrganizationID, "admin")
+	if err != nil {
+		return nil, err
+	}
+
+	account, err := getAccountByNode(ctx, input.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	if member, err := org.AddMember(account, input.Role); err != nil {
+		return nil, memberErrorFrom(err)
+	} else {
+		return dto.OrganizationMemberFrom(member.Account.GetUser(), member.Role), nil
+	}
+}
+
+// UpdateMemberRole
+//
+// Errors:
+//   - fault.UserInputError if the provided input is invalid
+// ErrorsRef:
+//   - controller.Organization.getAuthorizedOrganizationByNode
+//   - controller.getAccountByNode
+//   - facade.Organization.UpdateMemberRole
+func (c *Organization) UpdateMemberRole(ctx context.Context, input dto.OrganizationMemberInput) (*dto.OrganizationMember, error) {
+	if err := validate.
+		GetValidateInstance().
+		Struct(input); err != nil {
+		return nil, fault.UserInputErrorFrom(err)
+	}
+
+	_, org, err := c.getAuthorizedOrganizationByNode(ctx, input.OrganizationID, "admin")
+	if err != nil {
+		return nil, err
+	}
+
+	account, err := getAccountByNode(ctx, input.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	if member, err := org.UpdateMemberRole(account, input.Role); err != nil {
+		return nil, memberErrorFrom(err)
+	} else {
+		return dto.OrganizationMemberFrom(member.Account.GetUser(), member.Role), nil
+	}
+}
+
+// RemoveMember Removes a member from the organization, which the members may
+// do to themselves as well.
+//
+// Errors:
+//   - fault.UserInputError if the provided input is invalid
+// ErrorsRef:
+//   - controller.Organization.getAuthorizedOrganizationByNode
+//   - controller.getAccountByNode
+//   - facade.Organization.RemoveMember
+func (c *Organization) RemoveMember(ctx context.Context, input dto.RemoveOrganizationMemberInput) (*dto.OrganizationMember, error) {
+	if err := validate.
+		GetValidateInstance().
+		Struct(input); err != nil {
+		return nil, fault.UserInputErrorFrom(err)
+	}
+
+	account, err := getAccountByNode(ctx, input.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	act := "admin"
+	if currAccount, err := facade.GetAccountByAccessToken(ctx); err == nil &&
+		currAccount.GetUser().DomainID == account.GetUser().DomainID {
+		act = "read"
+	}
+
+	_, org, err := c.getAuthorizedOrganizationByNode(ctx, input.OrganizationID, act)
+	if err != nil {
+		return nil, err
+	}
+
+	if member, err := org.RemoveMember(account); err != nil {
+		return nil, memberErrorFrom(err)
+	} else {
+		return dto.OrganizationMemberFrom(member.Account.GetUser(), member.Role), nil
+	}
+}
+
+// OrganizationOpt
+var OrganizationOpt = fx.Provide(newOrganization)
+
+// newOrganization
+func newOrganization() *Organization {
+	return &Organization{}
+}
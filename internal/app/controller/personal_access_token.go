@@ -0,0 +1,125 @@
+/*
+ * Copyright 2021 Meraj Sahebdar
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/volatiletech/null/v8"
+	"bitban.io/server/internal/pkg/dto"
+	"bitban.io/server/internal/pkg/facade"
+	"bitban.io/server/internal/pkg/fault"
+	"bitban.io/server/internal/pkg/validate"
+)
+
+// accessTokenErrorFrom Maps the errors of personal access tokens to input errors.
+func accessTokenErrorFrom(err error) error {
+	switch err {
+	case facade.ErrInvalidAccessTokenScope:
+		ret := fault.UserInputErrorFrom(err)
+		ret.AddError("scopes", "oneof", err.Error())
+
+		return ret
+	case facade.ErrAccessTokenExpired:
+		ret := fault.UserInputErrorFrom(err)
+		ret.AddError("expiresAt", "future", err.Error())
+
+		return ret
+	}
+
+	return err
+}
+
+// GetAccessTokens
+//
+// Errors:
+//   - fault.ErrUnauthenticated, if the request is not authorized
+//   - fault.ErrForbidden, if the authorized user does not have access to the resource
+func (c *Account) GetAccessTokens(ctx context.Context, id int64) ([]*dto.PersonalAccessToken, error) {
+	if currAccount, err := facade.GetAccountByAccessToken(ctx); err != nil {
+		return nil, fault.ErrUnauthenticated
+	} else {
+		if err := currAccount.CheckPermission(
+			fmt.Sprintf("/users/%d/access-tokens", id),
+			"read",
+		); err != nil {
+			return nil, err
+		}
+	}
+
+	if account, err := facade.GetAccountByUserId(ctx, id); err != nil {
+		return nil, err
+	} else {
+		if tokens, err := account.GetPersonalAccessTokens(); err != nil {
+			return nil, err
+		} else {
+			return dto.PersonalAccessTokensFrom(tokens), nil
+		}
+	}
+}
+
+// CreateAccessToken Creates a personal access token of the current user,
+// returning the token itself only this once.
+//
+// Errors:
+//   - fault.ErrUnauthenticated, if the request is not authorized by a session
+//   - fault.UserInputError, if the provided input is invalid
+// ErrorsRef:
+//   - facade.Account.CreatePersonalAccessToken
+func (c *Account) CreateAccessToken(ctx context.Context, input dto.CreateAccessTokenInput) (*dto.CreatedAccessToken, error) {
+	if currAccount, err := facade.GetAccountBySessionToken(ctx); err != nil {
+		return nil, fault.ErrUnauthenticated
+	} else {
+		if err := validate.
+			GetValidateInstance().
+			Struct(input); err != nil {
+			return nil, fault.UserInputErrorFrom(err)
+		}
+
+		if pat, token, err := currAccount.CreatePersonalAccessToken(
+			input.Name,
+			input.Scopes,
+			null.TimeFromPtr(input.ExpiresAt),
+		); err != nil {
+			return nil, accessTokenErrorFrom(err)
+		} else {
+			return &dto.CreatedAccessToken{
+				Token:       token,
+				AccessToken: dto.PersonalAccessTokenFrom(pat),
+			}, nil
+		}
+	}
+}
+
+// RevokeAccessToken
+//
+// Errors:
+//   - fault.ErrUnauthenticated, if the request is not authorized by a session
+// ErrorsRef:
+//   - facade.Account.RevokePersonalAccessToken
+func (c *Account) RevokeAccessToken(ctx context.Context, id int64) (*dto.PersonalAccessToken, error) {
+	if currAccount, err := facade.GetAccountBySessionToken(ctx); err != nil {
+		return nil, fault.ErrUnauthenticated
+	} else {
+		if pat, err := currAccount.RevokePersonalAccessToken(id); err != nil {
+			return nil, err
+		} else {
+			return dto.PersonalAccessTokenFrom(pat), nil
+		}
+	}
+}
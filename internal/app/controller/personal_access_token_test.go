@@ -0,0 +1,86 @@
+/*
+ * Copyright 2021 Meraj Sahebdar
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+
+package controller
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/volatiletech/null/v8"
+	"bitban.io/server/internal/pkg/dto"
+	"bitban.io/server/internal/pkg/facade"
+	"bitban.io/server/internal/pkg/fault"
+)
+
+func TestAccessTokenSession(t *testing.T) {
+	ctx := context.Background()
+
+	account, err := facade.GetAccountByUserId(ctx, 1)
+	if err != nil {
+		t.Fatalf("failed to find the user fixture: %s", err.Error())
+	}
+
+	pat, token, err := account.CreatePersonalAccessToken("api", []string{facade.ScopeApi}, null.Time{})
+	if err != nil {
+		t.Fatalf("failed to create the personal access token: %s", err.Error())
+	}
+
+	session, err := account.CreateAccessToken()
+	if err != nil {
+		t.Fatalf("failed to create the access token: %s", err.Error())
+	}
+
+	c := &Account{}
+	input := dto.CreateAccessTokenInput{Name: "ci", Scopes: []string{facade.ScopeApi}}
+
+	serve := func(bearer string, fn func(ctx context.Context)) {
+		req := httptest.NewRequest(http.MethodPost, "/api", nil)
+		req.Header.Set(echo.HeaderAuthorization, "Bearer "+bearer)
+
+		serveTestContext(t, req, fn)
+	}
+
+	t.Run("personal access token", func(t *testing.T) {
+		serve(token, func(ctx context.Context) {
+			if _, err := c.CreateAccessToken(ctx, input); err != fault.ErrUnauthenticated {
+				t.Errorf("expected error: %v, got: %v", fault.ErrUnauthenticated, err)
+			}
+
+			if _, err := c.RevokeAccessToken(ctx, pat.ID); err != fault.ErrUnauthenticated {
+				t.Errorf("expected error: %v, got: %v", fault.ErrUnauthenticated, err)
+			}
+		})
+	})
+
+	t.Run("session", func(t *testing.T) {
+		serve(session, func(ctx context.Context) {
+			if created, err := c.CreateAccessToken(ctx, input); err != nil {
+				t.Errorf("got an unexpected error: %s", err.Error())
+			} else if created.Token == "" {
+				t.Errorf("expected the token to be returned")
+			}
+
+			if _, err := c.RevokeAccessToken(ctx, pat.ID); err != nil {
+				t.Errorf("got an unexpected error: %s", err.Error())
+			}
+		})
+	})
+}
@@ -0,0 +1,194 @@
+/*
+ * Copyright 2021 Meraj Sahebdar
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controller
+
+import (
+	"golang.org/x/net/context"
+	"bitban.io/server/internal/pkg/dto"
+	"bitban.io/server/internal/pkg/facade"
+	"bitban.io/server/internal/pkg/fault"
+	"bitban.io/server/internal/pkg/validate"
+)
+
+// protectedBranchConfigFrom Returns the protection rule of the input, resolving
+// the node identifiers of the allowed pushers.
+//
+// Errors:
+//   - fault.UserInputError if a pusher is not identified by a user node identifier
+func protectedBranchConfigFrom(pattern string, blockForcePush bool, blockDeletion bool, restrictPush bool, pusherIds []string) (*facade.ProtectedBranchConfig, error) {
+	config := &facade.ProtectedBranchConfig{
+		Pattern:        pattern,
+		BlockForcePush: blockForcePush,
+		BlockDeletion:  blockDeletion,
+		RestrictPush:   restrictPush,
+		PusherIDs:      make([]int64, 0, len(pusherIds)),
+	}
+
+	for _, pusherId := range pusherIds {
+		if nType, id, err := dto.FromNodeIdentifier(pusherId); err != nil || nType != dto.UserNodeType {
+			ret := fault.UserInputErrorFrom(fault.ErrResourceNotFound)
+			ret.AddError("pusherIds", "user", "every pusher must be a user identifier")
+
+			return nil, ret
+		} else {
+			config.PusherIDs = append(config.PusherIDs, id)
+		}
+	}
+
+	return config, nil
+}
+
+// protectedBranchErrorFrom Maps the errors of branch protection rules to input errors.
+func protectedBranchErrorFrom(err error) error {
+	switch err {
+	case facade.ErrInvalidBranchPattern, facade.ErrProtectedBranchExists:
+		ret := fault.UserInputErrorFrom(err)
+		ret.AddError("pattern", "pattern", err.Error())
+
+		return ret
+	}
+
+	return err
+}
+
+// getAuthorizedRepoByProtectedBranch Returns the repository of the protection
+// rule if the current user is allowed to administer it.
+//
+// Errors:
+//   - fault.ErrResourceNotFound if the identifier does not belong to a protection rule
+// ErrorsRef:
+//   - facade.GetRepoByProtectedBranchId
+//   - controller.Repo.getAuthorizedRepo
+func (c *Repo) getAuthorizedRepoByProtectedBranch(ctx context.Context, nIdentifier string) (int64, *facade.Repo, error) {
+	if nType, id, err := dto.FromNodeIdentifier(nIdentifier); err != nil || nType != dto.ProtectedBranchNodeType {
+		return 0, nil, fault.ErrResourceNotFound
+	} else {
+		if repo, err := facade.GetRepoByProtectedBranchId(ctx, id); err != nil {
+			return 0, nil, err
+		} else {
+			_, repo, err := c.getAuthorizedRepo(ctx, repo.GetID(), "admin")
+			return id, repo, err
+		}
+	}
+}
+
+// GetProtectedBranches
+//
+// ErrorsRef:
+//   - controller.Repo.getReadableRepo
+func (c *Repo) GetProtectedBranches(ctx context.Context, id int64) ([]*dto.ProtectedBranch, error) {
+	repo, err := c.getReadableRepo(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if protectedBranches, err := repo.GetProtectedBranches(); err != nil {
+		return nil, err
+	} else {
+		return dto.ProtectedBranchesFrom(protectedBranches), nil
+	}
+}
+
+// AddProtectedBranch
+//
+// Errors:
+//   - fault.UserInputError if the provided input is invalid or the pattern is already protected
+// ErrorsRef:
+//   - controller.Repo.getAuthorizedRepo
+func (c *Repo) AddProtectedBranch(ctx context.Context, input dto.AddProtectedBranchInput) (*dto.ProtectedBranch, error) {
+	if err := validate.
+		GetValidateInstance().
+		Struct(input); err != nil {
+		return nil, fault.UserInputErrorFrom(err)
+	}
+
+	_, repo, err := c.getAuthorizedRepoByNode(ctx, input.RepositoryID, "admin")
+	if err != nil {
+		return nil, err
+	}
+
+	config, err := protectedBranchConfigFrom(
+		input.Pattern,
+		input.BlockForcePush,
+		input.BlockDeletion,
+		input.RestrictPush,
+		input.PusherIds,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if protectedBranch, err := repo.AddProtectedBranch(config); err != nil {
+		return nil, protectedBranchErrorFrom(err)
+	} else {
+		return dto.ProtectedBranchFrom(protectedBranch), nil
+	}
+}
+
+// UpdateProtectedBranch
+//
+// Errors:
+//   - fault.UserInputError if the provided input is invalid or the pattern is already protected
+// ErrorsRef:
+//   - controller.Repo.getAuthorizedRepoByProtectedBranch
+func (c *Repo) UpdateProtectedBranch(ctx context.Context, input dto.UpdateProtectedBranchInput) (*dto.ProtectedBranch, error) {
+	if err := validate.
+		GetValidateInstance().
+		Struct(input); err != nil {
+		return nil, fault.UserInputErrorFrom(err)
+	}
+
+	id, repo, err := c.getAuthorizedRepoByProtectedBranch(ctx, input.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	config, err := protectedBranchConfigFrom(
+		input.Pattern,
+		input.BlockForcePush,
+		input.BlockDeletion,
+		input.RestrictPush,
+		input.PusherIds,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if protectedBranch, err := repo.UpdateProtectedBranch(id, config); err != nil {
+		return nil, protectedBranchErrorFrom(err)
+	} else {
+		return dto.ProtectedBranchFrom(protectedBranch), nil
+	}
+}
+
+// RemoveProtectedBranch
+//
+// ErrorsRef:
+//   - controller.Repo.getAuthorizedRepoByProtectedBranch
+//   - facade.Repo.RemoveProtectedBranch
+func (c *Repo) RemoveProtectedBranch(ctx context.Context, nIdentifier string) (*dto.ProtectedBranch, error) {
+	id, repo, err := c.getAuthorizedRepoByProtectedBranch(ctx, nIdentifier)
+	if err != nil {
+		return nil, err
+	}
+
+	if protectedBranch, err := repo.RemoveProtectedBranch(id); err != nil {
+		return nil, err
+	} else {
+		return dto.ProtectedBranchFrom(protectedBranch), nil
+	}
+}
@@ -0,0 +1,152 @@
+/*
+ * Copyright 2021 Meraj Sahebdar
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	"bitban.io/server/internal/pkg/dto"
+	"bitban.io/server/internal/pkg/facade"
+	"bitban.io/server/internal/pkg/fault"
+	"bitban.io/server/internal/pkg/orm/entity"
+	"bitban.io/server/internal/pkg/validate"
+)
+
+// activityConfigFrom Validates the input and returns the activity config
+// fetching one more event to find out whether there is a next page.
+//
+// Errors:
+//   - fault.UserInputError, if the provided input is invalid
+func activityConfigFrom(input dto.ActivityInput) (*facade.ActivityConfig, error) {
+	if err := validate.
+		GetValidateInstance().
+		Struct(input); err != nil {
+		return nil, fault.UserInputErrorFrom(err)
+	}
+
+	offset := 0
+	if input.After != nil {
+		if after, err := dto.FromCursor(*input.After); err != nil {
+			return nil, fault.ErrUserInput
+		} else {
+			offset = after + 1
+		}
+	}
+
+	return &facade.ActivityConfig{
+		Ref:        input.Ref,
+		ForcedOnly: input.ForcedOnly,
+		Skip:       offset,
+		Limit:      input.First + 1,
+	}, nil
+}
+
+// pushEventConnectionFrom
+func pushEventConnectionFrom(pushEvents []*entity.PushEvent, config *facade.ActivityConfig) *dto.PushEventConnection {
+	first := config.Limit - 1
+
+	conn := &dto.PushEventConnection{
+		Edges: []*dto.PushEventEdge{},
+		PageInfo: &dto.PageInfo{
+			HasNextPage:     len(pushEvents) > first,
+			HasPreviousPage: config.Skip > 0,
+		},
+	}
+
+	for i, pushEvent := range pushEvents {
+		if i == first {
+			break
+		}
+
+		conn.Edges = append(conn.Edges, &dto.PushEventEdge{
+			Cursor: dto.ToCursor(config.Skip + i),
+			Node:   dto.PushEventFrom(pushEvent),
+		})
+	}
+
+	if len(conn.Edges) > 0 {
+		conn.PageInfo.StartCursor = &conn.Edges[0].Cursor
+		conn.PageInfo.EndCursor = &conn.Edges[len(conn.Edges)-1].Cursor
+	}
+
+	return conn
+}
+
+// GetActivity
+//
+// ErrorsRef:
+//   - controller.activityConfigFrom
+//   - controller.Repo.getReadableRepo
+func (c *Repo) GetActivity(ctx context.Context, id int64, input dto.ActivityInput) (*dto.PushEventConnection, error) {
+	config, err := activityConfigFrom(input)
+	if err != nil {
+		return nil, err
+	}
+
+	repo, err := c.getReadableRepo(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if pushEvents, err := repo.GetActivity(config); err != nil {
+		return nil, err
+	} else {
+		return pushEventConnectionFrom(pushEvents, config), nil
+	}
+}
+
+// GetActivity
+//
+// Errors:
+//   - fault.ErrUnauthenticated, if the request is not authorized
+//   - fault.ErrForbidden, if the authorized user does not have access to the resource
+// ErrorsRef:
+//   - controller.activityConfigFrom
+func (c *Account) GetActivity(ctx context.Context, id int64, input dto.ActivityInput) (*dto.PushEventConnection, error) {
+	config, err := activityConfigFrom(input)
+	if err != nil {
+		return nil, err
+	}
+
+	//
+	// Check Permission
+
+	if currAccount, err := facade.GetAccountByAccessToken(ctx); err != nil {
+		return nil, fault.ErrUnauthenticated
+	} else {
+		if err := currAccount.CheckPermission(
+			fmt.Sprintf("/users/%d/activity", id),
+			"read",
+		); err != nil {
+			return nil, err
+		}
+	}
+
+	//
+	// Retrieve the Events
+
+	if account, err := facade.GetAccountByUserId(ctx, id); err != nil {
+		return nil, err
+	} else {
+		if pushEvents, err := account.GetActivity(config); err != nil {
+			return nil, err
+		} else {
+			return pushEventConnectionFrom(pushEvents, config), nil
+		}
+	}
+}
@@ -0,0 +1,138 @@
+/*
+ * Copyright 2021 Meraj Sahebdar
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controller
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"golang.org/x/net/context"
+	"bitban.io/server/internal/pkg/dto"
+	"bitban.io/server/internal/pkg/facade"
+	"bitban.io/server/internal/pkg/fault"
+	"bitban.io/server/internal/pkg/validate"
+)
+
+// redirectByHttp Redirects a request made to a former address of the
+// repository to its current one, which git clients follow for the whole session.
+func redirectByHttp(ec echo.Context, repo *facade.Repo) error {
+	req := ec.Request()
+
+	repoAddress := repo.GetEntity().Address
+	if strings.HasSuffix(ec.Param("repo"), ".git") {
+		repoAddress += ".git"
+	}
+
+	location := *req.URL
+	location.Path = fmt.Sprintf("/%s/%s", repo.GetDomainAddress(), repoAddress) + strings.TrimPrefix(
+		req.URL.Path,
+		fmt.Sprintf("/%s/%s", ec.Param("domain"), ec.Param("repo")),
+	)
+	location.RawPath = ""
+
+	return ec.Redirect(http.StatusMovedPermanently, location.String())
+}
+
+// moveErrorFrom Maps the errors of placing repositories at an address, by
+// creating, forking or moving them, to input errors.
+func moveErrorFrom(err error, namespace string) error {
+	switch err {
+	case facade.ErrRepositoryAddressTaken:
+		ret := fault.UserInputErrorFrom(err)
+		ret.AddError(namespace, "unique", err.Error())
+
+		return ret
+	case facade.ErrInvalidRepositoryAddress:
+		ret := fault.UserInputErrorFrom(err)
+		ret.AddError(namespace, "address", err.Error())
+
+		return ret
+	case facade.ErrRepositoryGrantedToTeams:
+		ret := fault.UserInputErrorFrom(err)
+		ret.AddError(namespace, "teams", err.Error())
+
+		return ret
+	}
+
+	return err
+}
+
+// RenameRepository
+//
+// Errors:
+//   - fault.UserInputError if the provided input is invalid
+// ErrorsRef:
+//   - controller.Repo.getAuthorizedRepoByNode
+//   - facade.Repo.Rename
+func (c *Repo) RenameRepository(ctx context.Context, input dto.RenameRepositoryInput) (*dto.Repository, error) {
+	if err := validate.
+		GetValidateInstance().
+		Struct(input); err != nil {
+		return nil, fault.UserInputErrorFrom(err)
+	}
+
+	_, repo, err := c.getAuthorizedRepoByNode(ctx, input.ID, "admin")
+	if err != nil {
+		return nil, err
+	}
+
+	if err := repo.Rename(input.Address); err != nil {
+		return nil, moveErrorFrom(err, "address")
+	} else {
+		return dto.RepositoryFrom(repo.GetEntity()), nil
+	}
+}
+
+// TransferRepository Moves the repository to another domain administered by
+// the current user.
+//
+// Errors:
+//   - fault.UserInputError if the provided input is invalid
+//   - fault.ErrResourceNotFound if there is no such domain
+//   - fault.ErrForbidden if the current user does not administer the domain
+// ErrorsRef:
+//   - controller.Repo.getAuthorizedRepoByNode
+//   - facade.Repo.Transfer
+func (c *Repo) TransferRepository(ctx context.Context, input dto.TransferRepositoryInput) (*dto.Repository, error) {
+	if err := validate.
+		GetValidateInstance().
+		Struct(input); err != nil {
+		return nil, fault.UserInputErrorFrom(err)
+	}
+
+	currAccount, repo, err := c.getAuthorizedRepoByNode(ctx, input.ID, "admin")
+	if err != nil {
+		return nil, err
+	}
+
+	domain, err := facade.GetDomainByAddress(ctx, input.Domain)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := currAccount.CheckDomainPermission(domain, "admin"); err != nil {
+		return nil, err
+	}
+
+	if err := repo.Transfer(domain); err != nil {
+		return nil, moveErrorFrom(err, "domain")
+	} else {
+		return dto.RepositoryFrom(repo.GetEntity()), nil
+	}
+}
@@ -17,9 +17,11 @@
 package controller
 
 import (
+	"encoding/base64"
 	"fmt"
 	"io"
 	"io/ioutil"
+	"net"
 	"net/http"
 	"strings"
 
@@ -28,6 +30,7 @@ import (
 	"go.uber.org/zap"
 	"golang.org/x/net/context"
 	"bitban.io/server/internal/cfg"
+	"bitban.io/server/internal/pkg/auth"
 	"bitban.io/server/internal/pkg/dto"
 	"bitban.io/server/internal/pkg/facade"
 	"bitban.io/server/internal/pkg/fault"
@@ -40,36 +43,47 @@ import (
 // Repo
 type Repo struct{}
 
-// hasAccessByHttp
-func (c *Repo) hasAccessByHttp(ctx context.Context, domain string, repo int64) bool {
+// gitActionOf Returns the permission needed to serve a git service.
+func gitActionOf(service string) string {
+	if service == facade.GitReceivePack {
+		return "write"
+	}
+
+	return "read"
+}
+
+// authorizeByHttp Returns the account authenticated by the basic auth of the
+// request, or nil if it is anonymous, if it is allowed to perform the action
+// on the repository. The password may be a personal access token as well, in
+// which case the username is ignored.
+func (c *Repo) authorizeByHttp(ctx context.Context, repo *facade.Repo, act string) (*facade.Account, error) {
 	ec := util.MustGetEchoContext(ctx)
 	req := ec.Request()
 	res := ec.Response()
 
-	authHeader := req.Header.Get(echo.HeaderAuthorization)
-	if authHeader == "" {
-		res.Header().Set(echo.HeaderWWWAuthenticate, `Basic realm="Restricted"`)
-		return false
-	}
-
-	if identifier, password, ok := req.BasicAuth(); ok {
-		if account, err := facade.GetAccountByPassword(ctx, dto.SignInInput{
+	var account *facade.Account
+	if identifier, password, ok := req.BasicAuth(); ok && auth.IsPersonalAccessToken(password) {
+		var err error
+		if account, err = facade.GetAccountByPersonalAccessToken(ctx, password, facade.RepoScopeOf(act)); fault.IsForbiddenError(err) {
+			return nil, echo.NewHTTPError(http.StatusForbidden)
+		}
+	} else if ok {
+		account, _ = facade.GetAccountByPassword(ctx, dto.SignInInput{
 			Identifier: identifier,
 			Password:   password,
-		}); err != nil {
-			return false
-		} else {
-			if err := account.CheckPermissionIn(
-				domain,
-				fmt.Sprintf("/repositories/%d", repo),
-				"git-serve",
-			); err != nil {
-				return false
-			}
+		})
+	}
+
+	if err := facade.CheckRepoPermission(account, repo, act); err != nil {
+		if account == nil {
+			res.Header().Set(echo.HeaderWWWAuthenticate, `Basic realm="Restricted"`)
+			return nil, echo.NewHTTPError(http.StatusUnauthorized)
 		}
+
+		return nil, echo.NewHTTPError(http.StatusForbidden)
 	}
 
-	return true
+	return account, nil
 }
 
 // InfoRefs
@@ -83,24 +97,122 @@ func (c *Repo) InfoRefs(ctx context.Context) error {
 
 	var err error
 	var repo *facade.Repo
+	var redirected bool
 
-	if repo, err = facade.GetRepoByAddress(
+	if repo, redirected, err = facade.ResolveRepoByAddress(
 		req.Context(),
 		domainAddress,
 		repoAddress,
 	); err != nil {
 		return echo.NewHTTPError(http.StatusNotFound)
+	} else if redirected {
+		return redirectByHttp(ec, repo)
 	}
 
-	if !c.hasAccessByHttp(ctx, domainAddress, repo.GetID()) {
-		return echo.NewHTTPError(http.StatusForbidden)
+	if _, err := c.authorizeByHttp(ctx, repo, gitActionOf(ec.QueryParam("service"))); err != nil {
+		return err
 	}
 
 	res.Header().Set("Content-Type", fmt.Sprintf("application/x-%s-advertisement", ec.QueryParam("service")))
 	res.Header().Set("Cache-Control", "no-cache")
 	res.WriteHeader(200)
 
-	repo.AdvertiseRefs(res.Writer, ec.QueryParam("service"))
+	repo.AdvertiseRefs(
+		res.Writer,
+		ec.QueryParam("service"),
+		req.Header.Get("Git-Protocol"),
+	)
+
+	return nil
+}
+
+// archiveFormats Maps the supported download extensions to archive formats.
+var archiveFormats = map[string]string{
+	".tar.gz": facade.ArchiveTarGz,
+	".zip":    facade.ArchiveZip,
+}
+
+// archiveNameOf Returns the name of the archive of the ref, keeping just the
+// characters that are safe in both the file name and the prefix of entries.
+func archiveNameOf(repoAddress string, ref string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			return r
+		case r == '.', r == '_', r == '-':
+			return r
+		}
+
+		return '-'
+	}, fmt.Sprintf("%s-%s", repoAddress, ref))
+}
+
+// Archive Streams an archive of a branch, tag or commit.
+func (c *Repo) Archive(ctx context.Context) error {
+	ec := util.MustGetEchoContext(ctx)
+	req := ec.Request()
+	res := ec.Response()
+
+	domainAddress := ec.Param("domain")
+	repoAddress := ec.Param("repo")
+
+	var ref string
+	var ext string
+	var format string
+
+	for e, f := range archiveFormats {
+		if name := ec.Param("*"); strings.HasSuffix(name, e) {
+			ref = strings.TrimSuffix(name, e)
+			ext = e
+			format = f
+		}
+	}
+
+	if ref == "" {
+		return echo.NewHTTPError(http.StatusNotFound)
+	}
+
+	var err error
+	var repo *facade.Repo
+	var redirected bool
+
+	if repo, redirected, err = facade.ResolveRepoByAddress(
+		req.Context(),
+		domainAddress,
+		repoAddress,
+	); err != nil {
+		return echo.NewHTTPError(http.StatusNotFound)
+	} else if redirected {
+		return redirectByHttp(ec, repo)
+	}
+
+	if _, err := c.authorizeByHttp(ctx, repo, "read"); err != nil {
+		return err
+	}
+
+	name := archiveNameOf(repoAddress, ref)
+	archiveConfig := &facade.ArchiveConfig{
+		Format:  format,
+		Prefix:  name + "/",
+		Treeish: ref,
+	}
+
+	// Validate before writing the headers, so a missing ref is still a 404.
+	if err := repo.CheckArchive(archiveConfig); err != nil {
+		return echo.NewHTTPError(http.StatusNotFound)
+	}
+
+	if format == facade.ArchiveZip {
+		res.Header().Set(echo.HeaderContentType, "application/zip")
+	} else {
+		res.Header().Set(echo.HeaderContentType, "application/gzip")
+	}
+	res.Header().Set(echo.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="%s%s"`, name, ext))
+	res.WriteHeader(200)
+
+	if err := repo.WriteArchive(res.Writer, archiveConfig); err != nil {
+		cfg.Log.Error("got an error on writing an archive", zap.Error(err))
+	}
 
 	return nil
 }
@@ -108,12 +220,15 @@ func (c *Repo) InfoRefs(ctx context.Context) error {
 // ServePack
 func (c *Repo) ServePack(ctx context.Context) error {
 	var service string
+	var protocol string
 	var domainAddress string
 	var repoAddress string
 	var isSsh bool
+	var remoteAddr string
 
 	var r io.Reader
 	var w io.Writer
+	var res *echo.Response
 
 	if ec, err := util.GetEchoContext(ctx); err != nil {
 		if ch, err := ssh.GetContextCh(ctx); err != nil {
@@ -122,59 +237,88 @@ func (c *Repo) ServePack(ctx context.Context) error {
 			cmd := ssh.GetContextCmd(ctx)
 
 			service = cmd.Name
+			protocol = ssh.GetContextEnv(ctx, "GIT_PROTOCOL")
 
 			paths := strings.SplitN(cmd.Args, "/", 2)
+			if len(paths) != 2 {
+				return fault.ErrResourceNotFound
+			}
+
 			domainAddress = paths[0]
 			repoAddress = paths[1]
 
 			isSsh = true
 
+			if host, _, err := net.SplitHostPort(ssh.GetContextConn(ctx).RemoteAddr().String()); err == nil {
+				remoteAddr = host
+			}
+
 			r = ioutil.NopCloser(ch)
 			w = ch
 		}
 	} else {
 		service = ec.Param("service")
+		protocol = ec.Request().Header.Get("Git-Protocol")
 		domainAddress = ec.Param("domain")
 		repoAddress = ec.Param("repo")
 		isSsh = false
+		remoteAddr = ec.RealIP()
 
 		req := ec.Request()
-		res := ec.Response()
+		res = ec.Response()
 
 		r = req.Body
 		w = res.Writer
-
-		res.Header().Set("Content-Type", fmt.Sprintf("application/x-%s-result", service))
-		res.Header().Set("Cache-Control", "no-cache")
-		res.WriteHeader(200)
 	}
 
 	var err error
 	var repo *facade.Repo
 
-	if repo, err = facade.GetRepoByAddress(
+	// Former addresses keep resolving, so the existing clones keep working.
+	if repo, _, err = facade.ResolveRepoByAddress(
 		ctx,
 		domainAddress,
 		strings.TrimSuffix(repoAddress, ".git"),
 	); err != nil {
+		if res != nil {
+			return echo.NewHTTPError(http.StatusNotFound)
+		}
+
 		// TODO:
 		cfg.Log.Error("failed to initiate a repo facade", zap.Error(err))
 		return nil
 	}
 
+	var pusher *facade.Account
+
 	if isSsh {
-		// TODO: implement ssh-key auth
+		if account, err := ssh.GetContextAccount(ctx); err == nil {
+			pusher = account
+		}
+
+		if err := facade.CheckRepoPermission(pusher, repo, gitActionOf(service)); err != nil {
+			return err
+		}
 	} else {
-		if !c.hasAccessByHttp(ctx, domainAddress, repo.GetID()) {
-			return echo.NewHTTPError(http.StatusForbidden)
+		if account, err := c.authorizeByHttp(ctx, repo, gitActionOf(service)); err != nil {
+			return err
+		} else {
+			pusher = account
 		}
+
+		res.Header().Set("Content-Type", fmt.Sprintf("application/x-%s-result", service))
+		res.Header().Set("Cache-Control", "no-cache")
+		res.WriteHeader(200)
 	}
 
 	if err := repo.ServePack(&facade.ServerPackConfig{
-		R:       r,
-		W:       w,
-		Service: service,
-		IsSsh:   isSsh,
+		R:          r,
+		W:          w,
+		Service:    service,
+		Protocol:   protocol,
+		IsSsh:      isSsh,
+		Pusher:     pusher,
+		RemoteAddr: remoteAddr,
 	}); err != nil {
 		// TODO: what is the best way to handle this error?
 		cfg.Log.Error("got an error on precessing git request", zap.Error(err))
@@ -183,11 +327,14 @@ func (c *Repo) ServePack(ctx context.Context) error {
 	return nil
 }
 
-// CreateRepository
+// CreateRepository Creates a repository within the domain of the owner, which
+// defaults to the current user's own domain.
 //
 // Errors:
 //   - fault.ErrUnauthenticated if the request is not authorized
 //   - fault.UserInputError if the provided input is invalid
+//   - fault.ErrResourceNotFound if there is no such owner domain
+//   - fault.ErrForbidden if the current user does not administer the owner domain
 // ErrorsRef:
 //   - facade.CreateRepoByAddress
 func (c *Repo) CreateRepository(ctx context.Context, input dto.CreateRepositoryInput) (*entity.Repository, error) {
@@ -200,14 +347,558 @@ func (c *Repo) CreateRepository(ctx context.Context, input dto.CreateRepositoryI
 			return nil, fault.UserInputErrorFrom(err)
 		}
 
-		if repo, err := facade.CreateRepoByAddress(ctx, currAccount.GetDomain().Address, input.Address); err != nil {
-			return nil, err
+		domain := currAccount.GetDomain()
+		if input.Owner != nil {
+			if domain, err = facade.GetDomainByAddress(ctx, *input.Owner); err != nil {
+				return nil, err
+			}
+
+			if err := currAccount.CheckDomainPermission(domain, "admin"); err != nil {
+				return nil, err
+			}
+		}
+
+		domainAddress := domain.Address
+
+		if repo, err := facade.CreateRepoByAddress(ctx, domainAddress, input.Address); err != nil {
+			return nil, moveErrorFrom(err, "address")
 		} else {
+			if err := currAccount.GrantOwnershipIn(
+				domainAddress,
+				fmt.Sprintf("/repositories/%d", repo.GetID()),
+			); err != nil {
+				return nil, err
+			}
+
+			if input.Visibility != facade.VisibilityPrivate {
+				if err := repo.SetVisibility(input.Visibility); err != nil {
+					return nil, err
+				}
+			}
+
 			return repo.GetEntity(), nil
 		}
 	}
 }
 
+// UpdateRepositoryVisibility
+//
+// Errors:
+//   - fault.UserInputError if the provided input is invalid
+// ErrorsRef:
+//   - controller.Repo.getAuthorizedRepoByNode
+//   - facade.Repo.SetVisibility
+func (c *Repo) UpdateRepositoryVisibility(ctx context.Context, input dto.UpdateRepositoryVisibilityInput) (*dto.Repository, error) {
+	if err := validate.
+		GetValidateInstance().
+		Struct(input); err != nil {
+		return nil, fault.UserInputErrorFrom(err)
+	}
+
+	_, repo, err := c.getAuthorizedRepoByNode(ctx, input.ID, "admin")
+	if err != nil {
+		return nil, err
+	}
+
+	if err := repo.SetVisibility(input.Visibility); err != nil {
+		return nil, err
+	} else {
+		return dto.RepositoryFrom(repo.GetEntity()), nil
+	}
+}
+
+// getAuthorizedRepo Returns the repository if the current user is allowed to
+// perform the action on it. Anonymous users, for whom the returned account is
+// nil, may only read the public repositories.
+//
+// Errors:
+//   - fault.ErrUnauthenticated if the request is not authorized
+//   - fault.ErrForbidden if the authorized user does not have access to the repository
+// ErrorsRef:
+//   - facade.GetRepoById
+func (c *Repo) getAuthorizedRepo(ctx context.Context, id int64, act string) (*facade.Account, *facade.Repo, error) {
+	currAccount, err := facade.GetAccountByAccessToken(ctx)
+	if err != nil {
+		currAccount = nil
+	}
+
+	if repo, err := facade.GetRepoById(ctx, id); err != nil {
+		if currAccount == nil {
+			return nil, nil, fault.ErrUnauthenticated
+		}
+
+		return nil, nil, err
+	} else {
+		if err := facade.CheckRepoPermission(currAccount, repo, act); err != nil {
+			return nil, nil, err
+		}
+
+		return currAccount, repo, nil
+	}
+}
+
+// getAuthorizedRepoByNode Same as `getAuthorizedRepo` using a node identifier.
+//
+// Errors:
+//   - fault.ErrResourceNotFound if the identifier does not belong to a repository
+// ErrorsRef:
+//   - controller.Repo.getAuthorizedRepo
+func (c *Repo) getAuthorizedRepoByNode(ctx context.Context, nIdentifier string, act string) (*facade.Account, *facade.Repo, error) {
+	if nType, id, err := dto.FromNodeIdentifier(nIdentifier); err != nil || nType != dto.RepositoryNodeType {
+		return nil, nil, fault.ErrResourceNotFound
+	} else {
+		return c.getAuthorizedRepo(ctx, id, act)
+	}
+}
+
+// getReadableRepo
+//
+// ErrorsRef:
+//   - controller.Repo.getAuthorizedRepo
+func (c *Repo) getReadableRepo(ctx context.Context, id int64) (*facade.Repo, error) {
+	_, repo, err := c.getAuthorizedRepo(ctx, id, "read")
+	return repo, err
+}
+
+// gitErrorFrom Maps git errors of the facade to resource and input errors.
+func gitErrorFrom(err error) error {
+	switch err {
+	case facade.ErrRevisionNotFound, facade.ErrPathNotFound, facade.ErrRefNotFound:
+		return fault.ErrResourceNotFound
+	case facade.ErrInvalidRefName, facade.ErrRefAlreadyExists, facade.ErrDefaultBranch:
+		ret := fault.UserInputErrorFrom(err)
+		ret.AddError("name", "ref", err.Error())
+
+		return ret
+	case facade.ErrDeletionBlocked, facade.ErrForcePushBlocked, facade.ErrPushRestricted:
+		ret := fault.UserInputErrorFrom(err)
+		ret.AddError("name", "protected", err.Error())
+
+		return ret
+	case facade.ErrMirrorReadOnly:
+		return fault.ErrForbidden
+	case facade.ErrNoMergeBase:
+		ret := fault.UserInputErrorFrom(err)
+		ret.AddError("head", "mergebase", err.Error())
+
+		return ret
+	}
+
+	return err
+}
+
+// GetRepository
+//
+// ErrorsRef:
+//   - controller.Repo.getReadableRepo
+func (c *Repo) GetRepository(ctx context.Context, id int64) (*dto.Repository, error) {
+	if repo, err := c.getReadableRepo(ctx, id); err != nil {
+		return nil, err
+	} else {
+		return dto.RepositoryFrom(repo.GetEntity()), nil
+	}
+}
+
+// GetRepositoryByAddress Returns the repository by its current or a former address.
+//
+// ErrorsRef:
+//   - facade.ResolveRepoByAddress
+//   - controller.Repo.getReadableRepo
+func (c *Repo) GetRepositoryByAddress(ctx context.Context, domainAddress string, repoAddress string) (*dto.Repository, error) {
+	if repo, _, err := facade.ResolveRepoByAddress(ctx, domainAddress, repoAddress); err != nil {
+		return nil, err
+	} else {
+		return c.GetRepository(ctx, repo.GetID())
+	}
+}
+
+// GetTree
+//
+// Errors:
+//   - fault.ErrResourceNotFound if the revision or the path does not exist
+// ErrorsRef:
+//   - controller.Repo.getReadableRepo
+func (c *Repo) GetTree(ctx context.Context, id int64, rev string, path string) ([]*dto.TreeEntry, error) {
+	repo, err := c.getReadableRepo(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if entries, err := repo.GetTree(rev, path); err != nil {
+		return nil, gitErrorFrom(err)
+	} else {
+		ret := make([]*dto.TreeEntry, 0, len(entries))
+		for _, entry := range entries {
+			ret = append(ret, &dto.TreeEntry{
+				Name: entry.Name,
+				Path: entry.Path,
+				Mode: fmt.Sprintf("%06o", uint32(entry.Mode)),
+				Type: entry.Type,
+				Size: entry.Size,
+			})
+		}
+
+		return ret, nil
+	}
+}
+
+// GetBlob
+//
+// Errors:
+//   - fault.ErrResourceNotFound if the revision or the path does not exist
+// ErrorsRef:
+//   - controller.Repo.getReadableRepo
+func (c *Repo) GetBlob(ctx context.Context, id int64, rev string, path string) (*dto.Blob, error) {
+	repo, err := c.getReadableRepo(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if blob, err := repo.GetBlob(rev, path); err != nil {
+		return nil, gitErrorFrom(err)
+	} else {
+		ret := &dto.Blob{
+			Name:       blob.Name,
+			Path:       blob.Path,
+			Size:       blob.Size,
+			IsBinary:   blob.IsBinary,
+			IsTooLarge: blob.IsTooLarge,
+		}
+
+		if blob.IsBinary {
+			ret.Encoding = dto.BlobEncodingBase64
+			ret.Content = base64.StdEncoding.EncodeToString(blob.Content)
+		} else {
+			ret.Encoding = dto.BlobEncodingUtf8
+			ret.Content = string(blob.Content)
+		}
+
+		return ret, nil
+	}
+}
+
+// signatureFrom
+func signatureFrom(signature facade.Signature) *dto.Signature {
+	return &dto.Signature{
+		Name:  signature.Name,
+		Email: signature.Email,
+		Date:  signature.When,
+	}
+}
+
+// commitFrom
+func commitFrom(commit *facade.Commit) *dto.Commit {
+	return &dto.Commit{
+		Sha:       commit.Hash,
+		Author:    signatureFrom(commit.Author),
+		Committer: signatureFrom(commit.Committer),
+		Message:   commit.Message,
+		Parents:   commit.Parents,
+	}
+}
+
+// GetCommits
+//
+// Errors:
+//   - fault.UserInputError if the provided input is invalid
+//   - fault.ErrResourceNotFound if the revision does not exist
+// ErrorsRef:
+//   - controller.Repo.getReadableRepo
+func (c *Repo) GetCommits(ctx context.Context, id int64, input dto.CommitsInput) (*dto.CommitConnection, error) {
+	if err := validate.
+		GetValidateInstance().
+		Struct(input); err != nil {
+		return nil, fault.UserInputErrorFrom(err)
+	}
+
+	offset := 0
+	if input.After != nil {
+		if after, err := dto.FromCursor(*input.After); err != nil {
+			return nil, fault.ErrUserInput
+		} else {
+			offset = after + 1
+		}
+	}
+
+	repo, err := c.getReadableRepo(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Fetch one more commit to find out whether there is a next page.
+	commits, err := repo.Log(&facade.LogConfig{
+		Rev:    input.Ref,
+		Path:   input.Path,
+		Author: input.Author,
+		Since:  input.Since,
+		Until:  input.Until,
+		Skip:   offset,
+		Limit:  input.First + 1,
+	})
+	if err != nil {
+		return nil, gitErrorFrom(err)
+	}
+
+	conn := &dto.CommitConnection{
+		Edges: []*dto.CommitEdge{},
+		PageInfo: &dto.PageInfo{
+			HasNextPage:     len(commits) > input.First,
+			HasPreviousPage: offset > 0,
+		},
+	}
+
+	for i, commit := range commits {
+		if i == input.First {
+			break
+		}
+
+		conn.Edges = append(conn.Edges, &dto.CommitEdge{
+			Cursor: dto.ToCursor(offset + i),
+			Node:   commitFrom(commit),
+		})
+	}
+
+	if len(conn.Edges) > 0 {
+		conn.PageInfo.StartCursor = &conn.Edges[0].Cursor
+		conn.PageInfo.EndCursor = &conn.Edges[len(conn.Edges)-1].Cursor
+	}
+
+	return conn, nil
+}
+
+// fileDiffFrom
+func fileDiffFrom(d *facade.FileDiff) *dto.FileDiff {
+	ret := &dto.FileDiff{
+		Path:       d.Path,
+		Status:     d.Status,
+		Additions:  d.Additions,
+		Deletions:  d.Deletions,
+		IsBinary:   d.IsBinary,
+		IsTooLarge: d.IsTooLarge,
+	}
+
+	if d.OldPath != "" {
+		ret.OldPath = &d.OldPath
+	}
+
+	if d.Patch != "" {
+		ret.Patch = &d.Patch
+	}
+
+	return ret
+}
+
+// Compare
+//
+// Errors:
+//   - fault.ErrResourceNotFound if any of the revisions does not exist
+//   - fault.UserInputError if the revisions do not share any history
+// ErrorsRef:
+//   - controller.Repo.getReadableRepo
+func (c *Repo) Compare(ctx context.Context, id int64, base string, head string) (*dto.Comparison, error) {
+	repo, err := c.getReadableRepo(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	comparison, err := repo.Compare(base, head)
+	if err != nil {
+		return nil, gitErrorFrom(err)
+	}
+
+	ret := &dto.Comparison{
+		MergeBase:   comparison.MergeBase,
+		AheadBy:     comparison.Ahead,
+		BehindBy:    comparison.Behind,
+		Commits:     make([]*dto.Commit, 0, len(comparison.Commits)),
+		Files:       make([]*dto.FileDiff, 0, len(comparison.Files)),
+		IsTruncated: comparison.IsTruncated,
+	}
+
+	for _, commit := range comparison.Commits {
+		ret.Commits = append(ret.Commits, commitFrom(commit))
+	}
+
+	for _, d := range comparison.Files {
+		ret.Files = append(ret.Files, fileDiffFrom(d))
+	}
+
+	return ret, nil
+}
+
+// GetBlame
+//
+// Errors:
+//   - fault.ErrResourceNotFound if the revision or the path does not exist
+// ErrorsRef:
+//   - controller.Repo.getReadableRepo
+func (c *Repo) GetBlame(ctx context.Context, id int64, rev string, path string) ([]*dto.BlameRange, error) {
+	repo, err := c.getReadableRepo(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	ranges, err := repo.Blame(rev, path)
+	if err != nil {
+		return nil, gitErrorFrom(err)
+	}
+
+	ret := make([]*dto.BlameRange, 0, len(ranges))
+	for _, r := range ranges {
+		ret = append(ret, &dto.BlameRange{
+			StartLine: r.StartLine,
+			EndLine:   r.EndLine,
+			Commit:    commitFrom(r.Commit),
+		})
+	}
+
+	return ret, nil
+}
+
+// refsFrom
+func refsFrom(refs []*facade.Ref) []*dto.Ref {
+	ret := make([]*dto.Ref, 0, len(refs))
+	for _, ref := range refs {
+		ret = append(ret, dto.RefFrom(ref.Name, ref.Target))
+	}
+
+	return ret
+}
+
+// GetRefs
+//
+// ErrorsRef:
+//   - controller.Repo.getReadableRepo
+func (c *Repo) GetRefs(ctx context.Context, id int64, prefix string) ([]*dto.Ref, error) {
+	repo, err := c.getReadableRepo(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if refs, err := repo.GetRefs(prefix); err != nil {
+		return nil, err
+	} else {
+		return refsFrom(refs), nil
+	}
+}
+
+// CreateBranch
+//
+// Errors:
+//   - fault.UserInputError if the provided input is invalid, the branch exists or it is protected
+//   - fault.ErrResourceNotFound if the revision does not exist
+// ErrorsRef:
+//   - controller.Repo.getAuthorizedRepo
+func (c *Repo) CreateBranch(ctx context.Context, input dto.CreateBranchInput) (*dto.Ref, error) {
+	if err := validate.
+		GetValidateInstance().
+		Struct(input); err != nil {
+		return nil, fault.UserInputErrorFrom(err)
+	}
+
+	account, repo, err := c.getAuthorizedRepoByNode(ctx, input.RepositoryID, "write")
+	if err != nil {
+		return nil, err
+	}
+
+	if ref, err := repo.CreateBranch(input.Name, input.Ref, account.GetUser().DomainID); err != nil {
+		return nil, gitErrorFrom(err)
+	} else {
+		return dto.RefFrom(ref.Name, ref.Target), nil
+	}
+}
+
+// DeleteBranch
+//
+// Errors:
+//   - fault.UserInputError if the provided input is invalid, the branch is the default one or it is protected
+//   - fault.ErrResourceNotFound if the branch does not exist
+// ErrorsRef:
+//   - controller.Repo.getAuthorizedRepo
+func (c *Repo) DeleteBranch(ctx context.Context, input dto.DeleteBranchInput) (*dto.Ref, error) {
+	if err := validate.
+		GetValidateInstance().
+		Struct(input); err != nil {
+		return nil, fault.UserInputErrorFrom(err)
+	}
+
+	account, repo, err := c.getAuthorizedRepoByNode(ctx, input.RepositoryID, "write")
+	if err != nil {
+		return nil, err
+	}
+
+	if ref, err := repo.DeleteBranch(input.Name, account.GetUser().DomainID); err != nil {
+		return nil, gitErrorFrom(err)
+	} else {
+		return dto.RefFrom(ref.Name, ref.Target), nil
+	}
+}
+
+// CreateTag
+//
+// Errors:
+//   - fault.UserInputError if the provided input is invalid or the tag exists
+//   - fault.ErrResourceNotFound if the revision does not exist
+// ErrorsRef:
+//   - controller.Repo.getAuthorizedRepo
+func (c *Repo) CreateTag(ctx context.Context, input dto.CreateTagInput) (*dto.Ref, error) {
+	if err := validate.
+		GetValidateInstance().
+		Struct(input); err != nil {
+		return nil, fault.UserInputErrorFrom(err)
+	}
+
+	account, repo, err := c.getAuthorizedRepoByNode(ctx, input.RepositoryID, "write")
+	if err != nil {
+		return nil, err
+	}
+
+	tagConfig := &facade.TagConfig{
+		Name: input.Name,
+		Rev:  input.Ref,
+	}
+
+	if input.Message != nil && *input.Message != "" {
+		tagConfig.Message = *input.Message
+
+		if tagConfig.Tagger, err = account.GetSignature(); err != nil {
+			return nil, err
+		}
+	}
+
+	if ref, err := repo.CreateTag(tagConfig); err != nil {
+		return nil, gitErrorFrom(err)
+	} else {
+		return dto.RefFrom(ref.Name, ref.Target), nil
+	}
+}
+
+// DeleteTag
+//
+// Errors:
+//   - fault.UserInputError if the provided input is invalid
+//   - fault.ErrResourceNotFound if the tag does not exist
+// ErrorsRef:
+//   - controller.Repo.getAuthorizedRepo
+func (c *Repo) DeleteTag(ctx context.Context, input dto.DeleteTagInput) (*dto.Ref, error) {
+	if err := validate.
+		GetValidateInstance().
+		Struct(input); err != nil {
+		return nil, fault.UserInputErrorFrom(err)
+	}
+
+	_, repo, err := c.getAuthorizedRepoByNode(ctx, input.RepositoryID, "write")
+	if err != nil {
+		return nil, err
+	}
+
+	if ref, err := repo.DeleteTag(input.Name); err != nil {
+		return nil, gitErrorFrom(err)
+	} else {
+		return dto.RefFrom(ref.Name, ref.Target), nil
+	}
+}
+
 // RepoOpt
 var RepoOpt = fx.Provide(newRepo)
 
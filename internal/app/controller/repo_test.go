@@ -0,0 +1,333 @@
+/*
+ * Copyright 2021 Meraj Sahebdar
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controller
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-billy/v5/memfs"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/format/packfile"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/protocol/packp"
+	"github.com/go-git/go-git/v5/plumbing/protocol/packp/capability"
+	"github.com/go-git/go-git/v5/plumbing/revlist"
+	"github.com/go-git/go-git/v5/storage/memory"
+	"github.com/labstack/echo/v4"
+	"syreclabs.com/go/faker"
+	"bitban.io/server/internal/pkg/dto"
+	"bitban.io/server/internal/pkg/facade"
+	"bitban.io/server/internal/pkg/fault"
+	"bitban.io/server/internal/pkg/util"
+)
+
+// pushTestCommits Pushes a linear history to the branch of the repository,
+// committing the files of each item on top of the previous one.
+func pushTestCommits(t *testing.T, repo *facade.Repo, branch string, commits ...map[string]string) []plumbing.Hash {
+	t.Helper()
+
+	storage := memory.NewStorage()
+	local, err := git.Init(storage, memfs.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	worktree, err := local.Worktree()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var hashes []plumbing.Hash
+	for i, files := range commits {
+		for name, content := range files {
+			file, err := worktree.Filesystem.Create(name)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := file.Write([]byte(content)); err != nil {
+				t.Fatal(err)
+			}
+			file.Close()
+
+			if _, err := worktree.Add(name); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		hash, err := worktree.Commit(fmt.Sprintf("c%d", i), &git.CommitOptions{
+			Author: &object.Signature{
+				Name:  "Bitban",
+				Email: "bitban@bitban.io",
+				When:  time.Now().Add(time.Duration(i-len(commits)) * time.Minute),
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		hashes = append(hashes, hash)
+	}
+
+	head := hashes[len(hashes)-1]
+
+	objs, err := revlist.Objects(storage, []plumbing.Hash{head}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pack := &bytes.Buffer{}
+	if _, err := packfile.NewEncoder(pack, storage, false).Encode(objs, 10); err != nil {
+		t.Fatal(err)
+	}
+
+	req := packp.NewReferenceUpdateRequest()
+	req.Capabilities.Set(capability.ReportStatus)
+	req.Commands = []*packp.Command{{
+		Name: plumbing.NewBranchReferenceName(branch),
+		Old:  plumbing.ZeroHash,
+		New:  head,
+	}}
+	req.Packfile = io.NopCloser(pack)
+
+	body := &bytes.Buffer{}
+	if err := req.Encode(body); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := repo.ServePack(&facade.ServerPackConfig{
+		R:       body,
+		W:       io.Discard,
+		Service: facade.GitReceivePack,
+	}); err != nil {
+		t.Fatalf("failed to push the commits: %s", err.Error())
+	}
+
+	return hashes
+}
+
+// createPublicTestRepo Creates a public repository in the domain of the user
+// fixture, which is readable without authentication.
+func createPublicTestRepo(t *testing.T, ctx context.Context) *facade.Repo {
+	t.Helper()
+
+	account, err := facade.GetAccountByUserId(ctx, 1)
+	if err != nil {
+		t.Fatalf("failed to find the user fixture: %s", err.Error())
+	}
+
+	repo, err := facade.CreateRepoByAddress(ctx, account.GetUser().Domain.Address, faker.Internet().Slug())
+	if err != nil {
+		t.Fatalf("failed to create the repository: %s", err.Error())
+	}
+
+	if err := repo.SetVisibility(facade.VisibilityPublic); err != nil {
+		t.Fatalf("failed to publish the repository: %s", err.Error())
+	}
+
+	return repo
+}
+
+func TestArchiveNameOf(t *testing.T) {
+	for ref, expected := range map[string]string{
+		"main":            "repo-main",
+		"v1.0.0":          "repo-v1.0.0",
+		"feature/login":   "repo-feature-login",
+		`a"; filename="x`: "repo-a---filename--x",
+		"a\r\nb":          "repo-a--b",
+	} {
+		if got := archiveNameOf("repo", ref); got != expected {
+			t.Errorf("expected archiveNameOf(%q) to be %q, got: %q", ref, expected, got)
+		}
+	}
+}
+
+func TestArchive(t *testing.T) {
+	ctx := context.Background()
+
+	repo := createPublicTestRepo(t, ctx)
+	domainAddress := repo.GetDomainAddress()
+	repoAddress := repo.GetEntity().Address
+
+	pushTestCommits(t, repo, "main", map[string]string{"README.md": "hello\n"})
+
+	c := &Repo{}
+
+	ee := echo.New()
+	ee.Use(util.ContextWrapper())
+	ee.GET("/:domain/:repo/archive/*", func(ec echo.Context) error {
+		return c.Archive(ec.Request().Context())
+	})
+
+	get := func(t *testing.T, name string) *httptest.ResponseRecorder {
+		t.Helper()
+
+		rec := httptest.NewRecorder()
+		ee.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/"+domainAddress+"/"+repoAddress+"/archive/"+name, nil))
+
+		return rec
+	}
+
+	t.Run("tar.gz", func(t *testing.T) {
+		rec := get(t, "main.tar.gz")
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected status %d, got: %d", http.StatusOK, rec.Code)
+		}
+
+		if disposition := rec.Header().Get(echo.HeaderContentDisposition); disposition != `attachment; filename="`+repoAddress+`-main.tar.gz"` {
+			t.Errorf("unexpected content disposition: %s", disposition)
+		}
+
+		if body := rec.Body.Bytes(); len(body) < 2 || body[0] != 0x1f || body[1] != 0x8b {
+			t.Errorf("expected a gzip body")
+		}
+	})
+
+	t.Run("zip", func(t *testing.T) {
+		rec := get(t, "main.zip")
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected status %d, got: %d", http.StatusOK, rec.Code)
+		}
+
+		if contentType := rec.Header().Get(echo.HeaderContentType); contentType != "application/zip" {
+			t.Errorf("expected a zip content type, got: %s", contentType)
+		}
+
+		if body := rec.Body.String(); len(body) < 2 || body[:2] != "PK" {
+			t.Errorf("expected a zip body")
+		}
+	})
+
+	for _, name := range []string{"main.rar", "missing.zip", "-main.zip", "main:README.md.zip"} {
+		t.Run(name, func(t *testing.T) {
+			if rec := get(t, name); rec.Code != http.StatusNotFound {
+				t.Errorf("expected status %d, got: %d", http.StatusNotFound, rec.Code)
+			}
+		})
+	}
+
+	t.Run("private", func(t *testing.T) {
+		if err := repo.SetVisibility(facade.VisibilityPrivate); err != nil {
+			t.Fatalf("failed to hide the repository: %s", err.Error())
+		}
+
+		if rec := get(t, "main.zip"); rec.Code != http.StatusUnauthorized {
+			t.Errorf("expected status %d, got: %d", http.StatusUnauthorized, rec.Code)
+		}
+	})
+}
+
+func TestGetCommits(t *testing.T) {
+	ctx := context.Background()
+	repo := createPublicTestRepo(t, ctx)
+
+	pushTestCommits(t, repo, "main",
+		map[string]string{"a.txt": "0\n"},
+		map[string]string{"docs/b.md": "1\n"},
+		map[string]string{"a.txt": "2\n"},
+		map[string]string{"docs/b.md": "3\n"},
+		map[string]string{"a.txt": "4\n"},
+	)
+
+	c := &Repo{}
+
+	getCommits := func(t *testing.T, input dto.CommitsInput) (*dto.CommitConnection, error) {
+		t.Helper()
+
+		var conn *dto.CommitConnection
+		var err error
+		serveTestContext(t, httptest.NewRequest(http.MethodPost, "/api", nil), func(ctx context.Context) {
+			conn, err = c.GetCommits(ctx, repo.GetID(), input)
+		})
+
+		return conn, err
+	}
+
+	messagesOf := func(conn *dto.CommitConnection) string {
+		var messages []string
+		for _, edge := range conn.Edges {
+			messages = append(messages, edge.Node.Message)
+		}
+
+		return strings.Join(messages, " ")
+	}
+
+	t.Run("pages", func(t *testing.T) {
+		var after *string
+		for _, expected := range []struct {
+			messages string
+			hasNext  bool
+		}{
+			{"c4 c3", true},
+			{"c2 c1", true},
+			{"c0", false},
+		} {
+			conn, err := getCommits(t, dto.CommitsInput{Ref: "main", First: 2, After: after})
+			if err != nil {
+				t.Fatalf("got an unexpected error: %s", err.Error())
+			}
+
+			if got := messagesOf(conn); got != expected.messages {
+				t.Errorf("expected %q, got: %q", expected.messages, got)
+			}
+
+			if conn.PageInfo.HasNextPage != expected.hasNext || conn.PageInfo.HasPreviousPage != (after != nil) {
+				t.Errorf("unexpected page info for %q: %+v", expected.messages, conn.PageInfo)
+			}
+
+			after = conn.PageInfo.EndCursor
+		}
+	})
+
+	t.Run("path", func(t *testing.T) {
+		conn, err := getCommits(t, dto.CommitsInput{Ref: "main", Path: "docs", First: 1})
+		if err != nil {
+			t.Fatalf("got an unexpected error: %s", err.Error())
+		}
+
+		if got := messagesOf(conn); got != "c3" || !conn.PageInfo.HasNextPage {
+			t.Errorf("expected c3 with a next page, got: %q", got)
+		}
+
+		if conn, err = getCommits(t, dto.CommitsInput{Ref: "main", Path: "docs", First: 1, After: conn.PageInfo.EndCursor}); err != nil {
+			t.Fatalf("got an unexpected error: %s", err.Error())
+		} else if got := messagesOf(conn); got != "c1" || conn.PageInfo.HasNextPage {
+			t.Errorf("expected c1 as the last page, got: %q", got)
+		}
+	})
+
+	t.Run("invalid cursor", func(t *testing.T) {
+		after := "invalid"
+		if _, err := getCommits(t, dto.CommitsInput{Ref: "main", First: 2, After: &after}); !fault.IsUserInputError(err) {
+			t.Errorf("expected a user input error, got: %v", err)
+		}
+	})
+
+	t.Run("unknown revision", func(t *testing.T) {
+		if _, err := getCommits(t, dto.CommitsInput{Ref: "unknown", First: 2}); err != fault.ErrResourceNotFound {
+			t.Errorf("expected error: %v, got: %v", fault.ErrResourceNotFound, err)
+		}
+	})
+}
@@ -0,0 +1,386 @@
+/*
+ * Copyright 2021 Meraj Sahebdar
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controller
+
+import (
+	"context"
+
+	"bitban.io/server/internal/pkg/dto"
+	"bitban.io/server/internal/pkg/facade"
+	"bitban.io/server/internal/pkg/fault"
+	"bitban.io/server/internal/pkg/validate"
+)
+
+// teamErrorFrom Maps the errors of teams to input errors.
+func teamErrorFrom(err error) error {
+	switch err {
+	case facade.ErrTeamNameTaken:
+		ret := fault.UserInputErrorFrom(err)
+		ret.AddError("name", "unique", err.Error())
+
+		return ret
+	case facade.ErrNotOrganizationMember:
+		ret := fault.UserInputErrorFrom(err)
+		ret.AddError("userId", "member", err.Error())
+
+		return ret
+	case facade.ErrTeamMemberExists:
+		ret := fault.UserInputErrorFrom(err)
+		ret.AddError("userId", "unique", err.Error())
+
+		return ret
+	case facade.ErrRepositoryNotInOrganization:
+		ret := fault.UserInputErrorFrom(err)
+		ret.AddError("repositoryId", "organization", err.Error())
+
+		return ret
+	case facade.ErrInvalidCollaboratorRole:
+		ret := fault.UserInputErrorFrom(err)
+		ret.AddError("role", "oneof", err.Error())
+
+		return ret
+	}
+
+	return err
+}
+
+// getRepoByNode Returns the repository using its node identifier.
+//
+// Errors:
+//   - fault.ErrResourceNotFound if the identifier does not belong to a repository
+// ErrorsRef:
+//   - facade.GetRepoById
+func getRepoByNode(ctx context.Context, nIdentifier string) (*facade.Repo, error) {
+	if nType, id, err := dto.FromNodeIdentifier(nIdentifier); err != nil || nType != dto.RepositoryNodeType {
+		return nil, fault.ErrResourceNotFound
+	} else {
+		return facade.GetRepoById(ctx, id)
+	}
+}
+
+// getAuthorizedTeam Returns the team if the current user is allowed to
+// perform the action on its organization.
+//
+// Errors:
+//   - fault.ErrUnauthenticated if the request is not authorized
+//   - fault.ErrForbidden if the authorized user does not have access to the organization
+// ErrorsRef:
+//   - facade.GetTeamById
+func (c *Organization) getAuthorizedTeam(ctx context.Context, id int64, act string) (*facade.Team, error) {
+	currAccount, err := facade.GetAccountByAccessToken(ctx)
+	if err != nil {
+		return nil, fault.ErrUnauthenticated
+	}
+
+	if team, err := facade.GetTeamById(ctx, id); err != nil {
+		return nil, err
+	} else {
+		if err := currAccount.CheckDomainPermission(team.GetOrganization().GetDomain(), act); err != nil {
+			return nil, err
+		}
+
+		return team, nil
+	}
+}
+
+// getAuthorizedTeamByNode Same as `getAuthorizedTeam` using a node identifier.
+//
+// Errors:
+//   - fault.ErrResourceNotFound if the identifier does not belong to a team
+// ErrorsRef:
+//   - controller.Organization.getAuthorizedTeam
+func (c *Organization) getAuthorizedTeamByNode(ctx context.Context, nIdentifier string, act string) (*facade.Team, error) {
+	if nType, id, err := dto.FromNodeIdentifier(nIdentifier); err != nil || nType != dto.TeamNodeType {
+		return nil, fault.ErrResourceNotFound
+	} else {
+		return c.getAuthorizedTeam(ctx, id, act)
+	}
+}
+
+// GetTeams
+//
+// ErrorsRef:
+//   - controller.Organization.getAuthorizedOrganization
+func (c *Organization) GetTeams(ctx context.Context, id int64) ([]*dto.Team, error) {
+	_, org, err := c.getAuthorizedOrganization(ctx, id, "read")
+	if err != nil {
+		return nil, err
+	}
+
+	teams, err := org.GetTeams()
+	if err != nil {
+		return nil, err
+	}
+
+	ret := make([]*dto.Team, 0, len(teams))
+	for _, team := range teams {
+		ret = append(ret, dto.TeamFrom(team.GetEntity()))
+	}
+
+	return ret, nil
+}
+
+// GetTeam
+//
+// ErrorsRef:
+//   - controller.Organization.getAuthorizedTeam
+func (c *Organization) GetTeam(ctx context.Context, id int64) (*dto.Team, error) {
+	if team, err := c.getAuthorizedTeam(ctx, id, "read"); err != nil {
+		return nil, err
+	} else {
+		return dto.TeamFrom(team.GetEntity()), nil
+	}
+}
+
+// GetTeamMembers
+//
+// ErrorsRef:
+//   - controller.Organization.getAuthorizedTeam
+func (c *Organization) GetTeamMembers(ctx context.Context, id int64) ([]*dto.User, error) {
+	team, err := c.getAuthorizedTeam(ctx, id, "read")
+	if err != nil {
+		return nil, err
+	}
+
+	members, err := team.GetMembers()
+	if err != nil {
+		return nil, err
+	}
+
+	ret := make([]*dto.User, 0, len(members))
+	for _, member := range members {
+		ret = append(ret, dto.UserFrom(member.GetUser()))
+	}
+
+	return ret, nil
+}
+
+// GetTeamRepositories
+//
+// ErrorsRef:
+//   - controller.Organization.getAuthorizedTeam
+func (c *Organization) GetTeamRepositories(ctx context.Context, id int64) ([]*dto.TeamRepository, error) {
+	team, err := c.getAuthorizedTeam(ctx, id, "read")
+	if err != nil {
+		return nil, err
+	}
+
+	repos, err := team.GetRepositories()
+	if err != nil {
+		return nil, err
+	}
+
+	ret := make([]*dto.TeamRepository, 0, len(repos))
+	for _, repo := range repos {
+		ret = append(ret, dto.TeamRepositoryFrom(repo.Repo.GetEntity(), repo.Role))
+	}
+
+	return ret, nil
+}
+
+// CreateTeam
+//
+// Errors:
+//   - fault.UserInputError if the provided input is invalid
+// ErrorsRef:
+//   - controller.Organization.getAuthorizedOrganizationByNode
+func (c *Organization) CreateTeam(ctx context.Context, input dto.CreateTeamInput) (*dto.Team, error) {
+	if err := validate.
+		GetValidateInstance().
+		Struct(input); err != nil {
+		return nil, fault.UserInputErrorFrom(err)
+	}
+
+	_, org, err := c.getAuthorizedOrganizationByNode(ctx, input.OrganizationID, "admin")
+	if err != nil {
+		return nil, err
+	}
+
+	if team, err := org.CreateTeam(input.Name, input.Description); err != nil {
+		return nil, teamErrorFrom(err)
+	} else {
+		return dto.TeamFrom(team.GetEntity()), nil
+	}
+}
+
+// UpdateTeam
+//
+// Errors:
+//   - fault.UserInputError if the provided input is invalid
+// ErrorsRef:
+//   - controller.Organization.getAuthorizedTeamByNode
+func (c *Organization) UpdateTeam(ctx context.Context, input dto.UpdateTeamInput) (*dto.Team, error) {
+	if err := validate.
+		GetValidateInstance().
+		Struct(input); err != nil {
+		return nil, fault.UserInputErrorFrom(err)
+	}
+
+	team, err := c.getAuthorizedTeamByNode(ctx, input.TeamID, "admin")
+	if err != nil {
+		return nil, err
+	}
+
+	if err := team.Update(input.Name, input.Description); err != nil {
+		return nil, teamErrorFrom(err)
+	}
+
+	return dto.TeamFrom(team.GetEntity()), nil
+}
+
+// DeleteTeam
+//
+// ErrorsRef:
+//   - controller.Organization.getAuthorizedTeamByNode
+func (c *Organization) DeleteTeam(ctx context.Context, nIdentifier string) (*dto.Team, error) {
+	team, err := c.getAuthorizedTeamByNode(ctx, nIdentifier, "admin")
+	if err != nil {
+		return nil, err
+	}
+
+	if err := team.Delete(); err != nil {
+		return nil, err
+	}
+
+	return dto.TeamFrom(team.GetEntity()), nil
+}
+
+// AddTeamMember
+//
+// Errors:
+//   - fault.UserInputError if the provided input is invalid
+// ErrorsRef:
+//   - controller.Organization.getAuthorizedTeamByNode
+//   - controller.getAccountByNode
+func (c *Organization) AddTeamMember(ctx context.Context, input dto.TeamMemberInput) (*dto.User, error) {
+	if err := validate.
+		GetValidateInstance().
+		Struct(input); err != nil {
+		return nil, fault.UserInputErrorFrom(err)
+	}
+
+	team, err := c.getAuthorizedTeamByNode(ctx, input.TeamID, "admin")
+	if err != nil {
+		return nil, err
+	}
+
+	account, err := getAccountByNode(ctx, input.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := team.AddMember(account); err != nil {
+		return nil, teamErrorFrom(err)
+	}
+
+	return dto.UserFrom(account.GetUser()), nil
+}
+
+// RemoveTeamMember
+//
+// Errors:
+//   - fault.UserInputError if the provided input is invalid
+// ErrorsRef:
+//   - controller.Organization.getAuthorizedTeamByNode
+//   - controller.getAccountByNode
+//   - facade.Team.RemoveMember
+func (c *Organization) RemoveTeamMember(ctx context.Context, input dto.TeamMemberInput) (*dto.User, error) {
+	if err := validate.
+		GetValidateInstance().
+		Struct(input); err != nil {
+		return nil, fault.UserInputErrorFrom(err)
+	}
+
+	team, err := c.getAuthorizedTeamByNode(ctx, input.TeamID, "admin")
+	if err != nil {
+		return nil, err
+	}
+
+	account, err := getAccountByNode(ctx, input.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := team.RemoveMember(account); err != nil {
+		return nil, err
+	}
+
+	return dto.UserFrom(account.GetUser()), nil
+}
+
+// GrantTeamRepository
+//
+// Errors:
+//   - fault.UserInputError if the provided input is invalid
+// ErrorsRef:
+//   - controller.Organization.getAuthorizedTeamByNode
+//   - controller.getRepoByNode
+func (c *Organization) GrantTeamRepository(ctx context.Context, input dto.TeamRepositoryInput) (*dto.TeamRepository, error) {
+	if err := validate.
+		GetValidateInstance().
+		Struct(input); err != nil {
+		return nil, fault.UserInputErrorFrom(err)
+	}
+
+	team, err := c.getAuthorizedTeamByNode(ctx, input.TeamID, "admin")
+	if err != nil {
+		return nil, err
+	}
+
+	repo, err := getRepoByNode(ctx, input.RepositoryID)
+	if err != nil {
+		return nil, err
+	}
+
+	if granted, err := team.GrantRepository(repo, input.Role); err != nil {
+		return nil, teamErrorFrom(err)
+	} else {
+		return dto.TeamRepositoryFrom(granted.Repo.GetEntity(), granted.Role), nil
+	}
+}
+
+// RevokeTeamRepository
+//
+// Errors:
+//   - fault.UserInputError if the provided input is invalid
+// ErrorsRef:
+//   - controller.Organization.getAuthorizedTeamByNode
+//   - controller.getRepoByNode
+//   - facade.Team.RevokeRepository
+func (c *Organization) RevokeTeamRepository(ctx context.Context, input dto.RevokeTeamRepositoryInput) (*dto.TeamRepository, error) {
+	if err := validate.
+		GetValidateInstance().
+		Struct(input); err != nil {
+		return nil, fault.UserInputErrorFrom(err)
+	}
+
+	team, err := c.getAuthorizedTeamByNode(ctx, input.TeamID, "admin")
+	if err != nil {
+		return nil, err
+	}
+
+	repo, err := getRepoByNode(ctx, input.RepositoryID)
+	if err != nil {
+		return nil, err
+	}
+
+	if revoked, err := team.RevokeRepository(repo); err != nil {
+		return nil, err
+	} else {
+		return dto.TeamRepositoryFrom(revoked.Repo.GetEntity(), revoked.Role), nil
+	}
+}
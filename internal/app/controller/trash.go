@@ -0,0 +1,102 @@
+/*
+ * Copyright 2021 Meraj Sahebdar
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	"bitban.io/server/internal/pkg/dto"
+	"bitban.io/server/internal/pkg/facade"
+	"bitban.io/server/internal/pkg/fault"
+)
+
+// trashErrorFrom Maps the errors of restoring repositories to input errors.
+func trashErrorFrom(err error) error {
+	switch err {
+	case facade.ErrRetentionExpired:
+		ret := fault.UserInputErrorFrom(err)
+		ret.AddError("id", "retention", err.Error())
+
+		return ret
+	case facade.ErrRepositoryAddressTaken:
+		ret := fault.UserInputErrorFrom(err)
+		ret.AddError("id", "unique", err.Error())
+
+		return ret
+	}
+
+	return err
+}
+
+// DeleteRepository Moves the repository to trash.
+//
+// ErrorsRef:
+//   - controller.Repo.getAuthorizedRepoByNode
+//   - facade.Repo.Delete
+func (c *Repo) DeleteRepository(ctx context.Context, nIdentifier string) (*dto.Repository, error) {
+	_, repo, err := c.getAuthorizedRepoByNode(ctx, nIdentifier, "admin")
+	if err != nil {
+		return nil, err
+	}
+
+	if err := repo.Delete(); err != nil {
+		return nil, err
+	} else {
+		return dto.RepositoryFrom(repo.GetEntity()), nil
+	}
+}
+
+// RestoreRepository Restores the repository from trash within the retention window.
+//
+// Errors:
+//   - fault.ErrUnauthenticated if the request is not authorized
+//   - fault.ErrResourceNotFound if the identifier does not belong to a repository
+//   - fault.ErrForbidden if the authorized user does not have access to the repository
+//   - fault.UserInputError if the retention window is over or the address is taken
+// ErrorsRef:
+//   - facade.GetTrashedRepoDomainAddress
+//   - facade.RestoreRepoById
+func (c *Repo) RestoreRepository(ctx context.Context, nIdentifier string) (*dto.Repository, error) {
+	currAccount, err := facade.GetAccountByAccessToken(ctx)
+	if err != nil {
+		return nil, fault.ErrUnauthenticated
+	}
+
+	nType, id, err := dto.FromNodeIdentifier(nIdentifier)
+	if err != nil || nType != dto.RepositoryNodeType {
+		return nil, fault.ErrResourceNotFound
+	}
+
+	if domainAddress, err := facade.GetTrashedRepoDomainAddress(ctx, id); err != nil {
+		return nil, err
+	} else {
+		if err := currAccount.CheckPermissionIn(
+			domainAddress,
+			fmt.Sprintf("/repositories/%d", id),
+			"admin",
+		); err != nil {
+			return nil, err
+		}
+	}
+
+	if repo, err := facade.RestoreRepoById(ctx, id); err != nil {
+		return nil, trashErrorFrom(err)
+	} else {
+		return dto.RepositoryFrom(repo.GetEntity()), nil
+	}
+}
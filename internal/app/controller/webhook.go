@@ -0,0 +1,183 @@
+/*
+ * Copyright 2021 Meraj Sahebdar
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controller
+
+import (
+	"golang.org/x/net/context"
+	"bitban.io/server/internal/pkg/dto"
+	"bitban.io/server/internal/pkg/facade"
+	"bitban.io/server/internal/pkg/fault"
+	"bitban.io/server/internal/pkg/validate"
+)
+
+// webhookErrorFrom Maps the errors of webhooks to input errors.
+func webhookErrorFrom(err error) error {
+	switch err {
+	case facade.ErrInvalidWebhookURL:
+		ret := fault.UserInputErrorFrom(err)
+		ret.AddError("url", "url", err.Error())
+
+		return ret
+	case facade.ErrInvalidWebhookEvent:
+		ret := fault.UserInputErrorFrom(err)
+		ret.AddError("events", "oneof", err.Error())
+
+		return ret
+	}
+
+	return err
+}
+
+// getAuthorizedRepoByWebhook Returns the repository of the webhook if the
+// current user is allowed to administer it.
+//
+// Errors:
+//   - fault.ErrResourceNotFound if the identifier does not belong to a webhook
+// ErrorsRef:
+//   - facade.GetRepoByWebhookId
+//   - controller.Repo.getAuthorizedRepo
+func (c *Repo) getAuthorizedRepoByWebhook(ctx context.Context, nIdentifier string) (int64, *facade.Repo, error) {
+	if nType, id, err := dto.FromNodeIdentifier(nIdentifier); err != nil || nType != dto.WebhookNodeType {
+		return 0, nil, fault.ErrResourceNotFound
+	} else {
+		if repo, err := facade.GetRepoByWebhookId(ctx, id); err != nil {
+			return 0, nil, err
+		} else {
+			_, repo, err := c.getAuthorizedRepo(ctx, repo.GetID(), "admin")
+			return id, repo, err
+		}
+	}
+}
+
+// GetWebhooks
+//
+// ErrorsRef:
+//   - controller.Repo.getAuthorizedRepo
+func (c *Repo) GetWebhooks(ctx context.Context, id int64) ([]*dto.Webhook, error) {
+	_, repo, err := c.getAuthorizedRepo(ctx, id, "admin")
+	if err != nil {
+		return nil, err
+	}
+
+	if webhooks, err := repo.GetWebhooks(); err != nil {
+		return nil, err
+	} else {
+		return dto.WebhooksFrom(webhooks), nil
+	}
+}
+
+// GetWebhookDeliveries
+//
+// Errors:
+//   - fault.UserInputError if the provided input is invalid
+// ErrorsRef:
+//   - controller.Repo.getAuthorizedRepoByWebhook
+func (c *Repo) GetWebhookDeliveries(ctx context.Context, nIdentifier string, input dto.WebhookDeliveriesInput) ([]*dto.WebhookDelivery, error) {
+	if err := validate.
+		GetValidateInstance().
+		Struct(input); err != nil {
+		return nil, fault.UserInputErrorFrom(err)
+	}
+
+	id, repo, err := c.getAuthorizedRepoByWebhook(ctx, nIdentifier)
+	if err != nil {
+		return nil, err
+	}
+
+	if deliveries, err := repo.GetWebhookDeliveries(id, input.First); err != nil {
+		return nil, err
+	} else {
+		return dto.WebhookDeliveriesFrom(deliveries), nil
+	}
+}
+
+// AddWebhook
+//
+// Errors:
+//   - fault.UserInputError if the provided input is invalid
+// ErrorsRef:
+//   - controller.Repo.getAuthorizedRepo
+func (c *Repo) AddWebhook(ctx context.Context, input dto.AddWebhookInput) (*dto.Webhook, error) {
+	if err := validate.
+		GetValidateInstance().
+		Struct(input); err != nil {
+		return nil, fault.UserInputErrorFrom(err)
+	}
+
+	_, repo, err := c.getAuthorizedRepoByNode(ctx, input.RepositoryID, "admin")
+	if err != nil {
+		return nil, err
+	}
+
+	if webhook, err := repo.AddWebhook(&facade.WebhookConfig{
+		URL:      input.URL,
+		Secret:   input.Secret,
+		Events:   input.Events,
+		IsActive: input.IsActive,
+	}); err != nil {
+		return nil, webhookErrorFrom(err)
+	} else {
+		return dto.WebhookFrom(webhook), nil
+	}
+}
+
+// UpdateWebhook
+//
+// Errors:
+//   - fault.UserInputError if the provided input is invalid
+// ErrorsRef:
+//   - controller.Repo.getAuthorizedRepoByWebhook
+func (c *Repo) UpdateWebhook(ctx context.Context, input dto.UpdateWebhookInput) (*dto.Webhook, error) {
+	if err := validate.
+		GetValidateInstance().
+		Struct(input); err != nil {
+		return nil, fault.UserInputErrorFrom(err)
+	}
+
+	id, repo, err := c.getAuthorizedRepoByWebhook(ctx, input.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if webhook, err := repo.UpdateWebhook(id, &facade.WebhookConfig{
+		URL:      input.URL,
+		Events:   input.Events,
+		IsActive: input.IsActive,
+	}, input.Secret); err != nil {
+		return nil, webhookErrorFrom(err)
+	} else {
+		return dto.WebhookFrom(webhook), nil
+	}
+}
+
+// RemoveWebhook
+//
+// ErrorsRef:
+//   - controller.Repo.getAuthorizedRepoByWebhook
+//   - facade.Repo.RemoveWebhook
+func (c *Repo) RemoveWebhook(ctx context.Context, nIdentifier string) (*dto.Webhook, error) {
+	id, repo, err := c.getAuthorizedRepoByWebhook(ctx, nIdentifier)
+	if err != nil {
+		return nil, err
+	}
+
+	if webhook, err := repo.RemoveWebhook(id); err != nil {
+		return nil, err
+	} else {
+		return dto.WebhookFrom(webhook), nil
+	}
+}
@@ -0,0 +1,81 @@
+/*
+ * Copyright 2021 Meraj Sahebdar
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package job
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+	"bitban.io/server/internal/cfg"
+	"bitban.io/server/internal/pkg/facade"
+)
+
+// MirrorOpt
+var MirrorOpt = fx.Invoke(registerMirrorLifecycle)
+
+// syncMirrors Fetches the remotes of the mirrors which are due, and pushes to
+// the push mirrors which have queued pushes.
+func syncMirrors(ctx context.Context) {
+	if failed, err := facade.SyncDueMirrors(ctx); err != nil {
+		cfg.Log.Error("failed to sync the mirrors", zap.Error(err))
+	} else if failed > 0 {
+		cfg.Log.Warn("some of the mirrors failed to sync", zap.Int("count", failed))
+	}
+
+	if failed, err := facade.SyncDuePushMirrors(ctx); err != nil {
+		cfg.Log.Error("failed to sync the push mirrors", zap.Error(err))
+	} else if failed > 0 {
+		cfg.Log.Warn("some of the push mirrors failed to sync", zap.Int("count", failed))
+	}
+}
+
+// registerMirrorLifecycle
+func registerMirrorLifecycle(lc fx.Lifecycle) {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	lc.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			ticker := time.NewTicker(time.Duration(cfg.Cog.Git.Mirror.PollInterval) * time.Minute)
+
+			go func() {
+				defer close(done)
+				defer ticker.Stop()
+
+				for {
+					syncMirrors(ctx)
+
+					select {
+					case <-ctx.Done():
+						return
+					case <-ticker.C:
+					}
+				}
+			}()
+
+			return nil
+		},
+		OnStop: func(context.Context) error {
+			cancel()
+			<-done
+
+			return nil
+		},
+	})
+}
@@ -0,0 +1,74 @@
+/*
+ * Copyright 2021 Meraj Sahebdar
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package job
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+	"bitban.io/server/internal/cfg"
+	"bitban.io/server/internal/pkg/facade"
+)
+
+// PurgeOpt
+var PurgeOpt = fx.Invoke(registerPurgeLifecycle)
+
+// purge Removes the repositories whose retention window is over.
+func purge(ctx context.Context) {
+	if purged, err := facade.PurgeTrashedRepos(ctx); err != nil {
+		cfg.Log.Error("failed to purge the trashed repositories", zap.Error(err))
+	} else if purged > 0 {
+		cfg.Log.Info("purged the trashed repositories", zap.Int("count", purged))
+	}
+}
+
+// registerPurgeLifecycle
+func registerPurgeLifecycle(lc fx.Lifecycle) {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	lc.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			ticker := time.NewTicker(time.Duration(cfg.Cog.Git.Trash.PurgeInterval) * time.Minute)
+
+			go func() {
+				defer close(done)
+				defer ticker.Stop()
+
+				for {
+					purge(ctx)
+
+					select {
+					case <-ctx.Done():
+						return
+					case <-ticker.C:
+					}
+				}
+			}()
+
+			return nil
+		},
+		OnStop: func(context.Context) error {
+			cancel()
+			<-done
+
+			return nil
+		},
+	})
+}
@@ -0,0 +1,74 @@
+/*
+ * Copyright 2021 Meraj Sahebdar
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package job
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+	"bitban.io/server/internal/cfg"
+	"bitban.io/server/internal/pkg/facade"
+)
+
+// WebhookOpt
+var WebhookOpt = fx.Invoke(registerWebhookLifecycle)
+
+// deliverWebhooks Attempts the webhook deliveries which are due.
+func deliverWebhooks(ctx context.Context) {
+	if failed, err := facade.DeliverDueWebhooks(ctx); err != nil {
+		cfg.Log.Error("failed to deliver the webhooks", zap.Error(err))
+	} else if failed > 0 {
+		cfg.Log.Warn("some of the webhooks failed to deliver", zap.Int("count", failed))
+	}
+}
+
+// registerWebhookLifecycle
+func registerWebhookLifecycle(lc fx.Lifecycle) {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	lc.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			ticker := time.NewTicker(time.Duration(cfg.Cog.Webhook.PollInterval) * time.Second)
+
+			go func() {
+				defer close(done)
+				defer ticker.Stop()
+
+				for {
+					deliverWebhooks(ctx)
+
+					select {
+					case <-ctx.Done():
+						return
+					case <-ticker.C:
+					}
+				}
+			}()
+
+			return nil
+		},
+		OnStop: func(context.Context) error {
+			cancel()
+			<-done
+
+			return nil
+		},
+	})
+}
@@ -27,12 +27,13 @@ import (
 var ConfigOpt = fx.Provide(newConfig)
 
 // newConfig
-func newConfig(accountController *controller.Account, repoController *controller.Repo) schema.Config {
+func newConfig(accountController *controller.Account, repoController *controller.Repo, organizationController *controller.Organization) schema.Config {
 	return schema.Config{
 		Resolvers: &rootResolver{
-			validate:          validate.GetValidateInstance(),
-			accountController: accountController,
-			repoController:    repoController,
+			validate:               validate.GetValidateInstance(),
+			accountController:      accountController,
+			repoController:         repoController,
+			organizationController: organizationController,
 		},
 	}
 }
@@ -118,6 +118,10 @@ func (r *mutationResolver) CreateRepository(ctx context.Context, input dto.Creat
 		switch {
 		case fault.IsUnauthenticatedError(err):
 			return nil, AuthenticationErrorFrom(err)
+		case fault.IsForbiddenError(err):
+			return nil, ForbiddenErrorFrom(err)
+		case fault.IsResourceNotFoundError(err):
+			return nil, NotFoundErrorFrom(err)
 		case fault.IsUserInputError(err):
 			return nil, UserInputErrorFrom(err)
 		default:
@@ -127,3 +131,865 @@ func (r *mutationResolver) CreateRepository(ctx context.Context, input dto.Creat
 		return dto.RepositoryFrom(repository), nil
 	}
 }
+
+// AddSSHKey
+func (r *mutationResolver) AddSSHKey(ctx context.Context, input dto.AddSshKeyInput) (*dto.SshKey, error) {
+	if sshKey, err := r.
+		accountController.
+		AddSshKey(ctx, input); err != nil {
+		switch {
+		case fault.IsUnauthenticatedError(err):
+			return nil, AuthenticationErrorFrom(err)
+		case fault.IsUserInputError(err):
+			return nil, UserInputErrorFrom(err)
+		default:
+			panic(err)
+		}
+	} else {
+		return sshKey, nil
+	}
+}
+
+// RemoveSSHKey
+func (r *mutationResolver) RemoveSSHKey(ctx context.Context, nIdentifier string) (*dto.SshKey, error) {
+	var id int64
+	if nType, nId, err := dto.FromNodeIdentifier(nIdentifier); err != nil || nType != dto.SshKeyNodeType {
+		return nil, NotFoundErrorFrom(err)
+	} else {
+		id = nId
+	}
+
+	if sshKey, err := r.
+		accountController.
+		RemoveSshKey(ctx, id); err != nil {
+		switch {
+		case fault.IsUnauthenticatedError(err):
+			return nil, AuthenticationErrorFrom(err)
+		case fault.IsResourceNotFoundError(err):
+			return nil, NotFoundErrorFrom(err)
+		default:
+			panic(err)
+		}
+	} else {
+		return sshKey, nil
+	}
+}
+
+// CreateAccessToken
+func (r *mutationResolver) CreateAccessToken(ctx context.Context, input dto.CreateAccessTokenInput) (*dto.CreatedAccessToken, error) {
+	if created, err := r.
+		accountController.
+		CreateAccessToken(ctx, input); err != nil {
+		switch {
+		case fault.IsUnauthenticatedError(err):
+			return nil, AuthenticationErrorFrom(err)
+		case fault.IsUserInputError(err):
+			return nil, UserInputErrorFrom(err)
+		default:
+			panic(err)
+		}
+	} else {
+		return created, nil
+	}
+}
+
+// RevokeAccessToken
+func (r *mutationResolver) RevokeAccessToken(ctx context.Context, nIdentifier string) (*dto.PersonalAccessToken, error) {
+	var id int64
+	if nType, nId, err := dto.FromNodeIdentifier(nIdentifier); err != nil || nType != dto.PersonalAccessTokenNodeType {
+		return nil, NotFoundErrorFrom(err)
+	} else {
+		id = nId
+	}
+
+	if token, err := r.
+		accountController.
+		RevokeAccessToken(ctx, id); err != nil {
+		switch {
+		case fault.IsUnauthenticatedError(err):
+			return nil, AuthenticationErrorFrom(err)
+		case fault.IsResourceNotFoundError(err):
+			return nil, NotFoundErrorFrom(err)
+		default:
+			panic(err)
+		}
+	} else {
+		return token, nil
+	}
+}
+
+// CreateBranch
+func (r *mutationResolver) CreateBranch(ctx context.Context, input dto.CreateBranchInput) (*dto.Ref, error) {
+	if ref, err := r.
+		repoController.
+		CreateBranch(ctx, input); err != nil {
+		switch {
+		case fault.IsUnauthenticatedError(err):
+			return nil, AuthenticationErrorFrom(err)
+		case fault.IsForbiddenError(err):
+			return nil, ForbiddenErrorFrom(err)
+		case fault.IsResourceNotFoundError(err):
+			return nil, NotFoundErrorFrom(err)
+		case fault.IsUserInputError(err):
+			return nil, UserInputErrorFrom(err)
+		default:
+			panic(err)
+		}
+	} else {
+		return ref, nil
+	}
+}
+
+// DeleteBranch
+func (r *mutationResolver) DeleteBranch(ctx context.Context, input dto.DeleteBranchInput) (*dto.Ref, error) {
+	if ref, err := r.
+		repoController.
+		DeleteBranch(ctx, input); err != nil {
+		switch {
+		case fault.IsUnauthenticatedError(err):
+			return nil, AuthenticationErrorFrom(err)
+		case fault.IsForbiddenError(err):
+			return nil, ForbiddenErrorFrom(err)
+		case fault.IsResourceNotFoundError(err):
+			return nil, NotFoundErrorFrom(err)
+		case fault.IsUserInputError(err):
+			return nil, UserInputErrorFrom(err)
+		default:
+			panic(err)
+		}
+	} else {
+		return ref, nil
+	}
+}
+
+// CreateTag
+func (r *mutationResolver) CreateTag(ctx context.Context, input dto.CreateTagInput) (*dto.Ref, error) {
+	if ref, err := r.
+		repoController.
+		CreateTag(ctx, input); err != nil {
+		switch {
+		case fault.IsUnauthenticatedError(err):
+			return nil, AuthenticationErrorFrom(err)
+		case fault.IsForbiddenError(err):
+			return nil, ForbiddenErrorFrom(err)
+		case fault.IsResourceNotFoundError(err):
+			return nil, NotFoundErrorFrom(err)
+		case fault.IsUserInputError(err):
+			return nil, UserInputErrorFrom(err)
+		default:
+			panic(err)
+		}
+	} else {
+		return ref, nil
+	}
+}
+
+// DeleteTag
+func (r *mutationResolver) DeleteTag(ctx context.Context, input dto.DeleteTagInput) (*dto.Ref, error) {
+	if ref, err := r.
+		repoController.
+		DeleteTag(ctx, input); err != nil {
+		switch {
+		case fault.IsUnauthenticatedError(err):
+			return nil, AuthenticationErrorFrom(err)
+		case fault.IsForbiddenError(err):
+			return nil, ForbiddenErrorFrom(err)
+		case fault.IsResourceNotFoundError(err):
+			return nil, NotFoundErrorFrom(err)
+		case fault.IsUserInputError(err):
+			return nil, UserInputErrorFrom(err)
+		default:
+			panic(err)
+		}
+	} else {
+		return ref, nil
+	}
+}
+
+// AddProtectedBranch
+func (r *mutationResolver) AddProtectedBranch(ctx context.Context, input dto.AddProtectedBranchInput) (*dto.ProtectedBranch, error) {
+	if protectedBranch, err := r.
+		repoController.
+		AddProtectedBranch(ctx, input); err != nil {
+		switch {
+		case fault.IsUnauthenticatedError(err):
+			return nil, AuthenticationErrorFrom(err)
+		case fault.IsForbiddenError(err):
+			return nil, ForbiddenErrorFrom(err)
+		case fault.IsResourceNotFoundError(err):
+			return nil, NotFoundErrorFrom(err)
+		case fault.IsUserInputError(err):
+			return nil, UserInputErrorFrom(err)
+		default:
+			panic(err)
+		}
+	} else {
+		return protectedBranch, nil
+	}
+}
+
+// UpdateProtectedBranch
+func (r *mutationResolver) UpdateProtectedBranch(ctx context.Context, input dto.UpdateProtectedBranchInput) (*dto.ProtectedBranch, error) {
+	if protectedBranch, err := r.
+		repoController.
+		UpdateProtectedBranch(ctx, input); err != nil {
+		switch {
+		case fault.IsUnauthenticatedError(err):
+			return nil, AuthenticationErrorFrom(err)
+		case fault.IsForbiddenError(err):
+			return nil, ForbiddenErrorFrom(err)
+		case fault.IsResourceNotFoundError(err):
+			return nil, NotFoundErrorFrom(err)
+		case fault.IsUserInputError(err):
+			return nil, UserInputErrorFrom(err)
+		default:
+			panic(err)
+		}
+	} else {
+		return protectedBranch, nil
+	}
+}
+
+// RemoveProtectedBranch
+func (r *mutationResolver) RemoveProtectedBranch(ctx context.Context, nIdentifier string) (*dto.ProtectedBranch, error) {
+	if protectedBranch, err := r.
+		repoController.
+		RemoveProtectedBranch(ctx, nIdentifier); err != nil {
+		switch {
+		case fault.IsUnauthenticatedError(err):
+			return nil, AuthenticationErrorFrom(err)
+		case fault.IsForbiddenError(err):
+			return nil, ForbiddenErrorFrom(err)
+		case fault.IsResourceNotFoundError(err):
+			return nil, NotFoundErrorFrom(err)
+		case fault.IsUserInputError(err):
+			return nil, UserInputErrorFrom(err)
+		default:
+			panic(err)
+		}
+	} else {
+		return protectedBranch, nil
+	}
+}
+
+// AddWebhook
+func (r *mutationResolver) AddWebhook(ctx context.Context, input dto.AddWebhookInput) (*dto.Webhook, error) {
+	if webhook, err := r.
+		repoController.
+		AddWebhook(ctx, input); err != nil {
+		switch {
+		case fault.IsUnauthenticatedError(err):
+			return nil, AuthenticationErrorFrom(err)
+		case fault.IsForbiddenError(err):
+			return nil, ForbiddenErrorFrom(err)
+		case fault.IsResourceNotFoundError(err):
+			return nil, NotFoundErrorFrom(err)
+		case fault.IsUserInputError(err):
+			return nil, UserInputErrorFrom(err)
+		default:
+			panic(err)
+		}
+	} else {
+		return webhook, nil
+	}
+}
+
+// UpdateWebhook
+func (r *mutationResolver) UpdateWebhook(ctx context.Context, input dto.UpdateWebhookInput) (*dto.Webhook, error) {
+	if webhook, err := r.
+		repoController.
+		UpdateWebhook(ctx, input); err != nil {
+		switch {
+		case fault.IsUnauthenticatedError(err):
+			return nil, AuthenticationErrorFrom(err)
+		case fault.IsForbiddenError(err):
+			return nil, ForbiddenErrorFrom(err)
+		case fault.IsResourceNotFoundError(err):
+			return nil, NotFoundErrorFrom(err)
+		case fault.IsUserInputError(err):
+			return nil, UserInputErrorFrom(err)
+		default:
+			panic(err)
+		}
+	} else {
+		return webhook, nil
+	}
+}
+
+// RemoveWebhook
+func (r *mutationResolver) RemoveWebhook(ctx context.Context, nIdentifier string) (*dto.Webhook, error) {
+	if webhook, err := r.
+		repoController.
+		RemoveWebhook(ctx, nIdentifier); err != nil {
+		switch {
+		case fault.IsUnauthenticatedError(err):
+			return nil, AuthenticationErrorFrom(err)
+		case fault.IsForbiddenError(err):
+			return nil, ForbiddenErrorFrom(err)
+		case fault.IsResourceNotFoundError(err):
+			return nil, NotFoundErrorFrom(err)
+		case fault.IsUserInputError(err):
+			return nil, UserInputErrorFrom(err)
+		default:
+			panic(err)
+		}
+	} else {
+		return webhook, nil
+	}
+}
+
+// DeleteRepository
+func (r *mutationResolver) DeleteRepository(ctx context.Context, nIdentifier string) (*dto.Repository, error) {
+	if repository, err := r.
+		repoController.
+		DeleteRepository(ctx, nIdentifier); err != nil {
+		switch {
+		case fault.IsUnauthenticatedError(err):
+			return nil, AuthenticationErrorFrom(err)
+		case fault.IsForbiddenError(err):
+			return nil, ForbiddenErrorFrom(err)
+		case fault.IsResourceNotFoundError(err):
+			return nil, NotFoundErrorFrom(err)
+		default:
+			panic(err)
+		}
+	} else {
+		return repository, nil
+	}
+}
+
+// RestoreRepository
+func (r *mutationResolver) RestoreRepository(ctx context.Context, nIdentifier string) (*dto.Repository, error) {
+	if repository, err := r.
+		repoController.
+		RestoreRepository(ctx, nIdentifier); err != nil {
+		switch {
+		case fault.IsUnauthenticatedError(err):
+			return nil, AuthenticationErrorFrom(err)
+		case fault.IsForbiddenError(err):
+			return nil, ForbiddenErrorFrom(err)
+		case fault.IsResourceNotFoundError(err):
+			return nil, NotFoundErrorFrom(err)
+		case fault.IsUserInputError(err):
+			return nil, UserInputErrorFrom(err)
+		default:
+			panic(err)
+		}
+	} else {
+		return repository, nil
+	}
+}
+
+// RenameRepository
+func (r *mutationResolver) RenameRepository(ctx context.Context, input dto.RenameRepositoryInput) (*dto.Repository, error) {
+	if repository, err := r.
+		repoController.
+		RenameRepository(ctx, input); err != nil {
+		switch {
+		case fault.IsUnauthenticatedError(err):
+			return nil, AuthenticationErrorFrom(err)
+		case fault.IsForbiddenError(err):
+			return nil, ForbiddenErrorFrom(err)
+		case fault.IsResourceNotFoundError(err):
+			return nil, NotFoundErrorFrom(err)
+		case fault.IsUserInputError(err):
+			return nil, UserInputErrorFrom(err)
+		default:
+			panic(err)
+		}
+	} else {
+		return repository, nil
+	}
+}
+
+// TransferRepository
+func (r *mutationResolver) TransferRepository(ctx context.Context, input dto.TransferRepositoryInput) (*dto.Repository, error) {
+	if repository, err := r.
+		repoController.
+		TransferRepository(ctx, input); err != nil {
+		switch {
+		case fault.IsUnauthenticatedError(err):
+			return nil, AuthenticationErrorFrom(err)
+		case fault.IsForbiddenError(err):
+			return nil, ForbiddenErrorFrom(err)
+		case fault.IsResourceNotFoundError(err):
+			return nil, NotFoundErrorFrom(err)
+		case fault.IsUserInputError(err):
+			return nil, UserInputErrorFrom(err)
+		default:
+			panic(err)
+		}
+	} else {
+		return repository, nil
+	}
+}
+
+// UpdateRepositoryVisibility
+func (r *mutationResolver) UpdateRepositoryVisibility(ctx context.Context, input dto.UpdateRepositoryVisibilityInput) (*dto.Repository, error) {
+	if repository, err := r.
+		repoController.
+		UpdateRepositoryVisibility(ctx, input); err != nil {
+		switch {
+		case fault.IsUnauthenticatedError(err):
+			return nil, AuthenticationErrorFrom(err)
+		case fault.IsForbiddenError(err):
+			return nil, ForbiddenErrorFrom(err)
+		case fault.IsResourceNotFoundError(err):
+			return nil, NotFoundErrorFrom(err)
+		case fault.IsUserInputError(err):
+			return nil, UserInputErrorFrom(err)
+		default:
+			panic(err)
+		}
+	} else {
+		return repository, nil
+	}
+}
+
+// ForkRepository
+func (r *mutationResolver) ForkRepository(ctx context.Context, input dto.ForkRepositoryInput) (*dto.Repository, error) {
+	if repository, err := r.
+		repoController.
+		ForkRepository(ctx, input); err != nil {
+		switch {
+		case fault.IsUnauthenticatedError(err):
+			return nil, AuthenticationErrorFrom(err)
+		case fault.IsForbiddenError(err):
+			return nil, ForbiddenErrorFrom(err)
+		case fault.IsResourceNotFoundError(err):
+			return nil, NotFoundErrorFrom(err)
+		case fault.IsUserInputError(err):
+			return nil, UserInputErrorFrom(err)
+		default:
+			panic(err)
+		}
+	} else {
+		return repository, nil
+	}
+}
+
+// SetMirror
+func (r *mutationResolver) SetMirror(ctx context.Context, input dto.SetMirrorInput) (*dto.PullMirror, error) {
+	if mirror, err := r.
+		repoController.
+		SetMirror(ctx, input); err != nil {
+		switch {
+		case fault.IsUnauthenticatedError(err):
+			return nil, AuthenticationErrorFrom(err)
+		case fault.IsForbiddenError(err):
+			return nil, ForbiddenErrorFrom(err)
+		case fault.IsResourceNotFoundError(err):
+			return nil, NotFoundErrorFrom(err)
+		case fault.IsUserInputError(err):
+			return nil, UserInputErrorFrom(err)
+		default:
+			panic(err)
+		}
+	} else {
+		return mirror, nil
+	}
+}
+
+// RemoveMirror
+func (r *mutationResolver) RemoveMirror(ctx context.Context, nIdentifier string) (*dto.PullMirror, error) {
+	if mirror, err := r.
+		repoController.
+		RemoveMirror(ctx, nIdentifier); err != nil {
+		switch {
+		case fault.IsUnauthenticatedError(err):
+			return nil, AuthenticationErrorFrom(err)
+		case fault.IsForbiddenError(err):
+			return nil, ForbiddenErrorFrom(err)
+		case fault.IsResourceNotFoundError(err):
+			return nil, NotFoundErrorFrom(err)
+		default:
+			panic(err)
+		}
+	} else {
+		return mirror, nil
+	}
+}
+
+// SyncMirror
+func (r *mutationResolver) SyncMirror(ctx context.Context, nIdentifier string) (*dto.PullMirror, error) {
+	if mirror, err := r.
+		repoController.
+		SyncMirror(ctx, nIdentifier); err != nil {
+		switch {
+		case fault.IsUnauthenticatedError(err):
+			return nil, AuthenticationErrorFrom(err)
+		case fault.IsForbiddenError(err):
+			return nil, ForbiddenErrorFrom(err)
+		case fault.IsResourceNotFoundError(err):
+			return nil, NotFoundErrorFrom(err)
+		default:
+			panic(err)
+		}
+	} else {
+		return mirror, nil
+	}
+}
+
+// AddPushMirror
+func (r *mutationResolver) AddPushMirror(ctx context.Context, input dto.AddPushMirrorInput) (*dto.PushMirror, error) {
+	if mirror, err := r.
+		repoController.
+		AddPushMirror(ctx, input); err != nil {
+		switch {
+		case fault.IsUnauthenticatedError(err):
+			return nil, AuthenticationErrorFrom(err)
+		case fault.IsForbiddenError(err):
+			return nil, ForbiddenErrorFrom(err)
+		case fault.IsResourceNotFoundError(err):
+			return nil, NotFoundErrorFrom(err)
+		case fault.IsUserInputError(err):
+			return nil, UserInputErrorFrom(err)
+		default:
+			panic(err)
+		}
+	} else {
+		return mirror, nil
+	}
+}
+
+// RemovePushMirror
+func (r *mutationResolver) RemovePushMirror(ctx context.Context, nIdentifier string) (*dto.PushMirror, error) {
+	if mirror, err := r.
+		repoController.
+		RemovePushMirror(ctx, nIdentifier); err != nil {
+		switch {
+		case fault.IsUnauthenticatedError(err):
+			return nil, AuthenticationErrorFrom(err)
+		case fault.IsForbiddenError(err):
+			return nil, ForbiddenErrorFrom(err)
+		case fault.IsResourceNotFoundError(err):
+			return nil, NotFoundErrorFrom(err)
+		default:
+			panic(err)
+		}
+	} else {
+		return mirror, nil
+	}
+}
+
+// SyncPushMirror
+func (r *mutationResolver) SyncPushMirror(ctx context.Context, nIdentifier string) (*dto.PushMirror, error) {
+	if mirror, err := r.
+		repoController.
+		SyncPushMirror(ctx, nIdentifier); err != nil {
+		switch {
+		case fault.IsUnauthenticatedError(err):
+			return nil, AuthenticationErrorFrom(err)
+		case fault.IsForbiddenError(err):
+			return nil, ForbiddenErrorFrom(err)
+		case fault.IsResourceNotFoundError(err):
+			return nil, NotFoundErrorFrom(err)
+		default:
+			panic(err)
+		}
+	} else {
+		return mirror, nil
+	}
+}
+
+// AddCollaborator
+func (r *mutationResolver) AddCollaborator(ctx context.Context, input dto.CollaboratorInput) (*dto.Collaborator, error) {
+	if collaborator, err := r.
+		repoController.
+		AddCollaborator(ctx, input); err != nil {
+		switch {
+		case fault.IsUnauthenticatedError(err):
+			return nil, AuthenticationErrorFrom(err)
+		case fault.IsForbiddenError(err):
+			return nil, ForbiddenErrorFrom(err)
+		case fault.IsResourceNotFoundError(err):
+			return nil, NotFoundErrorFrom(err)
+		case fault.IsUserInputError(err):
+			return nil, UserInputErrorFrom(err)
+		default:
+			panic(err)
+		}
+	} else {
+		return collaborator, nil
+	}
+}
+
+// UpdateCollaboratorRole
+func (r *mutationResolver) UpdateCollaboratorRole(ctx context.Context, input dto.CollaboratorInput) (*dto.Collaborator, error) {
+	if collaborator, err := r.
+		repoController.
+		UpdateCollaboratorRole(ctx, input); err != nil {
+		switch {
+		case fault.IsUnauthenticatedError(err):
+			return nil, AuthenticationErrorFrom(err)
+		case fault.IsForbiddenError(err):
+			return nil, ForbiddenErrorFrom(err)
+		case fault.IsResourceNotFoundError(err):
+			return nil, NotFoundErrorFrom(err)
+		case fault.IsUserInputError(err):
+			return nil, UserInputErrorFrom(err)
+		default:
+			panic(err)
+		}
+	} else {
+		return collaborator, nil
+	}
+}
+
+// RemoveCollaborator
+func (r *mutationResolver) RemoveCollaborator(ctx context.Context, input dto.RemoveCollaboratorInput) (*dto.Collaborator, error) {
+	if collaborator, err := r.
+		repoController.
+		RemoveCollaborator(ctx, input); err != nil {
+		switch {
+		case fault.IsUnauthenticatedError(err):
+			return nil, AuthenticationErrorFrom(err)
+		case fault.IsForbiddenError(err):
+			return nil, ForbiddenErrorFrom(err)
+		case fault.IsResourceNotFoundError(err):
+			return nil, NotFoundErrorFrom(err)
+		case fault.IsUserInputError(err):
+			return nil, UserInputErrorFrom(err)
+		default:
+			panic(err)
+		}
+	} else {
+		return collaborator, nil
+	}
+}
+
+// CreateOrganization
+func (r *mutationResolver) CreateOrganization(ctx context.Context, input dto.CreateOrganizationInput) (*dto.Organization, error) {
+	if organization, err := r.
+		organizationController.
+		CreateOrganization(ctx, input); err != nil {
+		switch {
+		case fault.IsUnauthenticatedError(err):
+			return nil, AuthenticationErrorFrom(err)
+		case fault.IsUserInputError(err):
+			return nil, UserInputErrorFrom(err)
+		default:
+			panic(err)
+		}
+	} else {
+		return organization, nil
+	}
+}
+
+// AddOrganizationMember
+func (r *mutationResolver) AddOrganizationMember(ctx context.Context, input dto.OrganizationMemberInput) (*dto.OrganizationMember, error) {
+	if member, err := r.
+		organizationController.
+		AddMember(ctx, input); err != nil {
+		switch {
+		case fault.IsUnauthenticatedError(err):
+			return nil, AuthenticationErrorFrom(err)
+		case fault.IsForbiddenError(err):
+			return nil, ForbiddenErrorFrom(err)
+		case fault.IsResourceNotFoundError(err):
+			return nil, NotFoundErrorFrom(err)
+		case fault.IsUserInputError(err):
+			return nil, UserInputErrorFrom(err)
+		default:
+			panic(err)
+		}
+	} else {
+		return member, nil
+	}
+}
+
+// UpdateOrganizationMemberRole
+func (r *mutationResolver) UpdateOrganizationMemberRole(ctx context.Context, input dto.OrganizationMemberInput) (*dto.OrganizationMember, error) {
+	if member, err := r.
+		organizationController.
+		UpdateMemberRole(ctx, input); err != nil {
+		switch {
+		case fault.IsUnauthenticatedError(err):
+			return nil, AuthenticationErrorFrom(err)
+		case fault.IsForbiddenError(err):
+			return nil, ForbiddenErrorFrom(err)
+		case fault.IsResourceNotFoundError(err):
+			return nil, NotFoundErrorFrom(err)
+		case fault.IsUserInputError(err):
+			return nil, UserInputErrorFrom(err)
+		default:
+			panic(err)
+		}
+	} else {
+		return member, nil
+	}
+}
+
+// RemoveOrganizationMember
+func (r *mutationResolver) RemoveOrganizationMember(ctx context.Context, input dto.RemoveOrganizationMemberInput) (*dto.OrganizationMember, error) {
+	if member, err := r.
+		organizationController.
+		RemoveMember(ctx, input); err != nil {
+		switch {
+		case fault.IsUnauthenticatedError(err):
+			return nil, AuthenticationErrorFrom(err)
+		case fault.IsForbiddenError(err):
+			return nil, ForbiddenErrorFrom(err)
+		case fault.IsResourceNotFoundError(err):
+			return nil, NotFoundErrorFrom(err)
+		case fault.IsUserInputError(err):
+			return nil, UserInputErrorFrom(err)
+		default:
+			panic(err)
+		}
+	} else {
+		return member, nil
+	}
+}
+
+// CreateTeam
+func (r *mutationResolver) CreateTeam(ctx context.Context, input dto.CreateTeamInput) (*dto.Team, error) {
+	if team, err := r.
+		organizationController.
+		CreateTeam(ctx, input); err != nil {
+		switch {
+		case fault.IsUnauthenticatedError(err):
+			return nil, AuthenticationErrorFrom(err)
+		case fault.IsForbiddenError(err):
+			return nil, ForbiddenErrorFrom(err)
+		case fault.IsResourceNotFoundError(err):
+			return nil, NotFoundErrorFrom(err)
+		case fault.IsUserInputError(err):
+			return nil, UserInputErrorFrom(err)
+		default:
+			panic(err)
+		}
+	} else {
+		return team, nil
+	}
+}
+
+// UpdateTeam
+func (r *mutationResolver) UpdateTeam(ctx context.Context, input dto.UpdateTeamInput) (*dto.Team, error) {
+	if team, err := r.
+		organizationController.
+		UpdateTeam(ctx, input); err != nil {
+		switch {
+		case fault.IsUnauthenticatedError(err):
+			return nil, AuthenticationErrorFrom(err)
+		case fault.IsForbiddenError(err):
+			return nil, ForbiddenErrorFrom(err)
+		case fault.IsResourceNotFoundError(err):
+			return nil, NotFoundErrorFrom(err)
+		case fault.IsUserInputError(err):
+			return nil, UserInputErrorFrom(err)
+		default:
+			panic(err)
+		}
+	} else {
+		return team, nil
+	}
+}
+
+// DeleteTeam
+func (r *mutationResolver) DeleteTeam(ctx context.Context, nIdentifier string) (*dto.Team, error) {
+	if team, err := r.
+		organizationController.
+		DeleteTeam(ctx, nIdentifier); err != nil {
+		switch {
+		case fault.IsUnauthenticatedError(err):
+			return nil, AuthenticationErrorFrom(err)
+		case fault.IsForbiddenError(err):
+			return nil, ForbiddenErrorFrom(err)
+		case fault.IsResourceNotFoundError(err):
+			return nil, NotFoundErrorFrom(err)
+		default:
+			panic(err)
+		}
+	} else {
+		return team, nil
+	}
+}
+
+// AddTeamMember
+func (r *mutationResolver) AddTeamMember(ctx context.Context, input dto.TeamMemberInput) (*dto.User, error) {
+	if user, err := r.
+		organizationController.
+		AddTeamMember(ctx, input); err != nil {
+		switch {
+		case fault.IsUnauthenticatedError(err):
+			return nil, AuthenticationErrorFrom(err)
+		case fault.IsForbiddenError(err):
+			return nil, ForbiddenErrorFrom(err)
+		case fault.IsResourceNotFoundError(err):
+			return nil, NotFoundErrorFrom(err)
+		case fault.IsUserInputError(err):
+			return nil, UserInputErrorFrom(err)
+		default:
+			panic(err)
+		}
+	} else {
+		return user, nil
+	}
+}
+
+// RemoveTeamMember
+func (r *mutationResolver) RemoveTeamMember(ctx context.Context, input dto.TeamMemberInput) (*dto.User, error) {
+	if user, err := r.
+		organizationController.
+		RemoveTeamMember(ctx, input); err != nil {
+		switch {
+		case fault.IsUnauthenticatedError(err):
+			return nil, AuthenticationErrorFrom(err)
+		case fault.IsForbiddenError(err):
+			return nil, ForbiddenErrorFrom(err)
+		case fault.IsResourceNotFoundError(err):
+			return nil, NotFoundErrorFrom(err)
+		case fault.IsUserInputError(err):
+			return nil, UserInputErrorFrom(err)
+		default:
+			panic(err)
+		}
+	} else {
+		return user, nil
+	}
+}
+
+// GrantTeamRepository
+func (r *mutationResolver) GrantTeamRepository(ctx context.Context, input dto.TeamRepositoryInput) (*dto.TeamRepository, error) {
+	if repository, err := r.
+		organizationController.
+		GrantTeamRepository(ctx, input); err != nil {
+		switch {
+		case fault.IsUnauthenticatedError(err):
+			return nil, AuthenticationErrorFrom(err)
+		case fault.IsForbiddenError(err):
+			return nil, ForbiddenErrorFrom(err)
+		case fault.IsResourceNotFoundError(err):
+			return nil, NotFoundErrorFrom(err)
+		case fault.IsUserInputError(err):
+			return nil, UserInputErrorFrom(err)
+		default:
+			panic(err)
+		}
+	} else {
+		return repository, nil
+	}
+}
+
+// RevokeTeamRepository
+func (r *mutationResolver) RevokeTeamRepository(ctx context.Context, input dto.RevokeTeamRepositoryInput) (*dto.TeamRepository, error) {
+	if repository, err := r.
+		organizationController.
+		RevokeTeamRepository(ctx, input); err != nil {
+		switch {
+		case fault.IsUnauthenticatedError(err):
+			return nil, AuthenticationErrorFrom(err)
+		case fault.IsForbiddenError(err):
+			return nil, ForbiddenErrorFrom(err)
+		case fault.IsResourceNotFoundError(err):
+			return nil, NotFoundErrorFrom(err)
+		case fault.IsUserInputError(err):
+			return nil, UserInputErrorFrom(err)
+		default:
+			panic(err)
+		}
+	} else {
+		return repository, nil
+	}
+}
@@ -35,6 +35,12 @@ func (r *queryResolver) Node(ctx context.Context, nIdentifier string) (node dto.
 	switch nType {
 	case dto.UserNodeType:
 		node, err = r.accountController.GetUser(ctx, id)
+	case dto.RepositoryNodeType:
+		node, err = r.repoController.GetRepository(ctx, id)
+	case dto.OrganizationNodeType:
+		node, err = r.organizationController.GetOrganization(ctx, id)
+	case dto.TeamNodeType:
+		node, err = r.organizationController.GetTeam(ctx, id)
 	}
 
 	if err == nil {
@@ -52,3 +58,57 @@ func (r *queryResolver) Node(ctx context.Context, nIdentifier string) (node dto.
 		panic(err)
 	}
 }
+
+// Viewer
+func (r *queryResolver) Viewer(ctx context.Context) (*dto.User, error) {
+	if user, err := r.accountController.GetViewer(ctx); err != nil {
+		switch {
+		case fault.IsUnauthenticatedError(err):
+			return nil, AuthenticationErrorFrom(err)
+		default:
+			panic(err)
+		}
+	} else {
+		return user, nil
+	}
+}
+
+// Repository
+func (r *queryResolver) Repository(ctx context.Context, domain string, address string) (*dto.Repository, error) {
+	if repository, err := r.
+		repoController.
+		GetRepositoryByAddress(ctx, domain, address); err != nil {
+		switch {
+		case fault.IsUnauthenticatedError(err):
+			return nil, AuthenticationErrorFrom(err)
+		case fault.IsForbiddenError(err):
+			return nil, ForbiddenErrorFrom(err)
+		case fault.IsResourceNotFoundError(err):
+			return nil, NotFoundErrorFrom(err)
+		default:
+			panic(err)
+		}
+	} else {
+		return repository, nil
+	}
+}
+
+// Organization
+func (r *queryResolver) Organization(ctx context.Context, address string) (*dto.Organization, error) {
+	if organization, err := r.
+		organizationController.
+		GetOrganizationByAddress(ctx, address); err != nil {
+		switch {
+		case fault.IsUnauthenticatedError(err):
+			return nil, AuthenticationErrorFrom(err)
+		case fault.IsForbiddenError(err):
+			return nil, ForbiddenErrorFrom(err)
+		case fault.IsResourceNotFoundError(err):
+			return nil, NotFoundErrorFrom(err)
+		default:
+			panic(err)
+		}
+	} else {
+		return organization, nil
+	}
+}
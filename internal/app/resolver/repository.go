@@ -0,0 +1,324 @@
+/*
+ * Copyright 2021 Meraj Sahebdar
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package resolver
+
+import (
+	"context"
+	"time"
+
+	"bitban.io/server/internal/pkg/dto"
+	"bitban.io/server/internal/pkg/fault"
+)
+
+// Tree
+func (r *repositoryResolver) Tree(ctx context.Context, obj *dto.Repository, ref string, path string) ([]*dto.TreeEntry, error) {
+	if entries, err := r.
+		repoController.
+		GetTree(ctx, dto.MustRetrieveIdentifier(obj.ID), ref, path); err != nil {
+		switch {
+		case fault.IsUnauthenticatedError(err):
+			return nil, AuthenticationErrorFrom(err)
+		case fault.IsForbiddenError(err):
+			return nil, ForbiddenErrorFrom(err)
+		case fault.IsResourceNotFoundError(err):
+			return nil, NotFoundErrorFrom(err)
+		default:
+			panic(err)
+		}
+	} else {
+		return entries, nil
+	}
+}
+
+// Blob
+func (r *repositoryResolver) Blob(ctx context.Context, obj *dto.Repository, ref string, path string) (*dto.Blob, error) {
+	if blob, err := r.
+		repoController.
+		GetBlob(ctx, dto.MustRetrieveIdentifier(obj.ID), ref, path); err != nil {
+		switch {
+		case fault.IsUnauthenticatedError(err):
+			return nil, AuthenticationErrorFrom(err)
+		case fault.IsForbiddenError(err):
+			return nil, ForbiddenErrorFrom(err)
+		case fault.IsResourceNotFoundError(err):
+			return nil, NotFoundErrorFrom(err)
+		default:
+			panic(err)
+		}
+	} else {
+		return blob, nil
+	}
+}
+
+// Commits
+func (r *repositoryResolver) Commits(ctx context.Context, obj *dto.Repository, ref string, path string, author string, since *time.Time, until *time.Time, first int, after *string) (*dto.CommitConnection, error) {
+	if commits, err := r.
+		repoController.
+		GetCommits(ctx, dto.MustRetrieveIdentifier(obj.ID), dto.CommitsInput{
+			Ref:    ref,
+			Path:   path,
+			Author: author,
+			Since:  since,
+			Until:  until,
+			First:  first,
+			After:  after,
+		}); err != nil {
+		switch {
+		case fault.IsUnauthenticatedError(err):
+			return nil, AuthenticationErrorFrom(err)
+		case fault.IsForbiddenError(err):
+			return nil, ForbiddenErrorFrom(err)
+		case fault.IsResourceNotFoundError(err):
+			return nil, NotFoundErrorFrom(err)
+		case fault.IsUserInputError(err):
+			return nil, UserInputErrorFrom(err)
+		default:
+			panic(err)
+		}
+	} else {
+		return commits, nil
+	}
+}
+
+// Refs
+func (r *repositoryResolver) Refs(ctx context.Context, obj *dto.Repository, prefix string) ([]*dto.Ref, error) {
+	if refs, err := r.
+		repoController.
+		GetRefs(ctx, dto.MustRetrieveIdentifier(obj.ID), prefix); err != nil {
+		switch {
+		case fault.IsUnauthenticatedError(err):
+			return nil, AuthenticationErrorFrom(err)
+		case fault.IsForbiddenError(err):
+			return nil, ForbiddenErrorFrom(err)
+		case fault.IsResourceNotFoundError(err):
+			return nil, NotFoundErrorFrom(err)
+		default:
+			panic(err)
+		}
+	} else {
+		return refs, nil
+	}
+}
+
+// Compare
+func (r *repositoryResolver) Compare(ctx context.Context, obj *dto.Repository, base string, head string) (*dto.Comparison, error) {
+	if comparison, err := r.
+		repoController.
+		Compare(ctx, dto.MustRetrieveIdentifier(obj.ID), base, head); err != nil {
+		switch {
+		case fault.IsUnauthenticatedError(err):
+			return nil, AuthenticationErrorFrom(err)
+		case fault.IsForbiddenError(err):
+			return nil, ForbiddenErrorFrom(err)
+		case fault.IsResourceNotFoundError(err):
+			return nil, NotFoundErrorFrom(err)
+		case fault.IsUserInputError(err):
+			return nil, UserInputErrorFrom(err)
+		default:
+			panic(err)
+		}
+	} else {
+		return comparison, nil
+	}
+}
+
+// Blame
+func (r *repositoryResolver) Blame(ctx context.Context, obj *dto.Repository, ref string, path string) ([]*dto.BlameRange, error) {
+	if ranges, err := r.
+		repoController.
+		GetBlame(ctx, dto.MustRetrieveIdentifier(obj.ID), ref, path); err != nil {
+		switch {
+		case fault.IsUnauthenticatedError(err):
+			return nil, AuthenticationErrorFrom(err)
+		case fault.IsForbiddenError(err):
+			return nil, ForbiddenErrorFrom(err)
+		case fault.IsResourceNotFoundError(err):
+			return nil, NotFoundErrorFrom(err)
+		default:
+			panic(err)
+		}
+	} else {
+		return ranges, nil
+	}
+}
+
+// ProtectedBranches
+func (r *repositoryResolver) ProtectedBranches(ctx context.Context, obj *dto.Repository) ([]*dto.ProtectedBranch, error) {
+	if protectedBranches, err := r.
+		repoController.
+		GetProtectedBranches(ctx, dto.MustRetrieveIdentifier(obj.ID)); err != nil {
+		switch {
+		case fault.IsUnauthenticatedError(err):
+			return nil, AuthenticationErrorFrom(err)
+		case fault.IsForbiddenError(err):
+			return nil, ForbiddenErrorFrom(err)
+		case fault.IsResourceNotFoundError(err):
+			return nil, NotFoundErrorFrom(err)
+		default:
+			panic(err)
+		}
+	} else {
+		return protectedBranches, nil
+	}
+}
+
+// Webhooks
+func (r *repositoryResolver) Webhooks(ctx context.Context, obj *dto.Repository) ([]*dto.Webhook, error) {
+	if webhooks, err := r.
+		repoController.
+		GetWebhooks(ctx, dto.MustRetrieveIdentifier(obj.ID)); err != nil {
+		switch {
+		case fault.IsUnauthenticatedError(err):
+			return nil, AuthenticationErrorFrom(err)
+		case fault.IsForbiddenError(err):
+			return nil, ForbiddenErrorFrom(err)
+		case fault.IsResourceNotFoundError(err):
+			return nil, NotFoundErrorFrom(err)
+		default:
+			panic(err)
+		}
+	} else {
+		return webhooks, nil
+	}
+}
+
+// Activity
+func (r *repositoryResolver) Activity(ctx context.Context, obj *dto.Repository, ref string, forcedOnly bool, first int, after *string) (*dto.PushEventConnection, error) {
+	if activity, err := r.
+		repoController.
+		GetActivity(ctx, dto.MustRetrieveIdentifier(obj.ID), dto.ActivityInput{
+			Ref:        ref,
+			ForcedOnly: forcedOnly,
+			First:      first,
+			After:      after,
+		}); err != nil {
+		switch {
+		case fault.IsUnauthenticatedError(err):
+			return nil, AuthenticationErrorFrom(err)
+		case fault.IsForbiddenError(err):
+			return nil, ForbiddenErrorFrom(err)
+		case fault.IsResourceNotFoundError(err):
+			return nil, NotFoundErrorFrom(err)
+		case fault.IsUserInputError(err):
+			return nil, UserInputErrorFrom(err)
+		default:
+			panic(err)
+		}
+	} else {
+		return activity, nil
+	}
+}
+
+// Parent
+func (r *repositoryResolver) Parent(ctx context.Context, obj *dto.Repository) (*dto.Repository, error) {
+	if parent, err := r.
+		repoController.
+		GetParent(ctx, dto.MustRetrieveIdentifier(obj.ID)); err != nil {
+		switch {
+		case fault.IsUnauthenticatedError(err):
+			return nil, AuthenticationErrorFrom(err)
+		case fault.IsForbiddenError(err):
+			return nil, ForbiddenErrorFrom(err)
+		case fault.IsResourceNotFoundError(err):
+			return nil, NotFoundErrorFrom(err)
+		default:
+			panic(err)
+		}
+	} else {
+		return parent, nil
+	}
+}
+
+// Forks
+func (r *repositoryResolver) Forks(ctx context.Context, obj *dto.Repository) ([]*dto.Repository, error) {
+	if forks, err := r.
+		repoController.
+		GetForks(ctx, dto.MustRetrieveIdentifier(obj.ID)); err != nil {
+		switch {
+		case fault.IsUnauthenticatedError(err):
+			return nil, AuthenticationErrorFrom(err)
+		case fault.IsForbiddenError(err):
+			return nil, ForbiddenErrorFrom(err)
+		case fault.IsResourceNotFoundError(err):
+			return nil, NotFoundErrorFrom(err)
+		default:
+			panic(err)
+		}
+	} else {
+		return forks, nil
+	}
+}
+
+// Mirror
+func (r *repositoryResolver) Mirror(ctx context.Context, obj *dto.Repository) (*dto.PullMirror, error) {
+	if mirror, err := r.
+		repoController.
+		GetMirror(ctx, dto.MustRetrieveIdentifier(obj.ID)); err != nil {
+		switch {
+		case fault.IsUnauthenticatedError(err):
+			return nil, AuthenticationErrorFrom(err)
+		case fault.IsForbiddenError(err):
+			return nil, ForbiddenErrorFrom(err)
+		case fault.IsResourceNotFoundError(err):
+			return nil, NotFoundErrorFrom(err)
+		default:
+			panic(err)
+		}
+	} else {
+		return mirror, nil
+	}
+}
+
+// PushMirrors
+func (r *repositoryResolver) PushMirrors(ctx context.Context, obj *dto.Repository) ([]*dto.PushMirror, error) {
+	if mirrors, err := r.
+		repoController.
+		GetPushMirrors(ctx, dto.MustRetrieveIdentifier(obj.ID)); err != nil {
+		switch {
+		case fault.IsUnauthenticatedError(err):
+			return nil, AuthenticationErrorFrom(err)
+		case fault.IsForbiddenError(err):
+			return nil, ForbiddenErrorFrom(err)
+		case fault.IsResourceNotFoundError(err):
+			return nil, NotFoundErrorFrom(err)
+		default:
+			panic(err)
+		}
+	} else {
+		return mirrors, nil
+	}
+}
+
+// Collaborators
+func (r *repositoryResolver) Collaborators(ctx context.Context, obj *dto.Repository) ([]*dto.Collaborator, error) {
+	if collaborators, err := r.
+		repoController.
+		GetCollaborators(ctx, dto.MustRetrieveIdentifier(obj.ID)); err != nil {
+		switch {
+		case fault.IsUnauthenticatedError(err):
+			return nil, AuthenticationErrorFrom(err)
+		case fault.IsForbiddenError(err):
+			return nil, ForbiddenErrorFrom(err)
+		case fault.IsResourceNotFoundError(err):
+			return nil, NotFoundErrorFrom(err)
+		default:
+			panic(err)
+		}
+	} else {
+		return collaborators, nil
+	}
+}
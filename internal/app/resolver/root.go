@@ -25,9 +25,10 @@ import (
 type (
 	// rootResolver
 	rootResolver struct {
-		validate          *validator.Validate
-		accountController *controller.Account
-		repoController    *controller.Repo
+		validate               *validator.Validate
+		accountController      *controller.Account
+		repoController         *controller.Repo
+		organizationController *controller.Organization
 	}
 
 	// queryResolver
@@ -39,6 +40,31 @@ type (
 	mutationResolver struct {
 		*rootResolver
 	}
+
+	// userResolver
+	userResolver struct {
+		*rootResolver
+	}
+
+	// repositoryResolver
+	repositoryResolver struct {
+		*rootResolver
+	}
+
+	// webhookResolver
+	webhookResolver struct {
+		*rootResolver
+	}
+
+	// organizationResolver
+	organizationResolver struct {
+		*rootResolver
+	}
+
+	// teamResolver
+	teamResolver struct {
+		*rootResolver
+	}
 )
 
 // Query
@@ -54,3 +80,38 @@ func (r *rootResolver) Mutation() schema.MutationResolver {
 		rootResolver: r,
 	}
 }
+
+// User
+func (r *rootResolver) User() schema.UserResolver {
+	return &userResolver{
+		rootResolver: r,
+	}
+}
+
+// Repository
+func (r *rootResolver) Repository() schema.RepositoryResolver {
+	return &repositoryResolver{
+		rootResolver: r,
+	}
+}
+
+// Webhook
+func (r *rootResolver) Webhook() schema.WebhookResolver {
+	return &webhookResolver{
+		rootResolver: r,
+	}
+}
+
+// Organization
+func (r *rootResolver) Organization() schema.OrganizationResolver {
+	return &organizationResolver{
+		rootResolver: r,
+	}
+}
+
+// Team
+func (r *rootResolver) Team() schema.TeamResolver {
+	return &teamResolver{
+		rootResolver: r,
+	}
+}
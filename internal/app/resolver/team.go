@@ -0,0 +1,64 @@
+/*
+ * Copyright 2021 Meraj Sahebdar
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package resolver
+
+import (
+	"context"
+
+	"bitban.io/server/internal/pkg/dto"
+	"bitban.io/server/internal/pkg/fault"
+)
+
+// Members
+func (r *teamResolver) Members(ctx context.Context, obj *dto.Team) ([]*dto.User, error) {
+	if members, err := r.
+		organizationController.
+		GetTeamMembers(ctx, dto.MustRetrieveIdentifier(obj.ID)); err != nil {
+		switch {
+		case fault.IsUnauthenticatedError(err):
+			return nil, AuthenticationErrorFrom(err)
+		case fault.IsForbiddenError(err):
+			return nil, ForbiddenErrorFrom(err)
+		case fault.IsResourceNotFoundError(err):
+			return nil, NotFoundErrorFrom(err)
+		default:
+			panic(err)
+		}
+	} else {
+		return members, nil
+	}
+}
+
+// Repositories
+func (r *teamResolver) Repositories(ctx context.Context, obj *dto.Team) ([]*dto.TeamRepository, error) {
+	if repositories, err := r.
+		organizationController.
+		GetTeamRepositories(ctx, dto.MustRetrieveIdentifier(obj.ID)); err != nil {
+		switch {
+		case fault.IsUnauthenticatedError(err):
+			return nil, AuthenticationErrorFrom(err)
+		case fault.IsForbiddenError(err):
+			return nil, ForbiddenErrorFrom(err)
+		case fault.IsResourceNotFoundError(err):
+			return nil, NotFoundErrorFrom(err)
+		default:
+			panic(err)
+		}
+	} else {
+		return repositories, nil
+	}
+}
@@ -0,0 +1,91 @@
+/*
+ * Copyright 2021 Meraj Sahebdar
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package resolver
+
+import (
+	"context"
+
+	"bitban.io/server/internal/pkg/dto"
+	"bitban.io/server/internal/pkg/fault"
+)
+
+// SSHKeys
+func (r *userResolver) SSHKeys(ctx context.Context, obj *dto.User) ([]*dto.SshKey, error) {
+	if sshKeys, err := r.
+		accountController.
+		GetSshKeys(ctx, dto.MustRetrieveIdentifier(obj.ID)); err != nil {
+		switch {
+		case fault.IsUnauthenticatedError(err):
+			return nil, AuthenticationErrorFrom(err)
+		case fault.IsForbiddenError(err):
+			return nil, ForbiddenErrorFrom(err)
+		case fault.IsResourceNotFoundError(err):
+			return nil, NotFoundErrorFrom(err)
+		default:
+			panic(err)
+		}
+	} else {
+		return sshKeys, nil
+	}
+}
+
+// AccessTokens
+func (r *userResolver) AccessTokens(ctx context.Context, obj *dto.User) ([]*dto.PersonalAccessToken, error) {
+	if tokens, err := r.
+		accountController.
+		GetAccessTokens(ctx, dto.MustRetrieveIdentifier(obj.ID)); err != nil {
+		switch {
+		case fault.IsUnauthenticatedError(err):
+			return nil, AuthenticationErrorFrom(err)
+		case fault.IsForbiddenError(err):
+			return nil, ForbiddenErrorFrom(err)
+		case fault.IsResourceNotFoundError(err):
+			return nil, NotFoundErrorFrom(err)
+		default:
+			panic(err)
+		}
+	} else {
+		return tokens, nil
+	}
+}
+
+// Activity
+func (r *userResolver) Activity(ctx context.Context, obj *dto.User, ref string, forcedOnly bool, first int, after *string) (*dto.PushEventConnection, error) {
+	if activity, err := r.
+		accountController.
+		GetActivity(ctx, dto.MustRetrieveIdentifier(obj.ID), dto.ActivityInput{
+			Ref:        ref,
+			ForcedOnly: forcedOnly,
+			First:      first,
+			After:      after,
+		}); err != nil {
+		switch {
+		case fault.IsUnauthenticatedError(err):
+			return nil, AuthenticationErrorFrom(err)
+		case fault.IsForbiddenError(err):
+			return nil, ForbiddenErrorFrom(err)
+		case fault.IsResourceNotFoundError(err):
+			return nil, NotFoundErrorFrom(err)
+		case fault.IsUserInputError(err):
+			return nil, UserInputErrorFrom(err)
+		default:
+			panic(err)
+		}
+	} else {
+		return activity, nil
+	}
+}
@@ -35,8 +35,10 @@ const (
 // Cog
 var Cog struct {
 	App struct {
-		Host string `yaml:"host" default:"0.0.0.0"`
-		Port int    `yaml:"port" default:"8080"`
+		Host           string   `yaml:"host" default:"0.0.0.0"`
+		Port           int      `yaml:"port" default:"8080"`
+		Url            string   `yaml:"url"`
+		TrustedProxies []string `yaml:"trustedProxies"`
 	} `yaml:"app"`
 	Git struct {
 		Backend GitBackend `yaml:"backend"`
@@ -46,10 +48,24 @@ var Cog struct {
 				DefaultBranch string `yaml:"defaultBranch" default:"main"`
 			} `yaml:"init"`
 		} `yaml:"configs"`
+		Trash struct {
+			Retention     int `yaml:"retention" default:"43200"`
+			PurgeInterval int `yaml:"purgeInterval" default:"60"`
+		} `yaml:"trash"`
+		Mirror struct {
+			SyncInterval int  `yaml:"syncInterval" default:"60"`
+			PollInterval int  `yaml:"pollInterval" default:"1"`
+			AllowFile    bool `yaml:"allowFile"`
+		} `yaml:"mirror"`
 	} `yaml:"git"`
+	Webhook struct {
+		PollInterval int `yaml:"pollInterval" default:"5"`
+	} `yaml:"webhook"`
 	Security struct {
-		AccessTokenExpiresAt  int `yaml:"accessTokenExpiresAt" default:"60"`
-		RefreshTokenExpiresAt int `yaml:"refreshTokenExpiresAt" default:"259200"`
+		AccessTokenExpiresAt  int    `yaml:"accessTokenExpiresAt" default:"60"`
+		RefreshTokenExpiresAt int    `yaml:"refreshTokenExpiresAt" default:"259200"`
+		EncryptionKey         string `yaml:"encryptionKey"`
+		AllowPrivateNetworks  bool   `yaml:"allowPrivateNetworks"`
 	} `yaml:"security"`
 	Database struct {
 		Host   string `yaml:"host" default:"127.0.0.1"`
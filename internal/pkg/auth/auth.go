@@ -20,6 +20,7 @@ import (
 	"context"
 	"errors"
 	"net/http"
+	"strings"
 	"time"
 
 	gojwt "github.com/dgrijalva/jwt-go"
@@ -42,6 +43,10 @@ const (
 
 	// authHeaderScheme
 	authHeaderScheme = "Bearer"
+
+	// PersonalAccessTokenPrefix Tells the personal access tokens apart from the
+	// jwt tokens.
+	PersonalAccessTokenPrefix = "bbp_"
 )
 
 // SetRefreshTokenCookie
@@ -77,20 +82,61 @@ func GetContextRefreshTokenClaims(ctx context.Context) (*gojwt.StandardClaims, e
 	}
 }
 
+// getContextBearerToken Returns the token of the bearer authorization header,
+// or an empty string if there is none.
+func getContextBearerToken(ctx context.Context) string {
+	authorization := util.GetHeader(ctx, echo.HeaderAuthorization)
+	schemeLength := len(authHeaderScheme)
+	if len(authorization) > schemeLength+1 && authorization[:schemeLength] == authHeaderScheme {
+		return authorization[schemeLength+1:]
+	}
+
+	return ""
+}
+
+// IsPersonalAccessToken
+func IsPersonalAccessToken(token string) bool {
+	return strings.HasPrefix(token, PersonalAccessTokenPrefix)
+}
+
+// GetContextPersonalAccessToken Returns the bearer token if it is a personal
+// access token.
+func GetContextPersonalAccessToken(ctx context.Context) (string, bool) {
+	if token := getContextBearerToken(ctx); IsPersonalAccessToken(token) {
+		return token, true
+	}
+
+	return "", false
+}
+
 // GetContextAccessTokenClaims
 //
 // Errors:
 //   - auth.ErrMissingJwtToken in case of missing jwt token
 //   - auth.ErrInvalidJwtToken in case of invalid or expired jwt token
 func GetContextAccessTokenClaims(ctx context.Context) (*gojwt.StandardClaims, error) {
-	var token string
+	token := getContextBearerToken(ctx)
+	if token == "" {
+		return nil, ErrMissingJwtToken
+	}
 
-	authorization := util.GetHeader(ctx, echo.HeaderAuthorization)
-	schemeLength := len(authHeaderScheme)
-	if len(authorization) > schemeLength+1 && authorization[:schemeLength] == authHeaderScheme {
-		token = authorization[schemeLength+1:]
+	// The tokens issued for a single audience, such as the git lfs ones, are
+	// not access tokens of the api.
+	if claims, err := jwt.GetJwtInstance().VerifyToken(token); err != nil || isTokenExpired(claims) || claims.Audience != "" {
+		return nil, ErrInvalidJwtToken
+	} else {
+		return claims, nil
 	}
+}
 
+// GetContextAudienceTokenClaims Returns the claims of the bearer token if it
+// is issued for one of the audiences.
+//
+// Errors:
+//   - auth.ErrMissingJwtToken in case of missing jwt token
+//   - auth.ErrInvalidJwtToken in case of invalid, expired or foreign jwt token
+func GetContextAudienceTokenClaims(ctx context.Context, audiences ...string) (*gojwt.StandardClaims, error) {
+	token := getContextBearerToken(ctx)
 	if token == "" {
 		return nil, ErrMissingJwtToken
 	}
@@ -98,6 +144,12 @@ func GetContextAccessTokenClaims(ctx context.Context) (*gojwt.StandardClaims, er
 	if claims, err := jwt.GetJwtInstance().VerifyToken(token); err != nil || isTokenExpired(claims) {
 		return nil, ErrInvalidJwtToken
 	} else {
-		return claims, nil
+		for _, audience := range audiences {
+			if claims.Audience == audience {
+				return claims, nil
+			}
+		}
+
+		return nil, ErrInvalidJwtToken
 	}
 }
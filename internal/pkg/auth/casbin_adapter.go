@@ -18,6 +18,7 @@ package auth
 
 import (
 	"context"
+	"fmt"
 	"strings"
 
 	"github.com/casbin/casbin/v2/model"
@@ -156,19 +157,57 @@ func (a *adapter) LoadPolicy(m model.Model) error {
 	return nil
 }
 
+// deletePolicyQuery Returns the query deleting the policies whose fields
+// starting from the index match the values, skipping the empty ones.
+func (a *adapter) deletePolicyQuery(db bun.IDB, ptype string, fieldIndex int, fieldValues ...string) *bun.DeleteQuery {
+	query := db.NewDelete().
+		Model((*entity.Policy)(nil)).
+		Where("? = ?", bun.Ident("ptype"), ptype)
+
+	for i, value := range fieldValues {
+		if value != "" {
+			query = query.Where("? = ?", bun.Ident(fmt.Sprintf("v%d", fieldIndex+i)), value)
+		}
+	}
+
+	return query
+}
+
 // RemoveFilteredPolicy
-func (*adapter) RemoveFilteredPolicy(sec string, ptype string, fieldIndex int, fieldValues ...string) error {
+func (a *adapter) RemoveFilteredPolicy(sec string, ptype string, fieldIndex int, fieldValues ...string) error {
+	if _, err := a.deletePolicyQuery(a.db, ptype, fieldIndex, fieldValues...).Exec(context.Background()); err != nil {
+		return err
+	}
+
 	return nil
 }
 
 // RemovePolicy
-func (*adapter) RemovePolicy(sec string, ptype string, rules []string) error {
+func (a *adapter) RemovePolicy(sec string, ptype string, rule []string) error {
+	if _, err := a.deletePolicyQuery(a.db, ptype, 0, rule...).Exec(context.Background()); err != nil {
+		return err
+	}
+
 	return nil
 }
 
 // RemovePolicies
-func (*adapter) RemovePolicies(sec string, ptype string, rules [][]string) error {
-	return nil
+func (a *adapter) RemovePolicies(sec string, ptype string, rules [][]string) error {
+	var err error
+
+	var tx bun.Tx
+	if tx, err = a.db.BeginTx(context.Background(), nil); err != nil {
+		return err
+	}
+
+	for _, rule := range rules {
+		if _, err = a.deletePolicyQuery(tx, ptype, 0, rule...).Exec(context.Background()); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	return tx.Commit()
 }
 
 // SavePolicy
@@ -0,0 +1,40 @@
+/*
+ * Copyright 2021 Meraj Sahebdar
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package dto
+
+import (
+	"bitban.io/server/internal/pkg/orm/entity"
+)
+
+// Collaborator
+type Collaborator struct {
+	User *User  `json:"user"`
+	Role string `json:"role"`
+}
+
+// CollaboratorFrom Returns an instance of dto: `Collaborator` from the user
+// and its role.
+func CollaboratorFrom(user *entity.User, role string) *Collaborator {
+	if user != nil {
+		return &Collaborator{
+			User: UserFrom(user),
+			Role: role,
+		}
+	}
+
+	return nil
+}
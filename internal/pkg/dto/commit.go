@@ -0,0 +1,67 @@
+/*
+ * Copyright 2021 Meraj Sahebdar
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package dto
+
+import (
+	"time"
+)
+
+// Signature
+type Signature struct {
+	Name  string    `json:"name"`
+	Email string    `json:"email"`
+	Date  time.Time `json:"date"`
+}
+
+// Commit
+type Commit struct {
+	Sha       string     `json:"sha"`
+	Author    *Signature `json:"author"`
+	Committer *Signature `json:"committer"`
+	Message   string     `json:"message"`
+	Parents   []string   `json:"parents"`
+}
+
+// CommitEdge
+type CommitEdge struct {
+	Cursor string  `json:"cursor"`
+	Node   *Commit `json:"node"`
+}
+
+// CommitConnection
+type CommitConnection struct {
+	Edges    []*CommitEdge `json:"edges"`
+	PageInfo *PageInfo     `json:"pageInfo"`
+}
+
+// CommitsInput
+type CommitsInput struct {
+	Ref    string `validate:"required"`
+	Path   string
+	Author string
+	Since  *time.Time
+	Until  *time.Time
+	First  int `validate:"min=1,max=100"`
+	After  *string
+}
+
+// BlameRange
+type BlameRange struct {
+	StartLine int     `json:"startLine"`
+	EndLine   int     `json:"endLine"`
+	Commit    *Commit `json:"commit"`
+}
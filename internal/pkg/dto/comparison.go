@@ -0,0 +1,39 @@
+/*
+ * Copyright 2021 Meraj Sahebdar
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package dto
+
+// FileDiff
+type FileDiff struct {
+	Path       string  `json:"path"`
+	OldPath    *string `json:"oldPath"`
+	Status     string  `json:"status"`
+	Additions  int     `json:"additions"`
+	Deletions  int     `json:"deletions"`
+	IsBinary   bool    `json:"isBinary"`
+	IsTooLarge bool    `json:"isTooLarge"`
+	Patch      *string `json:"patch"`
+}
+
+// Comparison
+type Comparison struct {
+	MergeBase   string      `json:"mergeBase"`
+	AheadBy     int         `json:"aheadBy"`
+	BehindBy    int         `json:"behindBy"`
+	Commits     []*Commit   `json:"commits"`
+	Files       []*FileDiff `json:"files"`
+	IsTruncated bool        `json:"isTruncated"`
+}
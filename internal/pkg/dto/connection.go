@@ -0,0 +1,56 @@
+/*
+ * Copyright 2021 Meraj Sahebdar
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package dto
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// cursorPrefix
+const cursorPrefix = "cursor:"
+
+// PageInfo
+type PageInfo struct {
+	HasNextPage     bool    `json:"hasNextPage"`
+	HasPreviousPage bool    `json:"hasPreviousPage"`
+	StartCursor     *string `json:"startCursor"`
+	EndCursor       *string `json:"endCursor"`
+}
+
+// ToCursor Returns an opaque cursor pointing to the offset within a connection.
+func ToCursor(offset int) string {
+	return base64.RawStdEncoding.EncodeToString(
+		[]byte(fmt.Sprintf("%s%d", cursorPrefix, offset)),
+	)
+}
+
+// FromCursor Returns the offset within a connection from its cursor.
+func FromCursor(cursor string) (offset int, err error) {
+	var byt []byte
+	if byt, err = base64.RawStdEncoding.DecodeString(cursor); err != nil {
+		return 0, err
+	}
+
+	if !strings.HasPrefix(string(byt), cursorPrefix) {
+		return 0, fmt.Errorf("invalid cursor: %s", cursor)
+	}
+
+	return strconv.Atoi(strings.TrimPrefix(string(byt), cursorPrefix))
+}
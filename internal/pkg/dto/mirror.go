@@ -0,0 +1,111 @@
+/*
+ * Copyright 2021 Meraj Sahebdar
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package dto
+
+import (
+	"net/url"
+	"time"
+
+	"github.com/volatiletech/null/v8"
+	"bitban.io/server/internal/pkg/orm/entity"
+)
+
+// PushMirrorNodeType
+const PushMirrorNodeType NodeType = "PushMirror"
+
+// PullMirror
+type PullMirror struct {
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+	URL       string    `json:"url"`
+	SyncedAt  null.Time `json:"syncedAt"`
+	SyncError *string   `json:"syncError"`
+}
+
+// redactURL Hides the password of the url, if any.
+func redactURL(rawURL string) string {
+	if u, err := url.Parse(rawURL); err != nil || u.User == nil {
+		return rawURL
+	} else if _, ok := u.User.Password(); !ok {
+		return rawURL
+	} else {
+		u.User = url.UserPassword(u.User.Username(), "xxxxx")
+		return u.String()
+	}
+}
+
+// PullMirrorFrom Returns an instance of dto: `PullMirror` from its entity.
+func PullMirrorFrom(mirror *entity.PullMirror) *PullMirror {
+	if mirror != nil {
+		return &PullMirror{
+			CreatedAt: mirror.CreatedAt,
+			UpdatedAt: mirror.UpdatedAt,
+			URL:       redactURL(mirror.URL),
+			SyncedAt:  mirror.SyncedAt,
+			SyncError: mirror.SyncError.Ptr(),
+		}
+	}
+
+	return nil
+}
+
+// PushMirror
+type PushMirror struct {
+	ID          string    `json:"id"`
+	CreatedAt   time.Time `json:"createdAt"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+	URL         string    `json:"url"`
+	Username    string    `json:"username"`
+	HasPassword bool      `json:"hasPassword"`
+	IsActive    bool      `json:"isActive"`
+	Attempts    int       `json:"attempts"`
+	PushedAt    null.Time `json:"pushedAt"`
+	PushError   *string   `json:"pushError"`
+}
+
+// IsNode
+func (PushMirror) IsNode() {}
+
+// PushMirrorFrom Returns an instance of dto: `PushMirror` from its entity.
+func PushMirrorFrom(mirror *entity.PushMirror) *PushMirror {
+	if mirror != nil {
+		return &PushMirror{
+			ID:          ToNodeIdentifier(PushMirrorNodeType, mirror.ID),
+			CreatedAt:   mirror.CreatedAt,
+			UpdatedAt:   mirror.UpdatedAt,
+			URL:         redactURL(mirror.URL),
+			Username:    mirror.Username,
+			HasPassword: mirror.Password != "",
+			IsActive:    mirror.IsActive,
+			Attempts:    mirror.Attempts,
+			PushedAt:    mirror.PushedAt,
+			PushError:   mirror.PushError.Ptr(),
+		}
+	}
+
+	return nil
+}
+
+// PushMirrorsFrom Returns a list of dto: `PushMirror` from their entities.
+func PushMirrorsFrom(mirrors []*entity.PushMirror) []*PushMirror {
+	ret := make([]*PushMirror, 0, len(mirrors))
+	for _, mirror := range mirrors {
+		ret = append(ret, PushMirrorFrom(mirror))
+	}
+
+	return ret
+}
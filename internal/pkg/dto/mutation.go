@@ -16,6 +16,8 @@
 
 package dto
 
+import "time"
+
 // SignInInput
 type SignInInput struct {
 	Password   string `json:"password" validate:"required"`
@@ -44,5 +46,190 @@ type SignUpPrimaryEmailInput struct {
 
 // CreateRepositoryInput
 type CreateRepositoryInput struct {
-	Address string `json:"address" validate:"required,notexistsin=repositories address"`
+	Address    string  `json:"address" validate:"required,max=250"`
+	Visibility string  `json:"visibility" validate:"required,oneof=public private"`
+	Owner      *string `json:"owner" validate:"omitempty,min=1"`
+}
+
+// AddSshKeyInput
+type AddSshKeyInput struct {
+	Title string `json:"title" validate:"required,max=250"`
+	Key   string `json:"key" validate:"required"`
+}
+
+// CreateBranchInput
+type CreateBranchInput struct {
+	RepositoryID string `json:"repositoryId" validate:"required"`
+	Name         string `json:"name" validate:"required,max=250"`
+	Ref          string `json:"ref" validate:"required"`
+}
+
+// DeleteBranchInput
+type DeleteBranchInput struct {
+	RepositoryID string `json:"repositoryId" validate:"required"`
+	Name         string `json:"name" validate:"required"`
+}
+
+// CreateTagInput
+type CreateTagInput struct {
+	RepositoryID string  `json:"repositoryId" validate:"required"`
+	Name         string  `json:"name" validate:"required,max=250"`
+	Ref          string  `json:"ref" validate:"required"`
+	Message      *string `json:"message"`
+}
+
+// DeleteTagInput
+type DeleteTagInput struct {
+	RepositoryID string `json:"repositoryId" validate:"required"`
+	Name         string `json:"name" validate:"required"`
+}
+
+// AddProtectedBranchInput
+type AddProtectedBranchInput struct {
+	RepositoryID   string   `json:"repositoryId" validate:"required"`
+	Pattern        string   `json:"pattern" validate:"required,max=250"`
+	BlockForcePush bool     `json:"blockForcePush"`
+	BlockDeletion  bool     `json:"blockDeletion"`
+	RestrictPush   bool     `json:"restrictPush"`
+	PusherIds      []string `json:"pusherIds"`
+}
+
+// UpdateProtectedBranchInput
+type UpdateProtectedBranchInput struct {
+	ID             string   `json:"id" validate:"required"`
+	Pattern        string   `json:"pattern" validate:"required,max=250"`
+	BlockForcePush bool     `json:"blockForcePush"`
+	BlockDeletion  bool     `json:"blockDeletion"`
+	RestrictPush   bool     `json:"restrictPush"`
+	PusherIds      []string `json:"pusherIds"`
+}
+
+// AddWebhookInput
+type AddWebhookInput struct {
+	RepositoryID string   `json:"repositoryId" validate:"required"`
+	URL          string   `json:"url" validate:"required,url,max=2048"`
+	Secret       string   `json:"secret" validate:"max=250"`
+	Events       []string `json:"events" validate:"dive,oneof=push"`
+	IsActive     bool     `json:"isActive"`
+}
+
+// UpdateWebhookInput
+type UpdateWebhookInput struct {
+	ID       string   `json:"id" validate:"required"`
+	URL      string   `json:"url" validate:"required,url,max=2048"`
+	Secret   *string  `json:"secret" validate:"omitempty,max=250"`
+	Events   []string `json:"events" validate:"dive,oneof=push"`
+	IsActive bool     `json:"isActive"`
+}
+
+// RenameRepositoryInput
+type RenameRepositoryInput struct {
+	ID      string `json:"id" validate:"required"`
+	Address string `json:"address" validate:"required,max=250"`
+}
+
+// TransferRepositoryInput
+type TransferRepositoryInput struct {
+	ID     string `json:"id" validate:"required"`
+	Domain string `json:"domain" validate:"required"`
+}
+
+// UpdateRepositoryVisibilityInput
+type UpdateRepositoryVisibilityInput struct {
+	ID         string `json:"id" validate:"required"`
+	Visibility string `json:"visibility" validate:"required,oneof=public private"`
+}
+
+// ForkRepositoryInput
+type ForkRepositoryInput struct {
+	ID      string  `json:"id" validate:"required"`
+	Domain  *string `json:"domain" validate:"omitempty,min=1"`
+	Address *string `json:"address" validate:"omitempty,min=1,max=250"`
+}
+
+// SetMirrorInput
+type SetMirrorInput struct {
+	ID  string `json:"id" validate:"required"`
+	URL string `json:"url" validate:"required,max=2048"`
+}
+
+// AddPushMirrorInput
+type AddPushMirrorInput struct {
+	RepositoryID string `json:"repositoryId" validate:"required"`
+	URL          string `json:"url" validate:"required,max=2048"`
+	Username     string `json:"username" validate:"max=250"`
+	Password     string `json:"password"`
+	IsActive     bool   `json:"isActive"`
+}
+
+// CollaboratorInput
+type CollaboratorInput struct {
+	RepositoryID string `json:"repositoryId" validate:"required"`
+	UserID       string `json:"userId" validate:"required"`
+	Role         string `json:"role" validate:"required,oneof=read write admin"`
+}
+
+// RemoveCollaboratorInput
+type RemoveCollaboratorInput struct {
+	RepositoryID string `json:"repositoryId" validate:"required"`
+	UserID       string `json:"userId" validate:"required"`
+}
+
+// CreateOrganizationInput
+type CreateOrganizationInput struct {
+	Name    string `json:"name" validate:"required,max=250"`
+	Address string `json:"address" validate:"required,max=250,notexistsin=domains address"`
+}
+
+// OrganizationMemberInput
+type OrganizationMemberInput struct {
+	OrganizationID string `json:"organizationId" validate:"required"`
+	UserID         string `json:"userId" validate:"required"`
+	Role           string `json:"role" validate:"required,oneof=owner member"`
+}
+
+// RemoveOrganizationMemberInput
+type RemoveOrganizationMemberInput struct {
+	OrganizationID string `json:"organizationId" validate:"required"`
+	UserID         string `json:"userId" validate:"required"`
+}
+
+// CreateTeamInput
+type CreateTeamInput struct {
+	OrganizationID string `json:"organizationId" validate:"required"`
+	Name           string `json:"name" validate:"required,max=250"`
+	Description    string `json:"description"`
+}
+
+// UpdateTeamInput
+type UpdateTeamInput struct {
+	TeamID      string `json:"teamId" validate:"required"`
+	Name        string `json:"name" validate:"required,max=250"`
+	Description string `json:"description"`
+}
+
+// TeamMemberInput
+type TeamMemberInput struct {
+	TeamID string `json:"teamId" validate:"required"`
+	UserID string `json:"userId" validate:"required"`
+}
+
+// TeamRepositoryInput
+type TeamRepositoryInput struct {
+	TeamID       string `json:"teamId" validate:"required"`
+	RepositoryID string `json:"repositoryId" validate:"required"`
+	Role         string `json:"role" validate:"required,oneof=read write admin"`
+}
+
+// RevokeTeamRepositoryInput
+type RevokeTeamRepositoryInput struct {
+	TeamID       string `json:"teamId" validate:"required"`
+	RepositoryID string `json:"repositoryId" validate:"required"`
+}
+
+// CreateAccessTokenInput
+type CreateAccessTokenInput struct {
+	Name      string     `json:"name" validate:"required,max=250"`
+	Scopes    []string   `json:"scopes" validate:"required,min=1,dive,oneof=repo:read repo:write api"`
+	ExpiresAt *time.Time `json:"expiresAt"`
 }
@@ -0,0 +1,80 @@
+/*
+ * Copyright 2021 Meraj Sahebdar
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package dto
+
+import (
+	"time"
+
+	"github.com/volatiletech/null/v8"
+	"bitban.io/server/internal/pkg/orm/entity"
+)
+
+// OrganizationNodeType
+const OrganizationNodeType NodeType = "Organization"
+
+// Organization
+type Organization struct {
+	ID        string    `json:"id"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+	RemovedAt null.Time `json:"removedAt"`
+	Name      string    `json:"name"`
+	Address   string    `json:"address"`
+}
+
+// IsNode
+func (Organization) IsNode() {}
+
+// OrganizationFrom Returns an instance of dto: `Organization` from its entity.
+func OrganizationFrom(organization *entity.Organization) *Organization {
+	if organization != nil {
+		ret := &Organization{
+			ID:        ToNodeIdentifier(OrganizationNodeType, organization.DomainID),
+			CreatedAt: organization.CreatedAt,
+			UpdatedAt: organization.UpdatedAt,
+			RemovedAt: organization.RemovedAt,
+		}
+
+		if organization.Domain != nil {
+			ret.Name = organization.Domain.Name
+			ret.Address = organization.Domain.Address
+		}
+
+		return ret
+	}
+
+	return nil
+}
+
+// OrganizationMember
+type OrganizationMember struct {
+	User *User  `json:"user"`
+	Role string `json:"role"`
+}
+
+// OrganizationMemberFrom Returns an instance of dto: `OrganizationMember` from
+// the user and its role.
+func OrganizationMemberFrom(user *entity.User, role string) *OrganizationMember {
+	if user != nil {
+		return &OrganizationMember{
+			User: UserFrom(user),
+			Role: role,
+		}
+	}
+
+	return nil
+}
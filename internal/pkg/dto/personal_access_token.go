@@ -0,0 +1,78 @@
+/*
+ * Copyright 2021 Meraj Sahebdar
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package dto
+
+import (
+	"time"
+
+	"github.com/volatiletech/null/v8"
+	"bitban.io/server/internal/pkg/orm/entity"
+)
+
+// PersonalAccessTokenNodeType
+const PersonalAccessTokenNodeType NodeType = "PersonalAccessToken"
+
+// PersonalAccessToken
+type PersonalAccessToken struct {
+	ID         string    `json:"id"`
+	CreatedAt  time.Time `json:"createdAt"`
+	UpdatedAt  time.Time `json:"updatedAt"`
+	RemovedAt  null.Time `json:"removedAt"`
+	Name       string    `json:"name"`
+	Scopes     []string  `json:"scopes"`
+	ExpiresAt  null.Time `json:"expiresAt"`
+	LastUsedAt null.Time `json:"lastUsedAt"`
+}
+
+// IsNode
+func (PersonalAccessToken) IsNode() {}
+
+// PersonalAccessTokenFrom Returns an instance of dto: `PersonalAccessToken`
+// from its entity.
+func PersonalAccessTokenFrom(token *entity.PersonalAccessToken) *PersonalAccessToken {
+	if token != nil {
+		return &PersonalAccessToken{
+			ID:         ToNodeIdentifier(PersonalAccessTokenNodeType, token.ID),
+			CreatedAt:  token.CreatedAt,
+			UpdatedAt:  token.UpdatedAt,
+			RemovedAt:  token.RemovedAt,
+			Name:       token.Name,
+			Scopes:     token.Scopes,
+			ExpiresAt:  token.ExpiresAt,
+			LastUsedAt: token.LastUsedAt,
+		}
+	}
+
+	return nil
+}
+
+// PersonalAccessTokensFrom Returns a list of dto: `PersonalAccessToken` from
+// their entities.
+func PersonalAccessTokensFrom(tokens []*entity.PersonalAccessToken) []*PersonalAccessToken {
+	ret := make([]*PersonalAccessToken, 0, len(tokens))
+	for _, token := range tokens {
+		ret = append(ret, PersonalAccessTokenFrom(token))
+	}
+
+	return ret
+}
+
+// CreatedAccessToken
+type CreatedAccessToken struct {
+	Token       string               `json:"token"`
+	AccessToken *PersonalAccessToken `json:"accessToken"`
+}
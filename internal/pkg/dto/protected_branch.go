@@ -0,0 +1,77 @@
+/*
+ * Copyright 2021 Meraj Sahebdar
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package dto
+
+import (
+	"time"
+
+	"github.com/volatiletech/null/v8"
+	"bitban.io/server/internal/pkg/orm/entity"
+)
+
+// ProtectedBranchNodeType
+const ProtectedBranchNodeType NodeType = "ProtectedBranch"
+
+// ProtectedBranch
+type ProtectedBranch struct {
+	ID             string    `json:"id"`
+	CreatedAt      time.Time `json:"createdAt"`
+	UpdatedAt      time.Time `json:"updatedAt"`
+	RemovedAt      null.Time `json:"removedAt"`
+	Pattern        string    `json:"pattern"`
+	BlockForcePush bool      `json:"blockForcePush"`
+	BlockDeletion  bool      `json:"blockDeletion"`
+	RestrictPush   bool      `json:"restrictPush"`
+	PusherIds      []string  `json:"pusherIds"`
+}
+
+// IsNode
+func (ProtectedBranch) IsNode() {}
+
+// ProtectedBranchFrom Returns an instance of dto: `ProtectedBranch` from its entity.
+func ProtectedBranchFrom(protectedBranch *entity.ProtectedBranch) *ProtectedBranch {
+	if protectedBranch != nil {
+		pusherIds := make([]string, 0, len(protectedBranch.PusherIDs))
+		for _, id := range protectedBranch.PusherIDs {
+			pusherIds = append(pusherIds, ToNodeIdentifier(UserNodeType, id))
+		}
+
+		return &ProtectedBranch{
+			ID:             ToNodeIdentifier(ProtectedBranchNodeType, protectedBranch.ID),
+			CreatedAt:      protectedBranch.CreatedAt,
+			UpdatedAt:      protectedBranch.UpdatedAt,
+			RemovedAt:      protectedBranch.RemovedAt,
+			Pattern:        protectedBranch.Pattern,
+			BlockForcePush: protectedBranch.BlockForcePush,
+			BlockDeletion:  protectedBranch.BlockDeletion,
+			RestrictPush:   protectedBranch.RestrictPush,
+			PusherIds:      pusherIds,
+		}
+	}
+
+	return nil
+}
+
+// ProtectedBranchesFrom Returns a list of dto: `ProtectedBranch` from their entities.
+func ProtectedBranchesFrom(protectedBranches []*entity.ProtectedBranch) []*ProtectedBranch {
+	ret := make([]*ProtectedBranch, 0, len(protectedBranches))
+	for _, protectedBranch := range protectedBranches {
+		ret = append(ret, ProtectedBranchFrom(protectedBranch))
+	}
+
+	return ret
+}
@@ -0,0 +1,98 @@
+/*
+ * Copyright 2021 Meraj Sahebdar
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package dto
+
+import (
+	"time"
+
+	"bitban.io/server/internal/pkg/orm/entity"
+)
+
+// PushEventNodeType
+const PushEventNodeType NodeType = "PushEvent"
+
+// PushEvent
+type PushEvent struct {
+	ID         string       `json:"id"`
+	CreatedAt  time.Time    `json:"createdAt"`
+	Protocol   string       `json:"protocol"`
+	RemoteAddr string       `json:"remoteAddr"`
+	Pusher     *User        `json:"pusher"`
+	Repository *Repository  `json:"repository"`
+	RefUpdates []*RefUpdate `json:"refUpdates"`
+}
+
+// IsNode
+func (PushEvent) IsNode() {}
+
+// PushEventFrom Returns an instance of dto: `PushEvent` from its entity.
+func PushEventFrom(pushEvent *entity.PushEvent) *PushEvent {
+	if pushEvent != nil {
+		ret := &PushEvent{
+			ID:         ToNodeIdentifier(PushEventNodeType, pushEvent.ID),
+			CreatedAt:  pushEvent.CreatedAt,
+			Protocol:   pushEvent.Protocol,
+			RemoteAddr: pushEvent.RemoteAddr,
+			Pusher:     UserFrom(pushEvent.Pusher),
+			Repository: RepositoryFrom(pushEvent.Repository),
+			RefUpdates: make([]*RefUpdate, 0, len(pushEvent.RefUpdates)),
+		}
+
+		for _, refUpdate := range pushEvent.RefUpdates {
+			ret.RefUpdates = append(ret.RefUpdates, &RefUpdate{
+				Ref:      refUpdate.Ref,
+				OldSha:   refUpdate.OldSha,
+				NewSha:   refUpdate.NewSha,
+				Action:   refUpdate.Action,
+				IsForced: refUpdate.IsForced,
+			})
+		}
+
+		return ret
+	}
+
+	return nil
+}
+
+// RefUpdate
+type RefUpdate struct {
+	Ref      string `json:"ref"`
+	OldSha   string `json:"oldSha"`
+	NewSha   string `json:"newSha"`
+	Action   string `json:"action"`
+	IsForced bool   `json:"isForced"`
+}
+
+// PushEventEdge
+type PushEventEdge struct {
+	Cursor string     `json:"cursor"`
+	Node   *PushEvent `json:"node"`
+}
+
+// PushEventConnection
+type PushEventConnection struct {
+	Edges    []*PushEventEdge `json:"edges"`
+	PageInfo *PageInfo        `json:"pageInfo"`
+}
+
+// ActivityInput
+type ActivityInput struct {
+	Ref        string
+	ForcedOnly bool
+	First      int `validate:"min=1,max=100"`
+	After      *string
+}
@@ -28,11 +28,12 @@ const RepositoryNodeType NodeType = "Repository"
 
 // Repository
 type Repository struct {
-	ID        string    `json:"id"`
-	CreatedAt time.Time `json:"createdAt"`
-	UpdatedAt time.Time `json:"updatedAt"`
-	RemovedAt null.Time `json:"removedAt"`
-	Address   string    `json:"address"`
+	ID         string    `json:"id"`
+	CreatedAt  time.Time `json:"createdAt"`
+	UpdatedAt  time.Time `json:"updatedAt"`
+	RemovedAt  null.Time `json:"removedAt"`
+	Address    string    `json:"address"`
+	Visibility string    `json:"visibility"`
 }
 
 // IsNode
@@ -42,11 +43,12 @@ func (Repository) IsNode() {}
 func RepositoryFrom(repository *entity.Repository) *Repository {
 	if repository != nil {
 		return &Repository{
-			ID:        ToNodeIdentifier(RepositoryNodeType, repository.ID),
-			CreatedAt: repository.CreatedAt,
-			UpdatedAt: repository.UpdatedAt,
-			RemovedAt: repository.RemovedAt,
-			Address:   repository.Address,
+			ID:         ToNodeIdentifier(RepositoryNodeType, repository.ID),
+			CreatedAt:  repository.CreatedAt,
+			UpdatedAt:  repository.UpdatedAt,
+			RemovedAt:  repository.RemovedAt,
+			Address:    repository.Address,
+			Visibility: repository.Visibility,
 		}
 	}
 
@@ -0,0 +1,70 @@
+/*
+ * Copyright 2021 Meraj Sahebdar
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package dto
+
+import (
+	"time"
+
+	"github.com/volatiletech/null/v8"
+	"bitban.io/server/internal/pkg/orm/entity"
+)
+
+// SshKeyNodeType
+const SshKeyNodeType NodeType = "SshKey"
+
+// SshKey
+type SshKey struct {
+	ID          string    `json:"id"`
+	CreatedAt   time.Time `json:"createdAt"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+	RemovedAt   null.Time `json:"removedAt"`
+	Title       string    `json:"title"`
+	Type        string    `json:"type"`
+	Fingerprint string    `json:"fingerprint"`
+	LastUsedAt  null.Time `json:"lastUsedAt"`
+}
+
+// IsNode
+func (SshKey) IsNode() {}
+
+// SshKeyFrom Returns an instance of dto: `SshKey` from its entity.
+func SshKeyFrom(sshKey *entity.SshKey) *SshKey {
+	if sshKey != nil {
+		return &SshKey{
+			ID:          ToNodeIdentifier(SshKeyNodeType, sshKey.ID),
+			CreatedAt:   sshKey.CreatedAt,
+			UpdatedAt:   sshKey.UpdatedAt,
+			RemovedAt:   sshKey.RemovedAt,
+			Title:       sshKey.Title,
+			Type:        sshKey.Type,
+			Fingerprint: sshKey.Fingerprint,
+			LastUsedAt:  sshKey.LastUsedAt,
+		}
+	}
+
+	return nil
+}
+
+// SshKeysFrom Returns a list of dto: `SshKey` from their entities.
+func SshKeysFrom(sshKeys []*entity.SshKey) []*SshKey {
+	ret := make([]*SshKey, 0, len(sshKeys))
+	for _, sshKey := range sshKeys {
+		ret = append(ret, SshKeyFrom(sshKey))
+	}
+
+	return ret
+}
@@ -0,0 +1,74 @@
+/*
+ * Copyright 2021 Meraj Sahebdar
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package dto
+
+import (
+	"time"
+
+	"bitban.io/server/internal/pkg/orm/entity"
+)
+
+// TeamNodeType
+const TeamNodeType NodeType = "Team"
+
+// Team
+type Team struct {
+	ID           string        `json:"id"`
+	CreatedAt    time.Time     `json:"createdAt"`
+	UpdatedAt    time.Time     `json:"updatedAt"`
+	Name         string        `json:"name"`
+	Description  string        `json:"description"`
+	Organization *Organization `json:"organization"`
+}
+
+// IsNode
+func (Team) IsNode() {}
+
+// TeamFrom Returns an instance of dto: `Team` from its entity.
+func TeamFrom(team *entity.Team) *Team {
+	if team != nil {
+		return &Team{
+			ID:           ToNodeIdentifier(TeamNodeType, team.ID),
+			CreatedAt:    team.CreatedAt,
+			UpdatedAt:    team.UpdatedAt,
+			Name:         team.Name,
+			Description:  team.Description,
+			Organization: OrganizationFrom(team.Organization),
+		}
+	}
+
+	return nil
+}
+
+// TeamRepository
+type TeamRepository struct {
+	Repository *Repository `json:"repository"`
+	Role       string      `json:"role"`
+}
+
+// TeamRepositoryFrom Returns an instance of dto: `TeamRepository` from the
+// repository and the role granted on it.
+func TeamRepositoryFrom(repository *entity.Repository, role string) *TeamRepository {
+	if repository != nil {
+		return &TeamRepository{
+			Repository: RepositoryFrom(repository),
+			Role:       role,
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,43 @@
+/*
+ * Copyright 2021 Meraj Sahebdar
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package dto
+
+// Blob Encodings
+const (
+	BlobEncodingUtf8   = "utf-8"
+	BlobEncodingBase64 = "base64"
+)
+
+// TreeEntry
+type TreeEntry struct {
+	Name string `json:"name"`
+	Path string `json:"path"`
+	Mode string `json:"mode"`
+	Type string `json:"type"`
+	Size *int64 `json:"size"`
+}
+
+// Blob
+type Blob struct {
+	Name       string `json:"name"`
+	Path       string `json:"path"`
+	Size       int64  `json:"size"`
+	IsBinary   bool   `json:"isBinary"`
+	IsTooLarge bool   `json:"isTooLarge"`
+	Encoding   string `json:"encoding"`
+	Content    string `json:"content"`
+}
@@ -0,0 +1,125 @@
+/*
+ * Copyright 2021 Meraj Sahebdar
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package dto
+
+import (
+	"time"
+
+	"github.com/volatiletech/null/v8"
+	"bitban.io/server/internal/pkg/orm/entity"
+)
+
+// WebhookNodeType
+const WebhookNodeType NodeType = "Webhook"
+
+// WebhookDeliveryNodeType
+const WebhookDeliveryNodeType NodeType = "WebhookDelivery"
+
+// Webhook
+type Webhook struct {
+	ID        string    `json:"id"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+	RemovedAt null.Time `json:"removedAt"`
+	URL       string    `json:"url"`
+	Events    []string  `json:"events"`
+	IsActive  bool      `json:"isActive"`
+	HasSecret bool      `json:"hasSecret"`
+}
+
+// IsNode
+func (Webhook) IsNode() {}
+
+// WebhookFrom Returns an instance of dto: `Webhook` from its entity.
+func WebhookFrom(webhook *entity.Webhook) *Webhook {
+	if webhook != nil {
+		return &Webhook{
+			ID:        ToNodeIdentifier(WebhookNodeType, webhook.ID),
+			CreatedAt: webhook.CreatedAt,
+			UpdatedAt: webhook.UpdatedAt,
+			RemovedAt: webhook.RemovedAt,
+			URL:       webhook.URL,
+			Events:    webhook.Events,
+			IsActive:  webhook.IsActive,
+			HasSecret: webhook.Secret != "",
+		}
+	}
+
+	return nil
+}
+
+// WebhooksFrom Returns a list of dto: `Webhook` from their entities.
+func WebhooksFrom(webhooks []*entity.Webhook) []*Webhook {
+	ret := make([]*Webhook, 0, len(webhooks))
+	for _, webhook := range webhooks {
+		ret = append(ret, WebhookFrom(webhook))
+	}
+
+	return ret
+}
+
+// WebhookDelivery
+type WebhookDelivery struct {
+	ID          string    `json:"id"`
+	CreatedAt   time.Time `json:"createdAt"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+	Event       string    `json:"event"`
+	Payload     string    `json:"payload"`
+	Attempts    int       `json:"attempts"`
+	StatusCode  *int      `json:"statusCode"`
+	Response    *string   `json:"response"`
+	Error       *string   `json:"error"`
+	DeliveredAt null.Time `json:"deliveredAt"`
+}
+
+// IsNode
+func (WebhookDelivery) IsNode() {}
+
+// WebhookDeliveryFrom Returns an instance of dto: `WebhookDelivery` from its entity.
+func WebhookDeliveryFrom(delivery *entity.WebhookDelivery) *WebhookDelivery {
+	if delivery != nil {
+		return &WebhookDelivery{
+			ID:          ToNodeIdentifier(WebhookDeliveryNodeType, delivery.ID),
+			CreatedAt:   delivery.CreatedAt,
+			UpdatedAt:   delivery.UpdatedAt,
+			Event:       delivery.Event,
+			Payload:     delivery.Payload,
+			Attempts:    delivery.Attempts,
+			StatusCode:  delivery.StatusCode.Ptr(),
+			Response:    delivery.Response.Ptr(),
+			Error:       delivery.Error.Ptr(),
+			DeliveredAt: delivery.DeliveredAt,
+		}
+	}
+
+	return nil
+}
+
+// WebhookDeliveriesFrom Returns a list of dto: `WebhookDelivery` from their entities.
+func WebhookDeliveriesFrom(deliveries []*entity.WebhookDelivery) []*WebhookDelivery {
+	ret := make([]*WebhookDelivery, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		ret = append(ret, WebhookDeliveryFrom(delivery))
+	}
+
+	return ret
+}
+
+// WebhookDeliveriesInput
+type WebhookDeliveriesInput struct {
+	First int `validate:"min=1,max=100"`
+}
@@ -18,6 +18,7 @@ package exec
 
 import (
 	"io"
+	"os"
 	goexec "os/exec"
 )
 
@@ -39,3 +40,29 @@ func Create(bin string, args ...string) (cmd *goexec.Cmd, stdin io.WriteCloser,
 
 	return cmd, stdin, stdout, stderr, nil
 }
+
+// Output Runs the command within the directory and returns its standard output.
+func Output(dir string, bin string, args ...string) ([]byte, error) {
+	cmd := goexec.Command(bin, args...)
+	cmd.Dir = dir
+
+	return cmd.Output()
+}
+
+// OutputFrom Same as `Output`, feeding the reader to the standard input of the command.
+func OutputFrom(r io.Reader, dir string, bin string, args ...string) ([]byte, error) {
+	cmd := goexec.Command(bin, args...)
+	cmd.Dir = dir
+	cmd.Stdin = r
+
+	return cmd.Output()
+}
+
+// OutputWithEnv Same as `Output`, appending the variables to the environment of the command.
+func OutputWithEnv(dir string, env []string, bin string, args ...string) ([]byte, error) {
+	cmd := goexec.Command(bin, args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), env...)
+
+	return cmd.Output()
+}
@@ -59,6 +59,27 @@ func (f *Account) GetDomain() *entity.Domain {
 	return f.user.Domain
 }
 
+// GetSignature Returns the identity of the account to be recorded in git objects.
+func (f *Account) GetSignature() (*Signature, error) {
+	email := new(entity.Email)
+	if err := orm.GetBunInstance().
+		NewSelect().
+		Model(email).
+		Where("? = ?", bun.Ident("email.user_id"), f.user.DomainID).
+		Where("? = ?", bun.Ident("email.is_primary"), true).
+		Where("? IS NULL", bun.Ident("email.removed_at")).
+		Limit(1).
+		Scan(f.ctx); err != nil {
+		return nil, err
+	}
+
+	return &Signature{
+		Name:  f.user.Domain.Name,
+		Email: email.Address,
+		When:  time.Now(),
+	}, nil
+}
+
 // CheckPermission
 //
 // Errors:
@@ -83,6 +104,23 @@ func (f *Account) CheckPermissionIn(dom string, obj string, act string) error {
 	return nil
 }
 
+// GrantOwnershipIn Allows the account to perform any action on the object and
+// its descendants within the domain.
+func (f *Account) GrantOwnershipIn(dom string, obj string) error {
+	sub := fmt.Sprintf("/users/%d", f.user.DomainID)
+	if _, err := auth.GetEnforcerInstance().AddNamedPolicies(
+		"p",
+		[][]string{
+			{sub, dom, obj, ".*"},
+			{sub, dom, fmt.Sprintf("%s/*", obj), ".*"},
+		},
+	); err != nil {
+		return err
+	}
+
+	return nil
+}
+
 // CreateAccessToken
 func (f *Account) CreateAccessToken() (accessToken string, err error) {
 	currTime := time.Now().In(time.UTC)
@@ -173,6 +211,43 @@ func GetAccountByPassword(ctx context.Context, input dto.SignInInput) (*Account,
 	}, nil
 }
 
+// GetAccountBySshKey Finds the account owning a public key using its SHA256 fingerprint.
+//
+// Errors:
+//   - fault.ErrUnauthenticated if was not able to find the corresponding account
+func GetAccountBySshKey(ctx context.Context, fingerprint string) (*Account, error) {
+	sshKey := new(entity.SshKey)
+	if err := orm.GetBunInstance().
+		NewSelect().
+		Model(sshKey).
+		Relation("User", func(sq *bun.SelectQuery) *bun.SelectQuery {
+			return sq.Where("? = ?", bun.Ident("user.is_active"), true).
+				Where("? = ?", bun.Ident("user.is_banned"), false).
+				Where("? IS NULL", bun.Ident("user.removed_at"))
+		}).
+		Relation("User.Domain", func(sq *bun.SelectQuery) *bun.SelectQuery {
+			return sq.Where("? IS NULL", bun.Ident("user__domain.removed_at"))
+		}).
+		Where("? = ?", bun.Ident("ssh_key.fingerprint"), fingerprint).
+		Where("? IS NULL", bun.Ident("ssh_key.removed_at")).
+		Limit(1).
+		Scan(ctx); fault.IsNonResourceNotFoundError(err) {
+		return nil, err
+	} else if fault.IsResourceNotFoundError(err) {
+		return nil, fault.ErrUnauthenticated
+	}
+
+	user := sshKey.User
+	if user == nil {
+		return nil, fault.ErrUnauthenticated
+	}
+
+	return &Account{
+		ctx:  ctx,
+		user: user,
+	}, nil
+}
+
 // GetAccountByUserId
 func GetAccountByUserId(ctx context.Context, id int64) (*Account, error) {
 	user := new(entity.User)
@@ -290,12 +365,41 @@ func CreateAccount(ctx context.Context, input dto.SignUpInput) (account *Account
 	return account, nil
 }
 
-// GetAccountByAccessToken
+// GetAccountByAccessToken Returns the account of the bearer token, which is
+// either an access token or a personal access token granted the api scope.
 //
 // ErrorsRef:
 //   - auth.GetContextAccessTokenClaims
+//   - facade.GetAccountByPersonalAccessToken
 //   - facade.GetAccountByUserId
 func GetAccountByAccessToken(ctx context.Context) (*Account, error) {
+	if token, ok := auth.GetContextPersonalAccessToken(ctx); ok {
+		return GetAccountByPersonalAccessToken(ctx, token, ScopeApi)
+	}
+
+	if claims, err := auth.GetContextAccessTokenClaims(ctx); err != nil {
+		return nil, err
+	} else {
+		return GetAccountByUserId(
+			ctx,
+			dto.MustRetrieveIdentifier(claims.Subject),
+		)
+	}
+}
+
+// GetAccountBySessionToken Returns the account of the bearer token, which must
+// be an access token of a session, not a personal access token.
+//
+// Errors:
+//   - auth.ErrInvalidJwtToken if the bearer token is a personal access token
+// ErrorsRef:
+//   - auth.GetContextAccessTokenClaims
+//   - facade.GetAccountByUserId
+func GetAccountBySessionToken(ctx context.Context) (*Account, error) {
+	if _, ok := auth.GetContextPersonalAccessToken(ctx); ok {
+		return nil, auth.ErrInvalidJwtToken
+	}
+
 	if claims, err := auth.GetContextAccessTokenClaims(ctx); err != nil {
 		return nil, err
 	} else {
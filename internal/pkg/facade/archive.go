@@ -0,0 +1,573 @@
+/*
+ * Copyright 2021 Meraj Sahebdar
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package facade
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+	"github.com/go-git/go-git/v5/plumbing/format/pktline"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/protocol/packp/sideband"
+	"bitban.io/server/internal/cfg"
+	"bitban.io/server/internal/pkg/exec"
+)
+
+// Archive Formats
+const (
+	ArchiveTar   = "tar"
+	ArchiveTarGz = "tar.gz"
+	ArchiveZip   = "zip"
+)
+
+var (
+	// ErrUnsupportedArchiveFormat
+	ErrUnsupportedArchiveFormat = errors.New("the requested archive format is not supported")
+
+	// ErrArchivePathNotFound
+	ErrArchivePathNotFound = errors.New("the requested path does not exist in the tree")
+)
+
+// ArchiveConfig
+type ArchiveConfig struct {
+	Format  string
+	Prefix  string
+	Treeish string
+	Paths   []string
+}
+
+// archiveSource
+type archiveSource struct {
+	// commit Is nil if the tree-ish does not name a commit, e.g. `rev:path`.
+	commit *object.Commit
+	tree   *object.Tree
+	paths  []string
+}
+
+// mtime Returns the modification time of the entries, which is the time of
+// archiving when there is no commit, as git does.
+func (s *archiveSource) mtime() time.Time {
+	if s.commit == nil {
+		return time.Now()
+	}
+
+	return s.commit.Committer.When
+}
+
+// includes Checks whether the entry must be written into the archive.
+func (s *archiveSource) includes(name string, isDir bool) bool {
+	if len(s.paths) == 0 {
+		return true
+	}
+
+	for _, path := range s.paths {
+		if name == path || strings.HasPrefix(name, path+"/") {
+			return true
+		}
+
+		// Keep the parent directories of the requested paths.
+		if isDir && strings.HasPrefix(path, name+"/") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// archiveEntry
+type archiveEntry struct {
+	name   string
+	mode   filemode.FileMode
+	size   int64
+	reader io.ReadCloser
+}
+
+// isSupportedArchiveFormat
+func isSupportedArchiveFormat(format string) bool {
+	switch format {
+	case ArchiveTar, ArchiveTarGz, ArchiveZip:
+		return true
+	}
+
+	return false
+}
+
+// resolveTreeish Resolves a commit, a tree id or `rev:path` to a tree, also
+// returning the commit if the tree-ish names one.
+//
+// Errors:
+//   - facade.ErrRevisionNotFound if the tree-ish does not point to any tree
+func (f *Repo) resolveTreeish(treeish string) (*object.Commit, *object.Tree, error) {
+	if i := strings.Index(treeish, ":"); i != -1 {
+		commit, err := f.resolveCommit(treeish[:i])
+		if err != nil {
+			return nil, nil, err
+		}
+
+		tree, err := commit.Tree()
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if path := strings.Trim(treeish[i+1:], "/"); path != "" {
+			if tree, err = tree.Tree(path); err != nil {
+				return nil, nil, ErrRevisionNotFound
+			}
+		}
+
+		return nil, tree, nil
+	}
+
+	if commit, err := f.resolveCommit(treeish); err == nil {
+		tree, err := commit.Tree()
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return commit, tree, nil
+	}
+
+	if plumbing.IsHash(treeish) {
+		if tree, err := f.repositoryInstance.TreeObject(plumbing.NewHash(treeish)); err == nil {
+			return nil, tree, nil
+		}
+	}
+
+	return nil, nil, ErrRevisionNotFound
+}
+
+// resolveArchiveSource
+//
+// Errors:
+//   - facade.ErrUnsupportedArchiveFormat if the format is unknown
+//   - facade.ErrRevisionNotFound if the tree-ish does not exist
+//   - facade.ErrArchivePathNotFound if any of the paths does not exist in the tree
+func (f *Repo) resolveArchiveSource(archiveConfig *ArchiveConfig) (*archiveSource, error) {
+	if !isSupportedArchiveFormat(archiveConfig.Format) {
+		return nil, ErrUnsupportedArchiveFormat
+	}
+
+	commit, tree, err := f.resolveTreeish(archiveConfig.Treeish)
+	if err != nil {
+		return nil, err
+	}
+
+	var paths []string
+	for _, path := range archiveConfig.Paths {
+		path = strings.Trim(path, "/")
+		if path == "" {
+			continue
+		}
+
+		if _, err := tree.FindEntry(path); err != nil {
+			return nil, ErrArchivePathNotFound
+		}
+
+		paths = append(paths, path)
+	}
+
+	return &archiveSource{
+		commit: commit,
+		tree:   tree,
+		paths:  paths,
+	}, nil
+}
+
+// walkArchiveSource Calls fn for every entry of the tree in the archive order.
+func (f *Repo) walkArchiveSource(src *archiveSource, fn func(entry *archiveEntry) error) error {
+	walker := object.NewTreeWalker(src.tree, true, nil)
+	defer walker.Close()
+
+	for {
+		name, treeEntry, err := walker.Next()
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		isDir := treeEntry.Mode == filemode.Dir || treeEntry.Mode == filemode.Submodule
+		if !src.includes(name, isDir) {
+			continue
+		}
+
+		entry := &archiveEntry{
+			name: name,
+			mode: treeEntry.Mode,
+		}
+
+		if !isDir {
+			blob, err := f.repositoryInstance.BlobObject(treeEntry.Hash)
+			if err != nil {
+				return err
+			}
+
+			if entry.reader, err = blob.Reader(); err != nil {
+				return err
+			}
+
+			entry.size = blob.Size
+		}
+
+		err = fn(entry)
+
+		if entry.reader != nil {
+			entry.reader.Close()
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// writeTarArchive
+func (f *Repo) writeTarArchive(w io.Writer, src *archiveSource, prefix string) error {
+	tw := tar.NewWriter(w)
+	mtime := src.mtime()
+
+	if src.commit != nil {
+		if err := tw.WriteHeader(&tar.Header{
+			Typeflag: tar.TypeXGlobalHeader,
+			Name:     "pax_global_header",
+			PAXRecords: map[string]string{
+				"comment": src.commit.Hash.String(),
+			},
+		}); err != nil {
+			return err
+		}
+	}
+
+	if err := f.walkArchiveSource(src, func(entry *archiveEntry) error {
+		hdr := &tar.Header{
+			Name:    prefix + entry.name,
+			ModTime: mtime,
+			Uname:   "root",
+			Gname:   "root",
+		}
+
+		switch entry.mode {
+		case filemode.Dir, filemode.Submodule:
+			hdr.Typeflag = tar.TypeDir
+			hdr.Name += "/"
+			hdr.Mode = 0775
+		case filemode.Symlink:
+			target, err := io.ReadAll(entry.reader)
+			if err != nil {
+				return err
+			}
+
+			hdr.Typeflag = tar.TypeSymlink
+			hdr.Linkname = string(target)
+			hdr.Mode = 0777
+		case filemode.Executable:
+			hdr.Typeflag = tar.TypeReg
+			hdr.Mode = 0775
+			hdr.Size = entry.size
+		default:
+			hdr.Typeflag = tar.TypeReg
+			hdr.Mode = 0664
+			hdr.Size = entry.size
+		}
+
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+
+		if hdr.Typeflag == tar.TypeReg {
+			if _, err := io.Copy(tw, entry.reader); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	return tw.Close()
+}
+
+// writeZipArchive
+func (f *Repo) writeZipArchive(w io.Writer, src *archiveSource, prefix string) error {
+	zw := zip.NewWriter(w)
+	mtime := src.mtime()
+
+	if src.commit != nil {
+		if err := zw.SetComment(src.commit.Hash.String()); err != nil {
+			return err
+		}
+	}
+
+	if err := f.walkArchiveSource(src, func(entry *archiveEntry) error {
+		hdr := &zip.FileHeader{
+			Name:     prefix + entry.name,
+			Method:   zip.Deflate,
+			Modified: mtime,
+		}
+
+		switch entry.mode {
+		case filemode.Dir, filemode.Submodule:
+			hdr.Name += "/"
+			hdr.Method = zip.Store
+			hdr.SetMode(os.ModeDir | 0775)
+		case filemode.Symlink:
+			hdr.Method = zip.Store
+			hdr.SetMode(os.ModeSymlink | 0777)
+		case filemode.Executable:
+			hdr.SetMode(0775)
+		default:
+			hdr.SetMode(0664)
+		}
+
+		fw, err := zw.CreateHeader(hdr)
+		if err != nil {
+			return err
+		}
+
+		if entry.reader != nil {
+			if _, err := io.Copy(fw, entry.reader); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	return zw.Close()
+}
+
+// writeArchive Writes the archive of an already resolved source.
+func (f *Repo) writeArchive(w io.Writer, src *archiveSource, archiveConfig *ArchiveConfig) error {
+	switch archiveConfig.Format {
+	case ArchiveTar:
+		return f.writeTarArchive(w, src, archiveConfig.Prefix)
+	case ArchiveTarGz:
+		gw := gzip.NewWriter(w)
+		if err := f.writeTarArchive(gw, src, archiveConfig.Prefix); err != nil {
+			return err
+		}
+
+		return gw.Close()
+	case ArchiveZip:
+		return f.writeZipArchive(w, src, archiveConfig.Prefix)
+	}
+
+	return ErrUnsupportedArchiveFormat
+}
+
+// CheckArchive Checks whether an archive can be created using the config,
+// without writing anything.
+//
+// ErrorsRef:
+//   - facade.Repo.resolveArchiveSource
+func (f *Repo) CheckArchive(archiveConfig *ArchiveConfig) error {
+	if cfg.IsGoBackend() {
+		_, err := f.resolveArchiveSource(archiveConfig)
+		return err
+	} else {
+		if !isSupportedArchiveFormat(archiveConfig.Format) {
+			return ErrUnsupportedArchiveFormat
+		}
+
+		if strings.HasPrefix(archiveConfig.Treeish, "-") {
+			return ErrRevisionNotFound
+		}
+
+		// Resolve the object before peeling it, as `rev:path` takes everything
+		// after the colon as the path.
+		out, err := exec.Output(
+			f.path,
+			"git",
+			"rev-parse",
+			"--verify",
+			"--quiet",
+			archiveConfig.Treeish,
+		)
+		if err != nil {
+			return ErrRevisionNotFound
+		}
+
+		if out, err = exec.Output(
+			f.path,
+			"git",
+			"rev-parse",
+			"--verify",
+			"--quiet",
+			fmt.Sprintf("%s^{tree}", strings.TrimSpace(string(out))),
+		); err != nil {
+			return ErrRevisionNotFound
+		}
+
+		tree := strings.TrimSpace(string(out))
+		for _, path := range archiveConfig.Paths {
+			if _, err := exec.Output(
+				f.path,
+				"git",
+				"cat-file",
+				"-e",
+				fmt.Sprintf("%s:%s", tree, strings.Trim(path, "/")),
+			); err != nil {
+				return ErrArchivePathNotFound
+			}
+		}
+
+		return nil
+	}
+}
+
+// WriteArchive Writes an archive of the tree-ish in the requested format.
+//
+// ErrorsRef:
+//   - facade.Repo.CheckArchive
+func (f *Repo) WriteArchive(w io.Writer, archiveConfig *ArchiveConfig) error {
+	if cfg.IsGoBackend() {
+		if src, err := f.resolveArchiveSource(archiveConfig); err != nil {
+			return err
+		} else {
+			return f.writeArchive(w, src, archiveConfig)
+		}
+	} else {
+		if err := f.CheckArchive(archiveConfig); err != nil {
+			return err
+		}
+
+		args := []string{
+			"archive",
+			fmt.Sprintf("--format=%s", archiveConfig.Format),
+		}
+
+		if archiveConfig.Prefix != "" {
+			args = append(args, fmt.Sprintf("--prefix=%s", archiveConfig.Prefix))
+		}
+
+		args = append(args, archiveConfig.Treeish, "--")
+		args = append(args, archiveConfig.Paths...)
+
+		if cmd, _, stdout, _, err := exec.Create(
+			"git",
+			args...,
+		); err != nil {
+			return err
+		} else {
+			cmd.Dir = f.path
+
+			if err := cmd.Start(); err != nil {
+				return err
+			}
+
+			io.Copy(w, stdout)
+
+			return cmd.Wait()
+		}
+	}
+}
+
+// parseUploadArchiveArgs Parses arguments sent by `git archive --remote`.
+func parseUploadArchiveArgs(args []string) (*ArchiveConfig, error) {
+	archiveConfig := &ArchiveConfig{
+		Format: ArchiveTar,
+	}
+
+	for _, arg := range args {
+		switch {
+		case strings.HasPrefix(arg, "--format="):
+			archiveConfig.Format = strings.TrimPrefix(arg, "--format=")
+		case strings.HasPrefix(arg, "--prefix="):
+			archiveConfig.Prefix = strings.TrimPrefix(arg, "--prefix=")
+		case arg == "--":
+			continue
+		case strings.HasPrefix(arg, "-"):
+			return nil, fmt.Errorf("unsupported archive option: %s", arg)
+		case archiveConfig.Treeish == "":
+			archiveConfig.Treeish = arg
+		default:
+			archiveConfig.Paths = append(archiveConfig.Paths, arg)
+		}
+	}
+
+	if archiveConfig.Treeish == "" {
+		return nil, errors.New("no tree-ish was specified")
+	}
+
+	if archiveConfig.Format == "tgz" {
+		archiveConfig.Format = ArchiveTarGz
+	}
+
+	return archiveConfig, nil
+}
+
+// serveUploadArchive Implements `git-upload-archive` service.
+func (f *Repo) serveUploadArchive(r io.Reader, w io.Writer) error {
+	var args []string
+
+	scanner := pktline.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			break
+		}
+
+		args = append(args, strings.TrimPrefix(strings.TrimSuffix(string(line), "\n"), "argument "))
+	}
+
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+
+	enc := pktline.NewEncoder(w)
+
+	var src *archiveSource
+	archiveConfig, err := parseUploadArchiveArgs(args)
+	if err == nil {
+		src, err = f.resolveArchiveSource(archiveConfig)
+	}
+
+	if err != nil {
+		if err := enc.Encodef("NACK %s\n", err.Error()); err != nil {
+			return err
+		}
+
+		return enc.Flush()
+	}
+
+	if err := enc.EncodeString("ACK\n"); err != nil {
+		return err
+	}
+
+	if err := enc.Flush(); err != nil {
+		return err
+	}
+
+	mux := sideband.NewMuxer(sideband.Sideband64k, w)
+	if err := f.writeArchive(mux, src, archiveConfig); err != nil {
+		mux.WriteChannel(sideband.ErrorMessage, []byte(err.Error()))
+	}
+
+	return enc.Flush()
+}
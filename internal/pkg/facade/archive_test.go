@@ -0,0 +1,195 @@
+/*
+ * Copyright 2021 Meraj Sahebdar
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package facade
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"io"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/go-git/go-git/v5/plumbing/format/pktline"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+func TestParseUploadArchiveArgs(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		archiveConfig, err := parseUploadArchiveArgs([]string{"--format=tgz", "--prefix=repo/", "main", "--", "docs", "README.md"})
+		if err != nil {
+			t.Fatalf("got an unexpected error: %s", err.Error())
+		}
+
+		if archiveConfig.Format != ArchiveTarGz || archiveConfig.Prefix != "repo/" || archiveConfig.Treeish != "main" {
+			t.Errorf("expected tar.gz of main prefixed by repo/, got: %+v", archiveConfig)
+		}
+
+		if strings.Join(archiveConfig.Paths, " ") != "docs README.md" {
+			t.Errorf("expected the paths after the tree-ish, got: %v", archiveConfig.Paths)
+		}
+	})
+
+	t.Run("default format", func(t *testing.T) {
+		if archiveConfig, err := parseUploadArchiveArgs([]string{"main"}); err != nil {
+			t.Errorf("got an unexpected error: %s", err.Error())
+		} else if archiveConfig.Format != ArchiveTar {
+			t.Errorf("expected format %s, got: %s", ArchiveTar, archiveConfig.Format)
+		}
+	})
+
+	t.Run("unsupported option", func(t *testing.T) {
+		if _, err := parseUploadArchiveArgs([]string{"--exec=sh", "main"}); err == nil {
+			t.Errorf("expected an error for an unsupported option")
+		}
+	})
+
+	t.Run("no tree-ish", func(t *testing.T) {
+		if _, err := parseUploadArchiveArgs([]string{"--format=zip"}); err == nil {
+			t.Errorf("expected an error without a tree-ish")
+		}
+	})
+}
+
+func TestUploadArchive(t *testing.T) {
+	ctx := context.Background()
+	repo := createTestRepo(t, ctx)
+
+	head := commitTestFiles(t, repo, "main", "initial", map[string]string{
+		"README.md":     "hello\n",
+		"docs/guide.md": "guide\n",
+	})
+
+	commit, err := object.GetCommit(repo.storage, head)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tree, err := commit.Tree()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// serve Sends the arguments and returns the status line and the entries of
+	// the archive.
+	serve := func(t *testing.T, args ...string) (string, []string) {
+		t.Helper()
+
+		in := &bytes.Buffer{}
+		enc := pktline.NewEncoder(in)
+		for _, arg := range args {
+			if err := enc.Encodef("argument %s\n", arg); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := enc.Flush(); err != nil {
+			t.Fatal(err)
+		}
+
+		out := &bytes.Buffer{}
+		if err := repo.serveUploadArchive(in, out); err != nil {
+			t.Fatalf("got an unexpected error: %s", err.Error())
+		}
+
+		p := &pktReader{r: out}
+
+		_, status, err := p.next()
+		if err != nil {
+			t.Fatalf("failed to read the status: %s", err.Error())
+		}
+
+		if kind, _, err := p.next(); err != nil || kind != pktFlush {
+			t.Fatalf("expected a flush after the status, got: %d %v", kind, err)
+		}
+
+		var archive []byte
+		for {
+			kind, payload, err := p.next()
+			if err == io.EOF {
+				break
+			} else if err != nil {
+				t.Fatalf("failed to read the archive: %s", err.Error())
+			} else if kind == pktData && payload[0] == 1 {
+				archive = append(archive, payload[1:]...)
+			}
+		}
+
+		var names []string
+		if len(archive) > 0 {
+			tr := tar.NewReader(bytes.NewReader(archive))
+			for {
+				hdr, err := tr.Next()
+				if err == io.EOF {
+					break
+				} else if err != nil {
+					t.Fatalf("failed to read the tar: %s", err.Error())
+				}
+
+				if hdr.Typeflag != tar.TypeXGlobalHeader {
+					names = append(names, hdr.Name)
+				}
+			}
+		}
+		sort.Strings(names)
+
+		return strings.TrimSuffix(string(status), "\n"), names
+	}
+
+	for _, c := range []struct {
+		name     string
+		args     []string
+		expected []string
+	}{
+		{"branch", []string{"main"}, []string{"README.md", "docs/", "docs/guide.md"}},
+		{"paths", []string{"--prefix=repo/", "main", "docs"}, []string{"repo/docs/", "repo/docs/guide.md"}},
+		{"tree id", []string{tree.Hash.String()}, []string{"README.md", "docs/", "docs/guide.md"}},
+		{"rev:path", []string{"main:docs"}, []string{"guide.md"}},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			status, names := serve(t, c.args...)
+			if status != "ACK" {
+				t.Fatalf("expected ACK, got: %s", status)
+			}
+
+			if strings.Join(names, " ") != strings.Join(c.expected, " ") {
+				t.Errorf("expected entries %v, got: %v", c.expected, names)
+			}
+		})
+	}
+
+	for _, c := range []struct {
+		name string
+		args []string
+	}{
+		{"unsupported option", []string{"--remote=elsewhere", "main"}},
+		{"unknown revision", []string{"unknown"}},
+		{"unknown path", []string{"main", "missing"}},
+		{"blob path", []string{"main:README.md"}},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			status, names := serve(t, c.args...)
+			if !strings.HasPrefix(status, "NACK ") {
+				t.Errorf("expected NACK, got: %s", status)
+			}
+
+			if len(names) != 0 {
+				t.Errorf("expected no archive, got: %v", names)
+			}
+		})
+	}
+}
@@ -0,0 +1,202 @@
+/*
+ * Copyright 2021 Meraj Sahebdar
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package facade
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-redis/cache/v8"
+	"bitban.io/server/internal/cfg"
+	"bitban.io/server/internal/pkg/exec"
+	mem "bitban.io/server/internal/pkg/rdb"
+)
+
+// blameCacheTTL Blames are immutable for a commit and path, the TTL only bounds memory.
+const blameCacheTTL = 24 * time.Hour
+
+// BlameRange Consecutive lines of a file attributed to a commit.
+type BlameRange struct {
+	StartLine int
+	EndLine   int
+	Commit    *Commit
+}
+
+// appendBlameLine Extends the last range or starts a new one for the line.
+func appendBlameLine(ranges []*BlameRange, line int, hash string) []*BlameRange {
+	if n := len(ranges); n > 0 && ranges[n-1].Commit.Hash == hash && ranges[n-1].EndLine == line-1 {
+		ranges[n-1].EndLine = line
+		return ranges
+	}
+
+	return append(ranges, &BlameRange{
+		StartLine: line,
+		EndLine:   line,
+		Commit:    &Commit{Hash: hash},
+	})
+}
+
+// blameByGo
+func (f *Repo) blameByGo(hash string, path string) ([]*BlameRange, error) {
+	commit, err := f.repositoryInstance.CommitObject(plumbing.NewHash(hash))
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := commit.File(path); err != nil {
+		return nil, ErrPathNotFound
+	}
+
+	result, err := git.Blame(commit, path)
+	if err != nil {
+		return nil, err
+	}
+
+	var ranges []*BlameRange
+	for i, line := range result.Lines {
+		ranges = appendBlameLine(ranges, i+1, line.Hash.String())
+	}
+
+	commits := map[string]*Commit{}
+	for _, r := range ranges {
+		c, ok := commits[r.Commit.Hash]
+		if !ok {
+			if obj, err := f.repositoryInstance.CommitObject(plumbing.NewHash(r.Commit.Hash)); err != nil {
+				return nil, err
+			} else {
+				c = commitFrom(obj)
+				commits[r.Commit.Hash] = c
+			}
+		}
+
+		r.Commit = c
+	}
+
+	return ranges, nil
+}
+
+// blameByBin
+func (f *Repo) blameByBin(hash string, path string) ([]*BlameRange, error) {
+	out, err := exec.Output(
+		f.path,
+		"git",
+		"blame",
+		"--porcelain",
+		hash,
+		"--",
+		path,
+	)
+	if err != nil {
+		return nil, ErrPathNotFound
+	}
+
+	var ranges []*BlameRange
+	var hashes []string
+	seen := map[string]bool{}
+
+	for _, line := range strings.Split(string(out), "\n") {
+		// <hash> <original line> <final line> [<lines in group>]
+		fields := strings.Fields(line)
+		if len(fields) < 3 || len(fields[0]) != 40 || strings.HasPrefix(line, "\t") {
+			continue
+		}
+
+		final, err := strconv.Atoi(fields[2])
+		if err != nil {
+			continue
+		}
+
+		ranges = appendBlameLine(ranges, final, fields[0])
+
+		if !seen[fields[0]] {
+			seen[fields[0]] = true
+			hashes = append(hashes, fields[0])
+		}
+	}
+
+	if len(hashes) == 0 {
+		return ranges, nil
+	}
+
+	args := append([]string{
+		"show",
+		"-s",
+		"-z",
+		fmt.Sprintf("--format=%s", commitLogFormat),
+	}, hashes...)
+
+	if out, err = exec.Output(f.path, "git", args...); err != nil {
+		return nil, err
+	}
+
+	list, err := parseCommitLog(out)
+	if err != nil {
+		return nil, err
+	}
+
+	commits := map[string]*Commit{}
+	for _, c := range list {
+		commits[c.Hash] = c
+	}
+
+	for _, r := range ranges {
+		if c, ok := commits[r.Commit.Hash]; ok {
+			r.Commit = c
+		}
+	}
+
+	return ranges, nil
+}
+
+// Blame Returns the line ranges of the file attributed to the commits that last changed them.
+//
+// Results are cached by the commit of the revision and the path.
+//
+// Errors:
+//   - facade.ErrRevisionNotFound if the revision does not point to any commit
+//   - facade.ErrPathNotFound if the path is not a file in the revision
+func (f *Repo) Blame(rev string, path string) ([]*BlameRange, error) {
+	path = cleanPath(path)
+
+	hash, err := f.resolveCommitHash(rev)
+	if err != nil {
+		return nil, err
+	}
+
+	var ranges []*BlameRange
+	if err := mem.GetCacheInstance().Once(&cache.Item{
+		Ctx:   f.ctx,
+		Key:   fmt.Sprintf("blame:%s:%s", hash, path),
+		Value: &ranges,
+		TTL:   blameCacheTTL,
+		Do: func(*cache.Item) (interface{}, error) {
+			if cfg.IsGoBackend() {
+				return f.blameByGo(hash, path)
+			} else {
+				return f.blameByBin(hash, path)
+			}
+		},
+	}); err != nil {
+		return nil, err
+	}
+
+	return ranges, nil
+}
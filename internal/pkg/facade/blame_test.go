@@ -0,0 +1,107 @@
+/*
+ * Copyright 2021 Meraj Sahebdar
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package facade
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestAppendBlameLine(t *testing.T) {
+	var ranges []*BlameRange
+	for i, hash := range []string{"a", "a", "b", "a"} {
+		ranges = appendBlameLine(ranges, i+1, hash)
+	}
+
+	if len(ranges) != 3 || ranges[0].EndLine != 2 || ranges[2].StartLine != 4 {
+		t.Errorf("expected ranges [1-2, 3-3, 4-4], got %d ranges", len(ranges))
+	}
+}
+
+func TestBlameByGo(t *testing.T) {
+	ctx := context.Background()
+	repo := createTestRepo(t, ctx)
+
+	c0 := commitTestFiles(t, repo, "main", "c0", map[string]string{"f.txt": "one\ntwo\n"})
+	c1 := commitTestFiles(t, repo, "main", "c1", map[string]string{"f.txt": "one\ntwo\nthree\n"})
+	c2 := commitTestFiles(t, repo, "main", "c2", map[string]string{"f.txt": "zero\none\nTWO\nthree\nfour\n"})
+	c3 := commitTestFiles(t, repo, "main", "c3", map[string]string{"g.txt": "other\n"})
+
+	// describe Formats the ranges as `start-end:message`.
+	describe := func(ranges []*BlameRange) string {
+		var ret []string
+		for _, r := range ranges {
+			ret = append(ret, fmt.Sprintf("%d-%d:%s", r.StartLine, r.EndLine, r.Commit.Message))
+		}
+
+		return strings.Join(ret, " ")
+	}
+
+	for _, c := range []struct {
+		name     string
+		hash     string
+		expected string
+	}{
+		{"initial", c0.String(), "1-2:c0"},
+		{"appended", c1.String(), "1-2:c0 3-3:c1"},
+		{"changed", c2.String(), "1-1:c2 2-2:c0 3-3:c2 4-4:c1 5-5:c2"},
+		{"untouched by the commit", c3.String(), "1-1:c2 2-2:c0 3-3:c2 4-4:c1 5-5:c2"},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			ranges, err := repo.blameByGo(c.hash, "f.txt")
+			if err != nil {
+				t.Fatalf("got an unexpected error: %s", err.Error())
+			}
+
+			if got := describe(ranges); got != c.expected {
+				t.Errorf("expected %q, got: %q", c.expected, got)
+			}
+		})
+	}
+
+	t.Run("commit details", func(t *testing.T) {
+		ranges, err := repo.blameByGo(c2.String(), "f.txt")
+		if err != nil {
+			t.Fatalf("got an unexpected error: %s", err.Error())
+		}
+
+		if commit := ranges[1].Commit; commit.Hash != c0.String() || commit.Author.Email != "bitban@bitban.io" || len(commit.Parents) != 0 {
+			t.Errorf("expected the details of c0, got: %+v", commit)
+		}
+
+		// The ranges of a commit share its details.
+		if ranges[0].Commit != ranges[2].Commit {
+			t.Errorf("expected the ranges of c2 to share the commit")
+		}
+	})
+
+	t.Run("missing path", func(t *testing.T) {
+		if _, err := repo.blameByGo(c3.String(), "missing.txt"); err != ErrPathNotFound {
+			t.Errorf("expected error: %v, got: %v", ErrPathNotFound, err)
+		}
+	})
+
+	t.Run("directory", func(t *testing.T) {
+		c4 := commitTestFiles(t, repo, "main", "c4", map[string]string{"docs/a.md": "a\n"})
+
+		if _, err := repo.blameByGo(c4.String(), "docs"); err != ErrPathNotFound {
+			t.Errorf("expected error: %v, got: %v", ErrPathNotFound, err)
+		}
+	})
+}
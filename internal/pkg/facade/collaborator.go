@@ -0,0 +1,250 @@
+/*
+ * Copyright 2021 Meraj Sahebdar
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package facade
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"bitban.io/server/internal/pkg/auth"
+	"bitban.io/server/internal/pkg/fault"
+)
+
+// Collaborator Roles
+const (
+	CollaboratorRoleRead  = "read"
+	CollaboratorRoleWrite = "write"
+	CollaboratorRoleAdmin = "admin"
+)
+
+// collaboratorRoleActs Maps the collaborator roles to the actions they allow
+// on the repository, each including the ones of the roles before it.
+var collaboratorRoleActs = []struct {
+	role string
+	act  string
+}{
+	{CollaboratorRoleRead, "^read$"},
+	{CollaboratorRoleWrite, "^(read|write)$"},
+	{CollaboratorRoleAdmin, "^(read|write|admin)$"},
+}
+
+var (
+	// ErrInvalidCollaboratorRole
+	ErrInvalidCollaboratorRole = errors.New("the collaborator role must be read, write or admin")
+
+	// ErrCollaboratorIsOwner
+	ErrCollaboratorIsOwner = errors.New("the owner of the repository can not be a collaborator")
+
+	// ErrCollaboratorExists
+	ErrCollaboratorExists = errors.New("the user is already a collaborator of the repository")
+)
+
+// Collaborator
+type Collaborator struct {
+	Account *Account
+	Role    string
+}
+
+// isCollaboratorRole
+func isCollaboratorRole(role string) bool {
+	for _, r := range collaboratorRoleActs {
+		if r.role == role {
+			return true
+		}
+	}
+
+	return false
+}
+
+// collaboratorRoleSubject Returns the casbin subject of a role on a repository,
+// which the collaborators are grouped into.
+func collaboratorRoleSubject(repoID int64, role string) string {
+	return fmt.Sprintf("/repositories/%d/roles/%s", repoID, role)
+}
+
+// grantCollaboratorRoles Adds the policies of the collaborator roles on the
+// repository, unless they are already there.
+func (f *Repo) grantCollaboratorRoles() error {
+	enforcer := auth.GetEnforcerInstance()
+
+	obj := fmt.Sprintf("/repositories/%d", f.GetID())
+	for _, r := range collaboratorRoleActs {
+		if _, err := enforcer.AddNamedPolicy(
+			"p",
+			collaboratorRoleSubject(f.GetID(), r.role),
+			f.GetDomainAddress(),
+			obj,
+			r.act,
+		); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// isOwnedBy Checks whether the repository belongs to the domain of the account.
+func (f *Repo) isOwnedBy(account *Account) bool {
+	return f.repositoryEntity.DomainID.Int64 == account.GetUser().DomainID
+}
+
+// GetCollaborators Returns the collaborators of the repository along with
+// their roles, the earliest registered users first.
+func (f *Repo) GetCollaborators() ([]*Collaborator, error) {
+	enforcer := auth.GetEnforcerInstance()
+
+	collaborators := []*Collaborator{}
+	for _, r := range collaboratorRoleActs {
+		for _, rule := range enforcer.GetFilteredNamedGroupingPolicy(
+			"g",
+			1,
+			collaboratorRoleSubject(f.GetID(), r.role),
+			f.GetDomainAddress(),
+		) {
+			id, err := strconv.ParseInt(strings.TrimPrefix(rule[0], "/users/"), 10, 64)
+			if err != nil {
+				continue
+			}
+
+			if account, err := GetAccountByUserId(f.ctx, id); err == fault.ErrResourceNotFound {
+				continue
+			} else if err != nil {
+				return nil, err
+			} else {
+				collaborators = append(collaborators, &Collaborator{
+					Account: account,
+					Role:    r.role,
+				})
+			}
+		}
+	}
+
+	sort.Slice(collaborators, func(i, j int) bool {
+		return collaborators[i].Account.GetUser().DomainID < collaborators[j].Account.GetUser().DomainID
+	})
+
+	return collaborators, nil
+}
+
+// GetCollaborator Returns the role of the account on the repository.
+//
+// Errors:
+//   - fault.ErrResourceNotFound if the account is not a collaborator
+func (f *Repo) GetCollaborator(account *Account) (*Collaborator, error) {
+	enforcer := auth.GetEnforcerInstance()
+
+	sub := fmt.Sprintf("/users/%d", account.GetUser().DomainID)
+	for _, r := range collaboratorRoleActs {
+		if rules := enforcer.GetFilteredNamedGroupingPolicy(
+			"g",
+			0,
+			sub,
+			collaboratorRoleSubject(f.GetID(), r.role),
+			f.GetDomainAddress(),
+		); len(rules) > 0 {
+			return &Collaborator{
+				Account: account,
+				Role:    r.role,
+			}, nil
+		}
+	}
+
+	return nil, fault.ErrResourceNotFound
+}
+
+// AddCollaborator Grants the role on the repository to the account.
+//
+// Errors:
+//   - facade.ErrInvalidCollaboratorRole if the role is unknown
+//   - facade.ErrCollaboratorIsOwner if the account owns the repository
+//   - facade.ErrCollaboratorExists if the account is already a collaborator
+func (f *Repo) AddCollaborator(account *Account, role string) (*Collaborator, error) {
+	if !isCollaboratorRole(role) {
+		return nil, ErrInvalidCollaboratorRole
+	}
+
+	if f.isOwnedBy(account) {
+		return nil, ErrCollaboratorIsOwner
+	}
+
+	if _, err := f.GetCollaborator(account); err == nil {
+		return nil, ErrCollaboratorExists
+	} else if err != fault.ErrResourceNotFound {
+		return nil, err
+	}
+
+	if err := f.grantCollaboratorRoles(); err != nil {
+		return nil, err
+	}
+
+	if _, err := auth.GetEnforcerInstance().AddNamedGroupingPolicy(
+		"g",
+		fmt.Sprintf("/users/%d", account.GetUser().DomainID),
+		collaboratorRoleSubject(f.GetID(), role),
+		f.GetDomainAddress(),
+	); err != nil {
+		return nil, err
+	}
+
+	return &Collaborator{
+		Account: account,
+		Role:    role,
+	}, nil
+}
+
+// UpdateCollaboratorRole Replaces the role of a collaborator on the repository.
+//
+// Errors:
+//   - facade.ErrInvalidCollaboratorRole if the role is unknown
+// ErrorsRef:
+//   - facade.Repo.RemoveCollaborator
+func (f *Repo) UpdateCollaboratorRole(account *Account, role string) (*Collaborator, error) {
+	if !isCollaboratorRole(role) {
+		return nil, ErrInvalidCollaboratorRole
+	}
+
+	if _, err := f.RemoveCollaborator(account); err != nil {
+		return nil, err
+	}
+
+	return f.AddCollaborator(account, role)
+}
+
+// RemoveCollaborator Revokes the role of a collaborator on the repository.
+//
+// ErrorsRef:
+//   - facade.Repo.GetCollaborator
+func (f *Repo) RemoveCollaborator(account *Account) (*Collaborator, error) {
+	collaborator, err := f.GetCollaborator(account)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := auth.GetEnforcerInstance().RemoveNamedGroupingPolicy(
+		"g",
+		fmt.Sprintf("/users/%d", account.GetUser().DomainID),
+		collaboratorRoleSubject(f.GetID(), collaborator.Role),
+		f.GetDomainAddress(),
+	); err != nil {
+		return nil, err
+	}
+
+	return collaborator, nil
+}
@@ -0,0 +1,89 @@
+/*
+ * Copyright 2021 Meraj Sahebdar
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package facade
+
+import (
+	"context"
+	"testing"
+
+	"bitban.io/server/internal/pkg/fault"
+	"syreclabs.com/go/faker"
+)
+
+func TestCollaborator(t *testing.T) {
+	t.Run("collaborator", func(t *testing.T) {
+		ctx := context.Background()
+
+		owner := getTestAccount(t, ctx)
+		account := createTestAccount(t, ctx)
+
+		repo, err := CreateRepoByAddress(ctx, owner.GetDomain().Address, faker.Internet().Slug())
+		if err != nil {
+			t.Fatalf("failed to create the repository, got error: %s", err.Error())
+		}
+
+		if _, err := repo.AddCollaborator(owner, CollaboratorRoleRead); err != ErrCollaboratorIsOwner {
+			t.Errorf("expected error: %v, got: %v", ErrCollaboratorIsOwner, err)
+		}
+
+		if _, err := repo.AddCollaborator(account, "owner"); err != ErrInvalidCollaboratorRole {
+			t.Errorf("expected error: %v, got: %v", ErrInvalidCollaboratorRole, err)
+		}
+
+		if _, err := repo.AddCollaborator(account, CollaboratorRoleRead); err != nil {
+			t.Fatalf("failed to add the collaborator, got error: %s", err.Error())
+		}
+
+		if _, err := repo.AddCollaborator(account, CollaboratorRoleWrite); err != ErrCollaboratorExists {
+			t.Errorf("expected error: %v, got: %v", ErrCollaboratorExists, err)
+		}
+
+		checkActs := func(role string, allowed map[string]bool) {
+			for act, ok := range allowed {
+				if err := CheckRepoPermission(account, repo, act); ok && err != nil {
+					t.Errorf("expected a %s collaborator to %s, got error: %s", role, act, err.Error())
+				} else if !ok && err != fault.ErrForbidden {
+					t.Errorf("expected a %s collaborator not to %s, got: %v", role, act, err)
+				}
+			}
+		}
+
+		checkActs(CollaboratorRoleRead, map[string]bool{"read": true, "write": false, "admin": false})
+
+		if _, err := repo.UpdateCollaboratorRole(account, CollaboratorRoleWrite); err != nil {
+			t.Fatalf("failed to update the collaborator role, got error: %s", err.Error())
+		}
+
+		checkActs(CollaboratorRoleWrite, map[string]bool{"read": true, "write": true, "admin": false})
+
+		if collaborators, err := repo.GetCollaborators(); err != nil {
+			t.Errorf("failed to get the collaborators, got error: %s", err.Error())
+		} else if len(collaborators) != 1 || collaborators[0].Role != CollaboratorRoleWrite {
+			t.Errorf("expected a single write collaborator, got: %v", collaborators)
+		}
+
+		if _, err := repo.RemoveCollaborator(account); err != nil {
+			t.Fatalf("failed to remove the collaborator, got error: %s", err.Error())
+		}
+
+		checkActs("removed", map[string]bool{"read": false, "write": false, "admin": false})
+
+		if _, err := repo.RemoveCollaborator(account); err != fault.ErrResourceNotFound {
+			t.Errorf("expected error: %v, got: %v", fault.ErrResourceNotFound, err)
+		}
+	})
+}
@@ -0,0 +1,255 @@
+/*
+ * Copyright 2021 Meraj Sahebdar
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package facade
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/storer"
+	"bitban.io/server/internal/cfg"
+	"bitban.io/server/internal/pkg/exec"
+)
+
+// Signature
+type Signature struct {
+	Name  string
+	Email string
+	When  time.Time
+}
+
+// Commit
+type Commit struct {
+	Hash      string
+	Author    Signature
+	Committer Signature
+	Message   string
+	Parents   []string
+}
+
+// LogConfig
+type LogConfig struct {
+	Rev     string
+	Exclude string
+	Path    string
+	Author  string
+	Since   *time.Time
+	Until   *time.Time
+	Skip    int
+	Limit   int
+}
+
+// commitFrom
+func commitFrom(c *object.Commit) *Commit {
+	parents := make([]string, 0, len(c.ParentHashes))
+	for _, hash := range c.ParentHashes {
+		parents = append(parents, hash.String())
+	}
+
+	return &Commit{
+		Hash: c.Hash.String(),
+		Author: Signature{
+			Name:  c.Author.Name,
+			Email: c.Author.Email,
+			When:  c.Author.When,
+		},
+		Committer: Signature{
+			Name:  c.Committer.Name,
+			Email: c.Committer.Email,
+			When:  c.Committer.When,
+		},
+		Message: c.Message,
+		Parents: parents,
+	}
+}
+
+// commitLogFormat Fields of a commit printed by `git log`, separated by NUL.
+const commitLogFormat = "%H%x00%P%x00%an%x00%ae%x00%aI%x00%cn%x00%ce%x00%cI%x00%B"
+
+// commitLogFields
+const commitLogFields = 9
+
+// parseCommitLog Parses the output of `git log -z` using `commitLogFormat`.
+func parseCommitLog(out []byte) ([]*Commit, error) {
+	fields := bytes.Split(out, []byte{0})
+
+	commits := []*Commit{}
+	for i := 0; i+commitLogFields <= len(fields); i += commitLogFields {
+		f := fields[i : i+commitLogFields]
+
+		authorWhen, err := time.Parse(time.RFC3339, string(f[4]))
+		if err != nil {
+			return nil, err
+		}
+
+		committerWhen, err := time.Parse(time.RFC3339, string(f[7]))
+		if err != nil {
+			return nil, err
+		}
+
+		commits = append(commits, &Commit{
+			Hash: string(f[0]),
+			Author: Signature{
+				Name:  string(f[2]),
+				Email: string(f[3]),
+				When:  authorWhen,
+			},
+			Committer: Signature{
+				Name:  string(f[5]),
+				Email: string(f[6]),
+				When:  committerWhen,
+			},
+			Message: string(f[8]),
+			Parents: strings.Fields(string(f[1])),
+		})
+	}
+
+	return commits, nil
+}
+
+// Log Returns the history of the revision, filtered and paginated using the config.
+//
+// The author filter is a fixed string matched against `Name <email>`, and the
+// commits reachable from the excluded revision are left out if it is provided.
+//
+// Errors:
+//   - facade.ErrRevisionNotFound if the revision does not point to any commit
+func (f *Repo) Log(logConfig *LogConfig) ([]*Commit, error) {
+	path := cleanPath(logConfig.Path)
+
+	if cfg.IsGoBackend() {
+		from, err := f.resolveCommit(logConfig.Rev)
+		if err != nil {
+			return nil, err
+		}
+
+		logOptions := &git.LogOptions{
+			From:  from.Hash,
+			Since: logConfig.Since,
+			Until: logConfig.Until,
+		}
+
+		if path != "" {
+			logOptions.PathFilter = func(name string) bool {
+				return name == path || strings.HasPrefix(name, path+"/")
+			}
+		}
+
+		var excluded map[plumbing.Hash]*object.Commit
+
+		if logConfig.Exclude != "" {
+			if exclude, err := f.resolveCommit(logConfig.Exclude); err != nil {
+				return nil, err
+			} else if excluded, err = ancestorsOf(exclude); err != nil {
+				return nil, err
+			}
+		}
+
+		iter, err := f.repositoryInstance.Log(logOptions)
+		if err != nil {
+			return nil, err
+		}
+		defer iter.Close()
+
+		skip := logConfig.Skip
+		commits := []*Commit{}
+
+		if err := iter.ForEach(func(c *object.Commit) error {
+			if _, ok := excluded[c.Hash]; ok {
+				return nil
+			}
+
+			if logConfig.Author != "" && !strings.Contains(
+				fmt.Sprintf("%s <%s>", c.Author.Name, c.Author.Email),
+				logConfig.Author,
+			) {
+				return nil
+			}
+
+			if skip > 0 {
+				skip--
+				return nil
+			}
+
+			commits = append(commits, commitFrom(c))
+			if logConfig.Limit > 0 && len(commits) >= logConfig.Limit {
+				return storer.ErrStop
+			}
+
+			return nil
+		}); err != nil {
+			return nil, err
+		}
+
+		return commits, nil
+	} else {
+		if err := f.verifyCommit(logConfig.Rev); err != nil {
+			return nil, err
+		}
+
+		if logConfig.Exclude != "" {
+			if err := f.verifyCommit(logConfig.Exclude); err != nil {
+				return nil, err
+			}
+		}
+
+		args := []string{
+			"log",
+			"-z",
+			"--fixed-strings",
+			fmt.Sprintf("--format=%s", commitLogFormat),
+			fmt.Sprintf("--skip=%d", logConfig.Skip),
+		}
+
+		if logConfig.Limit > 0 {
+			args = append(args, fmt.Sprintf("--max-count=%d", logConfig.Limit))
+		}
+
+		if logConfig.Author != "" {
+			args = append(args, fmt.Sprintf("--author=%s", logConfig.Author))
+		}
+
+		if logConfig.Since != nil {
+			args = append(args, fmt.Sprintf("--since=%s", logConfig.Since.Format(time.RFC3339)))
+		}
+
+		if logConfig.Until != nil {
+			args = append(args, fmt.Sprintf("--until=%s", logConfig.Until.Format(time.RFC3339)))
+		}
+
+		args = append(args, logConfig.Rev)
+		if logConfig.Exclude != "" {
+			args = append(args, "^"+logConfig.Exclude)
+		}
+
+		args = append(args, "--")
+		if path != "" {
+			args = append(args, path)
+		}
+
+		if out, err := exec.Output(f.path, "git", args...); err != nil {
+			return nil, err
+		} else {
+			return parseCommitLog(out)
+		}
+	}
+}
@@ -0,0 +1,116 @@
+/*
+ * Copyright 2021 Meraj Sahebdar
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package facade
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+func TestLog(t *testing.T) {
+	ctx := context.Background()
+	repo := createTestRepo(t, ctx)
+
+	// main: c0 - c1 - ... - c4, where the odd commits change docs.
+	var main []plumbing.Hash
+	for i := 0; i < 5; i++ {
+		name := fmt.Sprintf("file%d.txt", i)
+		if i%2 == 1 {
+			name = fmt.Sprintf("docs/page%d.md", i)
+		}
+
+		main = append(main, commitTestFiles(t, repo, "main", fmt.Sprintf("c%d", i), map[string]string{name: "content\n"}))
+	}
+
+	// feature: c0 - ... - c2 - f0 - f1
+	feature := main[2]
+	if err := repo.storage.SetReference(plumbing.NewHashReference(plumbing.NewBranchReferenceName("feature"), feature)); err != nil {
+		t.Fatal(err)
+	}
+	f0 := commitTestFiles(t, repo, "feature", "f0", map[string]string{"feature.txt": "0\n"})
+	f1 := commitTestFiles(t, repo, "feature", "f1", map[string]string{"feature.txt": "1\n"})
+
+	log := func(t *testing.T, logConfig *LogConfig) string {
+		t.Helper()
+
+		commits, err := repo.Log(logConfig)
+		if err != nil {
+			t.Fatalf("got an unexpected error: %s", err.Error())
+		}
+
+		var messages []string
+		for _, commit := range commits {
+			messages = append(messages, commit.Message)
+		}
+
+		return strings.Join(messages, " ")
+	}
+
+	for _, c := range []struct {
+		name      string
+		logConfig *LogConfig
+		expected  string
+	}{
+		{"all", &LogConfig{Rev: "main"}, "c4 c3 c2 c1 c0"},
+		{"first page", &LogConfig{Rev: "main", Limit: 2}, "c4 c3"},
+		{"second page", &LogConfig{Rev: "main", Skip: 2, Limit: 2}, "c2 c1"},
+		{"last page", &LogConfig{Rev: "main", Skip: 4, Limit: 2}, "c0"},
+		{"past the end", &LogConfig{Rev: "main", Skip: 5, Limit: 2}, ""},
+		{"commit hash", &LogConfig{Rev: main[3].String()}, "c3 c2 c1 c0"},
+		{"exclude", &LogConfig{Rev: "feature", Exclude: "main"}, "f1 f0"},
+		{"exclude paginated", &LogConfig{Rev: "feature", Exclude: "main", Skip: 1, Limit: 1}, "f0"},
+		{"exclude ancestor", &LogConfig{Rev: "main", Exclude: main[1].String()}, "c4 c3 c2"},
+		{"directory", &LogConfig{Rev: "main", Path: "docs"}, "c3 c1"},
+		{"file", &LogConfig{Rev: "main", Path: "/docs/page3.md"}, "c3"},
+		{"path paginated", &LogConfig{Rev: "main", Path: "docs", Skip: 1}, "c1"},
+		{"path and exclude", &LogConfig{Rev: "feature", Exclude: "main", Path: "feature.txt"}, "f1 f0"},
+		{"untouched path", &LogConfig{Rev: "main", Path: "feature.txt"}, ""},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			if got := log(t, c.logConfig); got != c.expected {
+				t.Errorf("expected %q, got: %q", c.expected, got)
+			}
+		})
+	}
+
+	t.Run("parents", func(t *testing.T) {
+		commits, err := repo.Log(&LogConfig{Rev: "feature", Limit: 1})
+		if err != nil {
+			t.Fatalf("got an unexpected error: %s", err.Error())
+		}
+
+		if commits[0].Hash != f1.String() || len(commits[0].Parents) != 1 || commits[0].Parents[0] != f0.String() {
+			t.Errorf("expected f1 with f0 as parent, got: %s %v", commits[0].Hash, commits[0].Parents)
+		}
+	})
+
+	t.Run("unknown revision", func(t *testing.T) {
+		if _, err := repo.Log(&LogConfig{Rev: "unknown"}); err != ErrRevisionNotFound {
+			t.Errorf("expected error: %v, got: %v", ErrRevisionNotFound, err)
+		}
+	})
+
+	t.Run("unknown exclude", func(t *testing.T) {
+		if _, err := repo.Log(&LogConfig{Rev: "main", Exclude: "unknown"}); err != ErrRevisionNotFound {
+			t.Errorf("expected error: %v, got: %v", ErrRevisionNotFound, err)
+		}
+	})
+}
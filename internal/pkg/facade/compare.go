@@ -0,0 +1,510 @@
+/*
+ * Copyright 2021 Meraj Sahebdar
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package facade
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+	"github.com/go-git/go-git/v5/plumbing/format/diff"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"bitban.io/server/internal/cfg"
+	"bitban.io/server/internal/pkg/exec"
+)
+
+// File Diff Statuses
+const (
+	FileAdded    = "added"
+	FileModified = "modified"
+	FileDeleted  = "deleted"
+	FileRenamed  = "renamed"
+)
+
+// Compare Limits
+const (
+	// MaxDiffFileSize Files larger than this size in bytes are listed without a patch.
+	MaxDiffFileSize = 1 << 20
+
+	// MaxCompareFiles
+	MaxCompareFiles = 300
+
+	// MaxCompareCommits
+	MaxCompareCommits = 250
+)
+
+var (
+	// ErrNoMergeBase
+	ErrNoMergeBase = errors.New("the revisions do not have any common history")
+)
+
+// FileDiff
+type FileDiff struct {
+	Path       string
+	OldPath    string
+	Status     string
+	Additions  int
+	Deletions  int
+	IsBinary   bool
+	IsTooLarge bool
+	Patch      string
+}
+
+// Comparison
+type Comparison struct {
+	MergeBase   string
+	Ahead       int
+	Behind      int
+	Commits     []*Commit
+	Files       []*FileDiff
+	IsTruncated bool
+}
+
+// setPatch Keeps the hunks of a unified diff and counts its changed lines.
+func (d *FileDiff) setPatch(patch string) {
+	if i := strings.Index(patch, "@@"); i >= 0 {
+		d.Patch = patch[i:]
+	} else {
+		d.Patch = ""
+	}
+
+	for _, line := range strings.Split(d.Patch, "\n") {
+		switch {
+		case strings.HasPrefix(line, "+"):
+			d.Additions++
+		case strings.HasPrefix(line, "-"):
+			d.Deletions++
+		}
+	}
+}
+
+// ancestorsOf Returns the hashes of the commit and all of its ancestors.
+func ancestorsOf(c *object.Commit) (map[plumbing.Hash]*object.Commit, error) {
+	ret := map[plumbing.Hash]*object.Commit{}
+
+	if err := object.NewCommitPreorderIter(c, nil, nil).ForEach(func(c *object.Commit) error {
+		ret[c.Hash] = c
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+// blobSize Returns zero for missing blobs.
+func (f *Repo) blobSize(hash plumbing.Hash) int64 {
+	if blob, err := f.repositoryInstance.BlobObject(hash); err != nil {
+		return 0
+	} else {
+		return blob.Size
+	}
+}
+
+// fileDiffFrom
+func (f *Repo) fileDiffFrom(change *object.Change) (*FileDiff, error) {
+	d := &FileDiff{}
+
+	switch {
+	case change.From.Name == "":
+		d.Status = FileAdded
+		d.Path = change.To.Name
+	case change.To.Name == "":
+		d.Status = FileDeleted
+		d.Path = change.From.Name
+	case change.From.Name != change.To.Name:
+		d.Status = FileRenamed
+		d.Path = change.To.Name
+		d.OldPath = change.From.Name
+	default:
+		d.Status = FileModified
+		d.Path = change.To.Name
+	}
+
+	if change.From.TreeEntry.Mode == filemode.Submodule || change.To.TreeEntry.Mode == filemode.Submodule {
+		return d, nil
+	}
+
+	if f.blobSize(change.From.TreeEntry.Hash) > MaxDiffFileSize || f.blobSize(change.To.TreeEntry.Hash) > MaxDiffFileSize {
+		d.IsTooLarge = true
+		return d, nil
+	}
+
+	patch, err := change.Patch()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, fp := range patch.FilePatches() {
+		if fp.IsBinary() {
+			d.IsBinary = true
+			return d, nil
+		}
+	}
+
+	buf := &bytes.Buffer{}
+	if err := diff.NewUnifiedEncoder(buf, diff.DefaultContextLines).Encode(patch); err != nil {
+		return nil, err
+	}
+
+	d.setPatch(buf.String())
+
+	return d, nil
+}
+
+// compareByGo
+func (f *Repo) compareByGo(base string, head string) (*Comparison, error) {
+	baseCommit, err := f.resolveCommit(base)
+	if err != nil {
+		return nil, err
+	}
+
+	headCommit, err := f.resolveCommit(head)
+	if err != nil {
+		return nil, err
+	}
+
+	mergeBases, err := baseCommit.MergeBase(headCommit)
+	if err != nil {
+		return nil, err
+	} else if len(mergeBases) == 0 {
+		return nil, ErrNoMergeBase
+	}
+
+	ret := &Comparison{
+		MergeBase: mergeBases[0].Hash.String(),
+		Commits:   []*Commit{},
+		Files:     []*FileDiff{},
+	}
+
+	//
+	// Ahead & Behind
+
+	baseAncestors, err := ancestorsOf(baseCommit)
+	if err != nil {
+		return nil, err
+	}
+
+	headAncestors, err := ancestorsOf(headCommit)
+	if err != nil {
+		return nil, err
+	}
+
+	var ahead []*object.Commit
+	for hash, c := range headAncestors {
+		if _, ok := baseAncestors[hash]; !ok {
+			ahead = append(ahead, c)
+		}
+	}
+
+	for hash := range baseAncestors {
+		if _, ok := headAncestors[hash]; !ok {
+			ret.Behind++
+		}
+	}
+
+	sort.SliceStable(ahead, func(i, j int) bool {
+		return ahead[i].Committer.When.After(ahead[j].Committer.When)
+	})
+
+	ret.Ahead = len(ahead)
+	for i, c := range ahead {
+		if i == MaxCompareCommits {
+			break
+		}
+
+		ret.Commits = append(ret.Commits, commitFrom(c))
+	}
+
+	//
+	// Files
+
+	fromTree, err := mergeBases[0].Tree()
+	if err != nil {
+		return nil, err
+	}
+
+	toTree, err := headCommit.Tree()
+	if err != nil {
+		return nil, err
+	}
+
+	diffTreeOptions := *object.DefaultDiffTreeOptions
+	diffTreeOptions.RenameScore = 50
+
+	changes, err := object.DiffTreeWithOptions(f.ctx, fromTree, toTree, &diffTreeOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, change := range changes {
+		if len(ret.Files) == MaxCompareFiles {
+			ret.IsTruncated = true
+			break
+		}
+
+		if d, err := f.fileDiffFrom(change); err != nil {
+			return nil, err
+		} else {
+			ret.Files = append(ret.Files, d)
+		}
+	}
+
+	return ret, nil
+}
+
+// rawDiffEntry
+type rawDiffEntry struct {
+	*FileDiff
+	oldHash string
+	newHash string
+	oldMode string
+	newMode string
+}
+
+// parseRawDiff Parses the output of `git diff --raw -z`.
+func parseRawDiff(out []byte) []*rawDiffEntry {
+	fields := strings.Split(strings.TrimSuffix(string(out), "\x00"), "\x00")
+
+	var ret []*rawDiffEntry
+	for i := 0; i+1 < len(fields); {
+		// :<old mode> <new mode> <old hash> <new hash> <status>
+		meta := strings.Fields(strings.TrimPrefix(fields[i], ":"))
+		if len(meta) != 5 {
+			break
+		}
+
+		entry := &rawDiffEntry{
+			FileDiff: &FileDiff{},
+			oldMode:  meta[0],
+			newMode:  meta[1],
+			oldHash:  meta[2],
+			newHash:  meta[3],
+		}
+
+		switch meta[4][0] {
+		case 'A':
+			entry.Status = FileAdded
+		case 'D':
+			entry.Status = FileDeleted
+		case 'R':
+			entry.Status = FileRenamed
+		default:
+			entry.Status = FileModified
+		}
+
+		if entry.Status == FileRenamed && i+2 < len(fields) {
+			entry.OldPath = fields[i+1]
+			entry.Path = fields[i+2]
+			i += 3
+		} else {
+			entry.Path = fields[i+1]
+			i += 2
+		}
+
+		ret = append(ret, entry)
+	}
+
+	return ret
+}
+
+// blobSizesByBin Returns the sizes of the blobs using a single `git cat-file` call.
+func (f *Repo) blobSizesByBin(hashes []string) (map[string]int64, error) {
+	out, err := exec.OutputFrom(
+		strings.NewReader(strings.Join(hashes, "\n")+"\n"),
+		f.path,
+		"git",
+		"cat-file",
+		"--batch-check=%(objectname) %(objectsize)",
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	ret := map[string]int64{}
+	for _, line := range strings.Split(string(out), "\n") {
+		if fields := strings.Fields(line); len(fields) == 2 {
+			if size, err := strconv.ParseInt(fields[1], 10, 64); err == nil {
+				ret[fields[0]] = size
+			}
+		}
+	}
+
+	return ret, nil
+}
+
+// compareByBin
+func (f *Repo) compareByBin(base string, head string) (*Comparison, error) {
+	if err := f.verifyCommit(base); err != nil {
+		return nil, err
+	}
+
+	if err := f.verifyCommit(head); err != nil {
+		return nil, err
+	}
+
+	out, err := exec.Output(f.path, "git", "merge-base", base, head)
+	if err != nil {
+		return nil, ErrNoMergeBase
+	}
+
+	ret := &Comparison{
+		MergeBase: strings.TrimSpace(string(out)),
+		Files:     []*FileDiff{},
+	}
+
+	//
+	// Ahead & Behind
+
+	if out, err = exec.Output(
+		f.path,
+		"git",
+		"rev-list",
+		"--left-right",
+		"--count",
+		fmt.Sprintf("%s...%s", base, head),
+	); err != nil {
+		return nil, err
+	}
+
+	if counts := strings.Fields(string(out)); len(counts) == 2 {
+		ret.Behind, _ = strconv.Atoi(counts[0])
+		ret.Ahead, _ = strconv.Atoi(counts[1])
+	}
+
+	if out, err = exec.Output(
+		f.path,
+		"git",
+		"log",
+		"-z",
+		"--date-order",
+		fmt.Sprintf("--format=%s", commitLogFormat),
+		fmt.Sprintf("--max-count=%d", MaxCompareCommits),
+		fmt.Sprintf("%s..%s", base, head),
+		"--",
+	); err != nil {
+		return nil, err
+	}
+
+	if ret.Commits, err = parseCommitLog(out); err != nil {
+		return nil, err
+	}
+
+	//
+	// Files
+
+	if out, err = exec.Output(
+		f.path,
+		"git",
+		"diff",
+		"--raw",
+		"--no-abbrev",
+		"-z",
+		"--find-renames",
+		ret.MergeBase,
+		head,
+		"--",
+	); err != nil {
+		return nil, err
+	}
+
+	entries := parseRawDiff(out)
+	if len(entries) > MaxCompareFiles {
+		entries = entries[:MaxCompareFiles]
+		ret.IsTruncated = true
+	}
+
+	var hashes []string
+	for _, entry := range entries {
+		hashes = append(hashes, entry.oldHash, entry.newHash)
+	}
+
+	sizes, err := f.blobSizesByBin(hashes)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, entry := range entries {
+		ret.Files = append(ret.Files, entry.FileDiff)
+
+		switch {
+		case entry.oldMode == "160000" || entry.newMode == "160000":
+			continue
+		case sizes[entry.oldHash] > MaxDiffFileSize || sizes[entry.newHash] > MaxDiffFileSize:
+			entry.IsTooLarge = true
+			continue
+		}
+
+		args := []string{
+			"diff",
+			"--no-color",
+			"--no-ext-diff",
+			"--find-renames",
+			ret.MergeBase,
+			head,
+			"--",
+			entry.Path,
+		}
+
+		if entry.OldPath != "" {
+			args = append(args, entry.OldPath)
+		}
+
+		if out, err = exec.Output(f.path, "git", args...); err != nil {
+			return nil, err
+		}
+
+		if patch := string(out); strings.Contains(patch, "\nBinary files ") || strings.HasPrefix(patch, "Binary files ") {
+			entry.IsBinary = true
+		} else {
+			entry.setPatch(patch)
+		}
+	}
+
+	return ret, nil
+}
+
+// Compare Returns the commits and the changes of the head since its merge base with the base.
+//
+// Errors:
+//   - facade.ErrRevisionNotFound if any of the revisions does not point to a commit
+//   - facade.ErrNoMergeBase if the revisions do not share any history
+func (f *Repo) Compare(base string, head string) (*Comparison, error) {
+	var ret *Comparison
+	var err error
+
+	if cfg.IsGoBackend() {
+		ret, err = f.compareByGo(base, head)
+	} else {
+		ret, err = f.compareByBin(base, head)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(ret.Files, func(i, j int) bool {
+		return ret.Files[i].Path < ret.Files[j].Path
+	})
+
+	return ret, nil
+}
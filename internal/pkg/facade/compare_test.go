@@ -0,0 +1,59 @@
+/*
+ * Copyright 2021 Meraj Sahebdar
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package facade
+
+import (
+	"testing"
+)
+
+func TestFileDiffPatch(t *testing.T) {
+	d := &FileDiff{}
+	d.setPatch("diff --git a/f.txt b/f.txt\n--- a/f.txt\n+++ b/f.txt\n@@ -1,2 +1,2 @@\n hello\n-world\n+there\n+again\n")
+
+	if d.Additions != 2 || d.Deletions != 1 {
+		t.Errorf("expected +2 -1, got: +%d -%d", d.Additions, d.Deletions)
+	}
+
+	if d.Patch[:2] != "@@" {
+		t.Errorf("expected the patch to start with a hunk, got: %q", d.Patch)
+	}
+}
+
+func TestParseRawDiff(t *testing.T) {
+	out := ":100644 100644 aaa bbb M\x00f.txt\x00" +
+		":100644 100644 ccc ccc R100\x00old.txt\x00new.txt\x00" +
+		":000000 100644 000 ddd A\x00added.txt\x00"
+
+	entries := parseRawDiff([]byte(out))
+	if len(entries) != 3 {
+		t.Fatalf("expected 3 entries, got: %d", len(entries))
+	}
+
+	for i, expected := range []struct {
+		path    string
+		oldPath string
+		status  string
+	}{
+		{"f.txt", "", FileModified},
+		{"new.txt", "old.txt", FileRenamed},
+		{"added.txt", "", FileAdded},
+	} {
+		if e := entries[i]; e.Path != expected.path || e.OldPath != expected.oldPath || e.Status != expected.status {
+			t.Errorf("expected %v, got: %s %s %s", expected, e.Path, e.OldPath, e.Status)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+/*
+ * Copyright 2021 Meraj Sahebdar
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package facade
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/uptrace/bun"
+	"bitban.io/server/internal/pkg/fault"
+	"bitban.io/server/internal/pkg/orm"
+	"bitban.io/server/internal/pkg/orm/entity"
+)
+
+// Domain Types
+const (
+	DomainTypeUser         = "user"
+	DomainTypeOrganization = "organization"
+)
+
+// GetDomainByAddress
+func GetDomainByAddress(ctx context.Context, address string) (*entity.Domain, error) {
+	domain := new(entity.Domain)
+	if err := orm.GetBunInstance().
+		NewSelect().
+		Model(domain).
+		Where("? = ?", bun.Ident("domain.address"), address).
+		Where("? IS NULL", bun.Ident("domain.removed_at")).
+		Scan(ctx); err != nil {
+		return nil, err
+	}
+
+	return domain, nil
+}
+
+// CheckDomainPermission Checks whether the account is allowed to perform the
+// action on the owner of the domain.
+//
+// Errors:
+//   - fault.ErrForbidden if the authorized user does not have access to the domain
+func (f *Account) CheckDomainPermission(domain *entity.Domain, act string) error {
+	switch domain.Type {
+	case DomainTypeUser:
+		return f.CheckPermission(fmt.Sprintf("/users/%d", domain.ID), act)
+	case DomainTypeOrganization:
+		return f.CheckPermissionIn(domain.Address, fmt.Sprintf("/organizations/%d", domain.ID), act)
+	}
+
+	return fault.ErrForbidden
+}
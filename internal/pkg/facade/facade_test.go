@@ -17,10 +17,20 @@
 package facade
 
 import (
+	"context"
+	"sort"
+	"strings"
 	"testing"
+	"time"
 
+	"bitban.io/server/internal/pkg/dto"
 	"bitban.io/server/internal/pkg/orm"
 	"bitban.io/server/test"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/storage"
+	"syreclabs.com/go/faker"
 )
 
 func TestMain(m *testing.M) {
@@ -29,3 +39,188 @@ func TestMain(m *testing.M) {
 	m.Run()
 	orm.MigrateDown(0)
 }
+
+// getTestAccount Returns the user fixture.
+func getTestAccount(t *testing.T, ctx context.Context) *Account {
+	t.Helper()
+
+	account, err := GetAccountByUserId(ctx, 1)
+	if err != nil {
+		t.Fatalf("failed to find the user fixture: %s", err.Error())
+	}
+
+	return account
+}
+
+// createTestAccount Signs up a new user.
+func createTestAccount(t *testing.T, ctx context.Context) *Account {
+	t.Helper()
+
+	password := faker.Internet().Password(8, 10)
+	account, err := CreateAccount(ctx, dto.SignUpInput{
+		Password:        password,
+		PasswordConfirm: password,
+		PrimaryEmail: dto.SignUpPrimaryEmailInput{
+			Address: faker.Internet().SafeEmail(),
+		},
+		Domain: dto.SignUpDomainInput{
+			Name:    faker.Name().Name(),
+			Address: faker.Internet().UserName(),
+		},
+	})
+	if err != nil {
+		t.Fatalf("failed to sign up: %s", err.Error())
+	}
+
+	return account
+}
+
+// createTestRepo Creates a repository in the domain of the user fixture.
+func createTestRepo(t *testing.T, ctx context.Context) *Repo {
+	t.Helper()
+
+	repo, err := CreateRepoByAddress(ctx, getTestAccount(t, ctx).GetDomain().Address, faker.Internet().Slug())
+	if err != nil {
+		t.Fatalf("failed to create the repository: %s", err.Error())
+	}
+
+	return repo
+}
+
+// testCommitTime Keeps the commits of the tests in a stable chronological order.
+var testCommitTime = time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// commitTestFiles Commits the files on top of the branch of the repository and
+// returns the hash of the new commit.
+func commitTestFiles(t *testing.T, repo *Repo, branch string, message string, files map[string]string) plumbing.Hash {
+	t.Helper()
+
+	entries := map[string]plumbing.Hash{}
+	var parents []plumbing.Hash
+
+	refName := plumbing.NewBranchReferenceName(branch)
+	if ref, err := repo.storage.Reference(refName); err == nil {
+		parents = append(parents, ref.Hash())
+
+		commit, err := object.GetCommit(repo.storage, ref.Hash())
+		if err != nil {
+			t.Fatalf("failed to read the branch head: %s", err.Error())
+		}
+
+		tree, err := commit.Tree()
+		if err != nil {
+			t.Fatalf("failed to read the branch tree: %s", err.Error())
+		}
+
+		if err := tree.Files().ForEach(func(file *object.File) error {
+			entries[file.Name] = file.Hash
+			return nil
+		}); err != nil {
+			t.Fatalf("failed to walk the branch tree: %s", err.Error())
+		}
+	}
+
+	for name, content := range files {
+		obj := repo.storage.NewEncodedObject()
+		obj.SetType(plumbing.BlobObject)
+
+		w, err := obj.Writer()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+		if err := w.Close(); err != nil {
+			t.Fatal(err)
+		}
+
+		if entries[name], err = repo.storage.SetEncodedObject(obj); err != nil {
+			t.Fatalf("failed to store the blob: %s", err.Error())
+		}
+	}
+
+	testCommitTime = testCommitTime.Add(time.Minute)
+	signature := object.Signature{
+		Name:  "Bitban",
+		Email: "bitban@bitban.io",
+		When:  testCommitTime,
+	}
+
+	commit := &object.Commit{
+		Author:       signature,
+		Committer:    signature,
+		Message:      message,
+		TreeHash:     storeTestTree(t, repo.storage, entries),
+		ParentHashes: parents,
+	}
+
+	obj := repo.storage.NewEncodedObject()
+	if err := commit.Encode(obj); err != nil {
+		t.Fatal(err)
+	}
+
+	hash, err := repo.storage.SetEncodedObject(obj)
+	if err != nil {
+		t.Fatalf("failed to store the commit: %s", err.Error())
+	}
+
+	if err := repo.storage.SetReference(plumbing.NewHashReference(refName, hash)); err != nil {
+		t.Fatalf("failed to update the branch: %s", err.Error())
+	}
+
+	return hash
+}
+
+// storeTestTree Stores the nested trees of the flat path to blob entries.
+func storeTestTree(t *testing.T, s storage.Storer, entries map[string]plumbing.Hash) plumbing.Hash {
+	t.Helper()
+
+	blobs := map[string]plumbing.Hash{}
+	dirs := map[string]map[string]plumbing.Hash{}
+	for name, hash := range entries {
+		if i := strings.Index(name, "/"); i == -1 {
+			blobs[name] = hash
+		} else {
+			dir := name[:i]
+			if dirs[dir] == nil {
+				dirs[dir] = map[string]plumbing.Hash{}
+			}
+			dirs[dir][name[i+1:]] = hash
+		}
+	}
+
+	tree := &object.Tree{}
+	for name, hash := range blobs {
+		tree.Entries = append(tree.Entries, object.TreeEntry{Name: name, Mode: filemode.Regular, Hash: hash})
+	}
+	for name, children := range dirs {
+		tree.Entries = append(tree.Entries, object.TreeEntry{Name: name, Mode: filemode.Dir, Hash: storeTestTree(t, s, children)})
+	}
+
+	// Git orders the entries as if the names of the trees end with a slash.
+	sort.Slice(tree.Entries, func(i, j int) bool {
+		return treeEntrySortName(tree.Entries[i]) < treeEntrySortName(tree.Entries[j])
+	})
+
+	obj := s.NewEncodedObject()
+	if err := tree.Encode(obj); err != nil {
+		t.Fatal(err)
+	}
+
+	hash, err := s.SetEncodedObject(obj)
+	if err != nil {
+		t.Fatalf("failed to store the tree: %s", err.Error())
+	}
+
+	return hash
+}
+
+// treeEntrySortName
+func treeEntrySortName(entry object.TreeEntry) string {
+	if entry.Mode == filemode.Dir {
+		return entry.Name + "/"
+	}
+
+	return entry.Name
+}
@@ -0,0 +1,213 @@
+/*
+ * Copyright 2021 Meraj Sahebdar
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package facade
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/uptrace/bun"
+	"github.com/volatiletech/null/v8"
+	"bitban.io/server/internal/cfg"
+	"bitban.io/server/internal/pkg/fault"
+	"bitban.io/server/internal/pkg/orm"
+	"bitban.io/server/internal/pkg/orm/entity"
+)
+
+// copyFile Copies a file of the operating system filesystem.
+func copyFile(from string, to string, mode os.FileMode) error {
+	src, err := os.Open(from)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dst, err := os.OpenFile(to, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, mode)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(dst, src); err != nil {
+		dst.Close()
+		return err
+	}
+
+	return dst.Close()
+}
+
+// forkStorage Clones the storage of a repository to another path. On the
+// operating system filesystem the objects are hardlinked, since git never
+// modifies them in place, while the references and the config are copied.
+// Unlike alternates, the links keep working after the source repository is
+// moved, deleted or purged.
+func forkStorage(from string, to string) error {
+	if cfg.Cog.Git.Storage == cfg.GitStorageFs {
+		objects := filepath.Join(from, "objects") + string(filepath.Separator)
+
+		return filepath.Walk(from, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+
+			rel, err := filepath.Rel(from, path)
+			if err != nil {
+				return err
+			}
+
+			dst := filepath.Join(to, rel)
+
+			switch {
+			case info.IsDir():
+				return os.MkdirAll(dst, 0755)
+			case strings.HasPrefix(path, objects):
+				return os.Link(path, dst)
+			default:
+				return copyFile(path, dst, info.Mode())
+			}
+		})
+	}
+
+	if fs, err := getFs("/"); err != nil {
+		return err
+	} else {
+		return copyDir(fs, from, to)
+	}
+}
+
+// Fork Creates a copy of the repository at the address within the domain,
+// recording the repository as its parent.
+//
+// Errors:
+//   - facade.ErrRepositoryAddressTaken if the address is used by another repository
+// ErrorsRef:
+//   - facade.checkRepoAddress
+func (f *Repo) Fork(domain *entity.Domain, address string) (repo *Repo, err error) {
+	if err := checkRepoAddress(address); err != nil {
+		return nil, err
+	}
+
+	if count, err := orm.GetBunInstance().
+		NewSelect().
+		Model((*entity.Repository)(nil)).
+		Where("? = ?", bun.Ident("repository.domain_id"), domain.ID).
+		Where("? = ?", bun.Ident("repository.address"), address).
+		Where("? IS NULL", bun.Ident("repository.removed_at")).
+		Count(f.ctx); err != nil {
+		return nil, err
+	} else if count > 0 {
+		return nil, ErrRepositoryAddressTaken
+	}
+
+	path, err := getStoragePath("repos", domain.Address, address)
+	if err != nil {
+		return nil, err
+	}
+
+	var tx bun.Tx
+	if tx, err = orm.GetBunInstance().BeginTx(f.ctx, nil); err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if err != nil && repo == nil {
+			tx.Rollback()
+		}
+	}()
+
+	repositoryEntity := &entity.Repository{
+		Address:    address,
+		Visibility: f.repositoryEntity.Visibility,
+		DomainID:   null.Int64From(domain.ID),
+		Domain:     domain,
+		ParentID:   null.Int64From(f.GetID()),
+	}
+	if _, err = tx.
+		NewInsert().
+		Model(repositoryEntity).
+		Column("address", "visibility", "domain_id", "parent_id").
+		Exec(f.ctx); err != nil {
+		return nil, err
+	}
+
+	if err = forkStorage(f.path, path); err != nil {
+		removeStorage(path)
+		return nil, err
+	}
+
+	lfsPath, err := getLfsPath(repositoryEntity.ID)
+	if err != nil {
+		removeStorage(path)
+		return nil, err
+	}
+
+	if err = f.forkLfsStorage(repositoryEntity.ID); err != nil {
+		removeStorage(path)
+		removeStorage(lfsPath)
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		removeStorage(path)
+		removeStorage(lfsPath)
+		return nil, err
+	}
+
+	return repoFrom(f.ctx, repositoryEntity)
+}
+
+// GetParent Returns the repository this one is forked from, or nil if it is
+// not a fork or its parent is deleted.
+func (f *Repo) GetParent() (*Repo, error) {
+	if !f.repositoryEntity.ParentID.Valid {
+		return nil, nil
+	}
+
+	if repo, err := GetRepoById(f.ctx, f.repositoryEntity.ParentID.Int64); err == fault.ErrResourceNotFound {
+		return nil, nil
+	} else {
+		return repo, err
+	}
+}
+
+// GetForks Returns the repositories forked from this one, the oldest first.
+func (f *Repo) GetForks() ([]*Repo, error) {
+	var repositoryEntities []*entity.Repository
+	if err := orm.
+		GetBunInstance().
+		NewSelect().
+		Model(&repositoryEntities).
+		Relation("Domain").
+		Where("? = ?", bun.Ident("repository.parent_id"), f.GetID()).
+		Where("? IS NULL", bun.Ident("repository.removed_at")).
+		Order("repository.id").
+		Scan(f.ctx); err != nil {
+		return nil, err
+	}
+
+	forks := make([]*Repo, 0, len(repositoryEntities))
+	for _, repositoryEntity := range repositoryEntities {
+		if repo, err := repoFrom(f.ctx, repositoryEntity); err != nil {
+			return nil, err
+		} else {
+			forks = append(forks, repo)
+		}
+	}
+
+	return forks, nil
+}
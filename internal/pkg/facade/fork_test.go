@@ -0,0 +1,102 @@
+/*
+ * Copyright 2021 Meraj Sahebdar
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package facade
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-git/go-billy/v5/util"
+	"syreclabs.com/go/faker"
+	"bitban.io/server/internal/cfg"
+)
+
+func TestForkStorage(t *testing.T) {
+	if cfg.Cog.Git.Storage != cfg.GitStorageMem {
+		t.Skip("the test runs on the memory storage")
+	}
+
+	fs, err := getFs("/")
+	if err != nil {
+		t.Fatalf("failed to get the filesystem, got error: %s", err.Error())
+	}
+
+	for _, path := range []string{"/repos/fork/a/HEAD", "/repos/fork/a/objects/info/packs"} {
+		if err := util.WriteFile(fs, path, []byte(path), 0644); err != nil {
+			t.Fatalf("failed to write %s, got error: %s", path, err.Error())
+		}
+	}
+
+	if err := forkStorage("/repos/fork/a", "/repos/fork/b"); err != nil {
+		t.Fatalf("failed to fork the storage, got error: %s", err.Error())
+	}
+
+	for _, path := range []string{
+		"/repos/fork/a/HEAD",
+		"/repos/fork/a/objects/info/packs",
+		"/repos/fork/b/HEAD",
+		"/repos/fork/b/objects/info/packs",
+	} {
+		if _, err := fs.Stat(path); err != nil {
+			t.Errorf("expected %s to exist, got error: %s", path, err.Error())
+		}
+	}
+}
+
+func TestFork(t *testing.T) {
+	t.Run("fork", func(t *testing.T) {
+		ctx := context.Background()
+
+		account := getTestAccount(t, ctx)
+		repo := createTestRepo(t, ctx)
+
+		if _, err := repo.Fork(account.GetDomain(), repo.GetEntity().Address); err != ErrRepositoryAddressTaken {
+			t.Errorf("expected error: %v, got: %v", ErrRepositoryAddressTaken, err)
+		}
+
+		if _, err := repo.Fork(account.GetDomain(), "../"+repo.GetEntity().Address); err != ErrInvalidRepositoryAddress {
+			t.Errorf("expected error: %v, got: %v", ErrInvalidRepositoryAddress, err)
+		}
+
+		fork, err := repo.Fork(account.GetDomain(), faker.Internet().Slug())
+		if err != nil {
+			t.Fatalf("failed to fork the repository, got error: %s", err.Error())
+		}
+
+		if parent, err := fork.GetParent(); err != nil {
+			t.Errorf("got an unexpected error: %s", err.Error())
+		} else if parent == nil || parent.GetID() != repo.GetID() {
+			t.Errorf("expected the parent to be the repository")
+		}
+
+		if forks, err := repo.GetForks(); err != nil {
+			t.Errorf("got an unexpected error: %s", err.Error())
+		} else if len(forks) != 1 || forks[0].GetID() != fork.GetID() {
+			t.Errorf("expected the fork to be listed, got: %d forks", len(forks))
+		}
+
+		if err := repo.Delete(); err != nil {
+			t.Fatalf("failed to delete the repository, got error: %s", err.Error())
+		}
+
+		if parent, err := fork.GetParent(); err != nil {
+			t.Errorf("got an unexpected error: %s", err.Error())
+		} else if parent != nil {
+			t.Errorf("expected no parent after deleting it")
+		}
+	})
+}
@@ -0,0 +1,317 @@
+/*
+ * Copyright 2021 Meraj Sahebdar
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package facade
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/protocol/packp"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+	"bitban.io/server/internal/cfg"
+)
+
+// ReceiveHook Runs around the reference updates of every push, on both git backends.
+type ReceiveHook interface {
+	// PreReceive Runs before updating any reference, and rejects the whole
+	// push by returning an error.
+	PreReceive(ctx context.Context, repo *Repo, commands []*packp.Command) error
+
+	// PostReceive Runs after the references are updated with the applied updates.
+	PostReceive(ctx context.Context, repo *Repo, updates []*packp.Command) error
+}
+
+// Push Protocols
+const (
+	PushProtocolHttp = "http"
+	PushProtocolSsh  = "ssh"
+)
+
+// PushInfo Describes the push running the receive hooks.
+type PushInfo struct {
+	PusherID   int64
+	Protocol   string
+	RemoteAddr string
+}
+
+// pushInfoContextKey
+type pushInfoContextKey struct{}
+
+// withPushInfo
+func withPushInfo(ctx context.Context, pushInfo *PushInfo) context.Context {
+	return context.WithValue(ctx, pushInfoContextKey{}, pushInfo)
+}
+
+// GetPushInfo Returns the push running the receive hooks.
+func GetPushInfo(ctx context.Context) *PushInfo {
+	if pushInfo, ok := ctx.Value(pushInfoContextKey{}).(*PushInfo); ok {
+		return pushInfo
+	}
+
+	return &PushInfo{}
+}
+
+// GetPusherID Returns the user pushing to the repository within the receive
+// hooks, or zero if the push is anonymous.
+func GetPusherID(ctx context.Context) int64 {
+	return GetPushInfo(ctx).PusherID
+}
+
+// ReceiveHookGroup The fx value group collecting the receive hooks.
+const ReceiveHookGroup = "receiveHooks"
+
+// AsReceiveHook Provides a receive hook to the app by its constructor.
+func AsReceiveHook(constructor interface{}) fx.Option {
+	return fx.Provide(fx.Annotated{
+		Group:  ReceiveHookGroup,
+		Target: constructor,
+	})
+}
+
+// receiveHookParams
+type receiveHookParams struct {
+	fx.In
+
+	Hooks []ReceiveHook `group:"receiveHooks"`
+}
+
+// ReceiveHookOpt Registers the receive hooks provided through fx.
+var ReceiveHookOpt = fx.Invoke(func(params receiveHookParams) {
+	RegisterReceiveHooks(params.Hooks...)
+})
+
+var (
+	// receiveHooksMutex
+	receiveHooksMutex = &sync.RWMutex{}
+
+	// receiveHooks
+	receiveHooks []ReceiveHook
+)
+
+// RegisterReceiveHooks
+func RegisterReceiveHooks(hooks ...ReceiveHook) {
+	receiveHooksMutex.Lock()
+	defer receiveHooksMutex.Unlock()
+
+	receiveHooks = append(receiveHooks, hooks...)
+}
+
+// getReceiveHooks
+func getReceiveHooks() []ReceiveHook {
+	receiveHooksMutex.RLock()
+	defer receiveHooksMutex.RUnlock()
+
+	return receiveHooks
+}
+
+// runPreReceiveHooks Returns the error of the first hook rejecting the push.
+func runPreReceiveHooks(ctx context.Context, repo *Repo, commands []*packp.Command) error {
+	for _, hook := range getReceiveHooks() {
+		if err := hook.PreReceive(ctx, repo, commands); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// runPostReceiveHooks Runs every hook, logging their errors since the push is
+// already done.
+func runPostReceiveHooks(ctx context.Context, repo *Repo, updates []*packp.Command) {
+	for _, hook := range getReceiveHooks() {
+		if err := hook.PostReceive(ctx, repo, updates); err != nil {
+			cfg.Log.Error("failed to run a post-receive hook", zap.Error(err))
+		}
+	}
+}
+
+// Hook Environment
+const (
+	HookRepositoryEnv = "BITBAN_REPOSITORY_ID"
+	HookPusherEnv     = "BITBAN_PUSHER_ID"
+	HookProtocolEnv   = "BITBAN_PUSH_PROTOCOL"
+	HookRemoteAddrEnv = "BITBAN_REMOTE_ADDR"
+)
+
+// hookNames The git hooks which call back into the server binary.
+var hookNames = []string{
+	"pre-receive",
+	"update",
+	"post-receive",
+}
+
+var (
+	// hooksPathOnce
+	hooksPathOnce sync.Once
+
+	// hooksPath
+	hooksPath string
+
+	// hooksPathErr
+	hooksPathErr error
+)
+
+// getHooksPath Writes the scripts of the git hooks calling back into the
+// server binary, and returns their directory to be used as `core.hooksPath`.
+func getHooksPath() (string, error) {
+	hooksPathOnce.Do(func() {
+		var exe, root string
+
+		if exe, hooksPathErr = os.Executable(); hooksPathErr != nil {
+			return
+		}
+
+		if root, hooksPathErr = cfg.GetVarPath(); hooksPathErr != nil {
+			return
+		}
+
+		hooksPath = root + "/hooks"
+		if hooksPathErr = os.MkdirAll(hooksPath, 0755); hooksPathErr != nil {
+			return
+		}
+
+		for _, name := range hookNames {
+			script := fmt.Sprintf(
+				"#!/bin/sh\nexec '%s' hook %s \"$@\"\n",
+				strings.ReplaceAll(exe, "'", `'\''`),
+				name,
+			)
+
+			if hooksPathErr = ioutil.WriteFile(hooksPath+"/"+name, []byte(script), 0755); hooksPathErr != nil {
+				return
+			}
+		}
+	})
+
+	return hooksPath, hooksPathErr
+}
+
+// hookEnv Returns the environment passed through git binary to the hooks.
+func hookEnv(repoID int64, pushInfo *PushInfo) []string {
+	return []string{
+		fmt.Sprintf("%s=%d", HookRepositoryEnv, repoID),
+		fmt.Sprintf("%s=%d", HookPusherEnv, pushInfo.PusherID),
+		fmt.Sprintf("%s=%s", HookProtocolEnv, pushInfo.Protocol),
+		fmt.Sprintf("%s=%s", HookRemoteAddrEnv, pushInfo.RemoteAddr),
+	}
+}
+
+// getHookRepo Returns the repository and the push running the hook.
+func getHookRepo(ctx context.Context) (*Repo, *PushInfo, error) {
+	repoID, err := strconv.ParseInt(os.Getenv(HookRepositoryEnv), 10, 64)
+	if err != nil {
+		return nil, nil, fmt.Errorf("missing %s in the hook environment", HookRepositoryEnv)
+	}
+
+	pusherID, err := strconv.ParseInt(os.Getenv(HookPusherEnv), 10, 64)
+	if err != nil {
+		return nil, nil, fmt.Errorf("missing %s in the hook environment", HookPusherEnv)
+	}
+
+	if repo, err := GetRepoById(ctx, repoID); err != nil {
+		return nil, nil, err
+	} else {
+		return repo, &PushInfo{
+			PusherID:   pusherID,
+			Protocol:   os.Getenv(HookProtocolEnv),
+			RemoteAddr: os.Getenv(HookRemoteAddrEnv),
+		}, nil
+	}
+}
+
+// RunUpdateHook Checks a reference update received by git binary against the
+// branch protection rules. Git rejects the reference if it returns an error.
+func RunUpdateHook(ctx context.Context, name string, oldHash string, newHash string) error {
+	repo, pushInfo, err := getHookRepo(ctx)
+	if err != nil {
+		return err
+	}
+
+	cmd := &packp.Command{
+		Name: plumbing.ReferenceName(name),
+		Old:  plumbing.NewHash(oldHash),
+		New:  plumbing.NewHash(newHash),
+	}
+
+	if rejections, err := repo.CheckRefUpdates(pushInfo.PusherID, []*packp.Command{cmd}); err != nil {
+		return err
+	} else {
+		return rejections[cmd.Name]
+	}
+}
+
+// readHookCommands Parses the `<old> <new> <ref>` lines which git binary feeds
+// to the pre-receive and post-receive hooks.
+func readHookCommands(r io.Reader) ([]*packp.Command, error) {
+	var commands []*packp.Command
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 3 {
+			return nil, fmt.Errorf("malformed hook input: %s", scanner.Text())
+		}
+
+		commands = append(commands, &packp.Command{
+			Name: plumbing.ReferenceName(fields[2]),
+			Old:  plumbing.NewHash(fields[0]),
+			New:  plumbing.NewHash(fields[1]),
+		})
+	}
+
+	return commands, scanner.Err()
+}
+
+// RunPreReceiveHook Runs the registered pre-receive hooks for a push received
+// by git binary. Git rejects the whole push if it returns an error.
+func RunPreReceiveHook(ctx context.Context, r io.Reader) error {
+	repo, pushInfo, err := getHookRepo(ctx)
+	if err != nil {
+		return err
+	}
+
+	if commands, err := readHookCommands(r); err != nil {
+		return err
+	} else {
+		return runPreReceiveHooks(withPushInfo(ctx, pushInfo), repo, commands)
+	}
+}
+
+// RunPostReceiveHook Runs the registered post-receive hooks for a push received
+// by git binary.
+func RunPostReceiveHook(ctx context.Context, r io.Reader) error {
+	repo, pushInfo, err := getHookRepo(ctx)
+	if err != nil {
+		return err
+	}
+
+	if updates, err := readHookCommands(r); err != nil {
+		return err
+	} else {
+		runPostReceiveHooks(withPushInfo(ctx, pushInfo), repo, updates)
+		return nil
+	}
+}
@@ -0,0 +1,89 @@
+/*
+ * Copyright 2021 Meraj Sahebdar
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package facade
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/protocol/packp"
+)
+
+// testReceiveHook
+type testReceiveHook struct {
+	err     error
+	updates []*packp.Command
+}
+
+// PreReceive
+func (h *testReceiveHook) PreReceive(ctx context.Context, repo *Repo, commands []*packp.Command) error {
+	return h.err
+}
+
+// PostReceive
+func (h *testReceiveHook) PostReceive(ctx context.Context, repo *Repo, updates []*packp.Command) error {
+	h.updates = updates
+	return h.err
+}
+
+func TestReceiveHook(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("read commands", func(t *testing.T) {
+		input := strings.Join([]string{
+			"0000000000000000000000000000000000000000 1111111111111111111111111111111111111111 refs/heads/main",
+			"1111111111111111111111111111111111111111 0000000000000000000000000000000000000000 refs/tags/v1",
+		}, "\n")
+
+		if commands, err := readHookCommands(strings.NewReader(input)); err != nil {
+			t.Errorf("got an unexpected error: %s", err.Error())
+		} else if len(commands) != 2 {
+			t.Errorf("expected 2 commands, got: %d", len(commands))
+		} else if commands[0].Action() != packp.Create || commands[1].Action() != packp.Delete {
+			t.Errorf("expected create and delete commands, got: %s and %s", commands[0].Action(), commands[1].Action())
+		} else if commands[1].Name != plumbing.ReferenceName("refs/tags/v1") {
+			t.Errorf("expected refs/tags/v1, got: %s", commands[1].Name)
+		}
+
+		if _, err := readHookCommands(strings.NewReader("malformed")); err == nil {
+			t.Error("expected an error for a malformed input")
+		}
+	})
+
+	t.Run("run hooks", func(t *testing.T) {
+		defer func() { receiveHooks = nil }()
+
+		errRejected := errors.New("rejected")
+		accepting := &testReceiveHook{}
+		rejecting := &testReceiveHook{err: errRejected}
+
+		RegisterReceiveHooks(accepting, rejecting)
+
+		commands := []*packp.Command{{Name: plumbing.ReferenceName("refs/heads/main")}}
+		if err := runPreReceiveHooks(ctx, nil, commands); err != errRejected {
+			t.Errorf("expected the push to be rejected, got: %v", err)
+		}
+
+		runPostReceiveHooks(ctx, nil, commands)
+		if len(accepting.updates) != 1 || len(rejecting.updates) != 1 {
+			t.Error("expected every post-receive hook to run")
+		}
+	})
+}
@@ -0,0 +1,309 @@
+/*
+ * Copyright 2021 Meraj Sahebdar
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package facade
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strconv"
+	"time"
+
+	gojwt "github.com/dgrijalva/jwt-go"
+	"github.com/go-git/go-billy/v5"
+	"github.com/go-git/go-billy/v5/osfs"
+	"bitban.io/server/internal/cfg"
+	"bitban.io/server/internal/pkg/auth"
+	"bitban.io/server/internal/pkg/dto"
+	"bitban.io/server/internal/pkg/jwt"
+)
+
+// Git LFS operations
+const (
+	LfsUpload   = "upload"
+	LfsDownload = "download"
+)
+
+// GitLfsAuthenticate The command git lfs runs over ssh to obtain the
+// credentials of the http api.
+const GitLfsAuthenticate = "git-lfs-authenticate"
+
+var (
+	// ErrInvalidLfsOid
+	ErrInvalidLfsOid = errors.New("the lfs object id must be a sha256 hex digest")
+
+	// ErrLfsObjectNotFound
+	ErrLfsObjectNotFound = errors.New("the requested lfs object does not exist")
+
+	// ErrLfsObjectMismatch
+	ErrLfsObjectMismatch = errors.New("the lfs object does not match its id or size")
+)
+
+// lfsOidRegex
+var lfsOidRegex = regexp.MustCompile(`^[0-9a-f]{64}$`)
+
+// IsValidLfsOid Checks whether the id is a sha256 hex digest, as git lfs
+// names its objects.
+func IsValidLfsOid(oid string) bool {
+	return lfsOidRegex.MatchString(oid)
+}
+
+// LfsActionOf Returns the permission needed to perform a git lfs operation.
+func LfsActionOf(operation string) string {
+	if operation == LfsUpload {
+		return "write"
+	}
+
+	return "read"
+}
+
+// getLfsFs Returns the filesystem the lfs objects are stored on, in which the
+// storage paths are absolute.
+func getLfsFs() (billy.Filesystem, error) {
+	if cfg.Cog.Git.Storage == cfg.GitStorageFs {
+		return osfs.New("/"), nil
+	}
+
+	return getFs("/")
+}
+
+// getLfsPath Returns the path the lfs objects of a repository are kept in.
+// It is keyed by the id, so renames, transfers and deletions of the
+// repository leave it in place.
+func getLfsPath(repoID int64, elem ...string) (string, error) {
+	return getStoragePath(append([]string{"lfs", strconv.FormatInt(repoID, 10)}, elem...)...)
+}
+
+// getLfsObjectPath
+//
+// Errors:
+//   - facade.ErrInvalidLfsOid if the id is not a sha256 hex digest
+func (f *Repo) getLfsObjectPath(oid string) (string, error) {
+	if !IsValidLfsOid(oid) {
+		return "", ErrInvalidLfsOid
+	}
+
+	return getLfsPath(f.GetID(), "objects", oid[0:2], oid[2:4], oid)
+}
+
+// GetLfsObjectSize Returns the size of a stored lfs object.
+//
+// Errors:
+//   - facade.ErrInvalidLfsOid if the id is not a sha256 hex digest
+//   - facade.ErrLfsObjectNotFound if the object is not uploaded
+func (f *Repo) GetLfsObjectSize(oid string) (int64, error) {
+	path, err := f.getLfsObjectPath(oid)
+	if err != nil {
+		return 0, err
+	}
+
+	fs, err := getLfsFs()
+	if err != nil {
+		return 0, err
+	}
+
+	if info, err := fs.Stat(path); os.IsNotExist(err) {
+		return 0, ErrLfsObjectNotFound
+	} else if err != nil {
+		return 0, err
+	} else {
+		return info.Size(), nil
+	}
+}
+
+// OpenLfsObject Opens a stored lfs object for reading, returning its size.
+//
+// Errors:
+//   - facade.ErrInvalidLfsOid if the id is not a sha256 hex digest
+//   - facade.ErrLfsObjectNotFound if the object is not uploaded
+func (f *Repo) OpenLfsObject(oid string) (io.ReadCloser, int64, error) {
+	size, err := f.GetLfsObjectSize(oid)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	path, _ := f.getLfsObjectPath(oid)
+	fs, err := getLfsFs()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if file, err := fs.Open(path); os.IsNotExist(err) {
+		return nil, 0, ErrLfsObjectNotFound
+	} else if err != nil {
+		return nil, 0, err
+	} else {
+		return file, size, nil
+	}
+}
+
+// WriteLfsObject Stores an lfs object read from the reader. The content is
+// written to a temporary file first and only moved in place once its digest
+// and size match the id and the announced size, so a broken upload never
+// replaces or shadows a valid object.
+//
+// Errors:
+//   - facade.ErrInvalidLfsOid if the id is not a sha256 hex digest
+//   - facade.ErrLfsObjectMismatch if the content does not match the id or size
+//   - facade.ErrMirrorReadOnly if the repository is a mirror
+func (f *Repo) WriteLfsObject(oid string, size int64, r io.Reader) error {
+	path, err := f.getLfsObjectPath(oid)
+	if err != nil {
+		return err
+	}
+
+	if err := f.checkWritable(); err != nil {
+		return err
+	}
+
+	tmpPath, err := getStoragePath("lfs", "tmp")
+	if err != nil {
+		return err
+	}
+
+	fs, err := getLfsFs()
+	if err != nil {
+		return err
+	}
+
+	if err := fs.MkdirAll(tmpPath, 0755); err != nil {
+		return err
+	}
+
+	tmp, err := fs.TempFile(tmpPath, oid)
+	if err != nil {
+		return err
+	}
+	defer fs.Remove(tmp.Name())
+
+	hash := sha256.New()
+	written, err := io.Copy(io.MultiWriter(tmp, hash), io.LimitReader(r, size+1))
+	if err != nil {
+		tmp.Close()
+		return err
+	}
+
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	if written != size || hex.EncodeToString(hash.Sum(nil)) != oid {
+		return ErrLfsObjectMismatch
+	}
+
+	if err := fs.MkdirAll(fs.Join(path, ".."), 0755); err != nil {
+		return err
+	}
+
+	return fs.Rename(tmp.Name(), path)
+}
+
+// VerifyLfsObject Checks whether an lfs object is stored with the size.
+//
+// Errors:
+//   - facade.ErrLfsObjectMismatch if the stored object has another size
+// ErrorsRef:
+//   - facade.Repo.GetLfsObjectSize
+func (f *Repo) VerifyLfsObject(oid string, size int64) error {
+	if stored, err := f.GetLfsObjectSize(oid); err != nil {
+		return err
+	} else if stored != size {
+		return ErrLfsObjectMismatch
+	}
+
+	return nil
+}
+
+// forkLfsStorage Clones the lfs objects of the repository for a fork.
+func (f *Repo) forkLfsStorage(forkID int64) error {
+	from, err := getLfsPath(f.GetID())
+	if err != nil {
+		return err
+	}
+
+	to, err := getLfsPath(forkID)
+	if err != nil {
+		return err
+	}
+
+	fs, err := getLfsFs()
+	if err != nil {
+		return err
+	}
+
+	if _, err := fs.Stat(from); os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	return forkStorage(from, to)
+}
+
+// lfsAudience Returns the audience of the tokens granting the git lfs
+// operation on a repository.
+func lfsAudience(repoID int64, operation string) string {
+	return fmt.Sprintf("lfs:%d:%s", repoID, operation)
+}
+
+// CreateLfsToken Creates a token granting the git lfs operation on the
+// repository to the account, returning it along with its lifetime. Unlike the
+// access tokens, it is not accepted by the rest of the api.
+func (f *Account) CreateLfsToken(repo *Repo, operation string) (string, time.Duration, error) {
+	lifetime := time.Duration(cfg.Cog.Security.AccessTokenExpiresAt) * time.Minute
+	currTime := time.Now().In(time.UTC)
+	claims := &gojwt.StandardClaims{
+		Subject:   dto.ToNodeIdentifier(dto.UserNodeType, f.user.DomainID),
+		Audience:  lfsAudience(repo.GetID(), operation),
+		IssuedAt:  currTime.Unix(),
+		ExpiresAt: currTime.Add(lifetime).Unix(),
+	}
+
+	if token, err := jwt.GetJwtInstance().SignToken(claims); err != nil {
+		return "", 0, err
+	} else {
+		return token, lifetime, nil
+	}
+}
+
+// GetAccountByLfsToken Returns the account of the bearer token if it grants
+// the git lfs operation on the repository. The upload tokens grant the
+// downloads as well, since the clients reuse them to verify the uploads.
+//
+// ErrorsRef:
+//   - auth.GetContextAudienceTokenClaims
+//   - facade.GetAccountByUserId
+func GetAccountByLfsToken(ctx context.Context, repo *Repo, operation string) (*Account, error) {
+	audiences := []string{lfsAudience(repo.GetID(), operation)}
+	if operation == LfsDownload {
+		audiences = append(audiences, lfsAudience(repo.GetID(), LfsUpload))
+	}
+
+	if claims, err := auth.GetContextAudienceTokenClaims(ctx, audiences...); err != nil {
+		return nil, err
+	} else {
+		return GetAccountByUserId(
+			ctx,
+			dto.MustRetrieveIdentifier(claims.Subject),
+		)
+	}
+}
@@ -0,0 +1,101 @@
+/*
+ * Copyright 2021 Meraj Sahebdar
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package facade
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"syreclabs.com/go/faker"
+)
+
+func TestIsValidLfsOid(t *testing.T) {
+	for oid, expected := range map[string]bool{
+		strings.Repeat("a", 64):         true,
+		strings.Repeat("0f", 32):        true,
+		strings.Repeat("A", 64):         false,
+		strings.Repeat("a", 63):         false,
+		"../" + strings.Repeat("a", 61): false,
+		"":                              false,
+	} {
+		if actual := IsValidLfsOid(oid); actual != expected {
+			t.Errorf("expected IsValidLfsOid(%q) to be %v, got: %v", oid, expected, actual)
+		}
+	}
+}
+
+func TestLfsObject(t *testing.T) {
+	t.Run("lfsObject", func(t *testing.T) {
+		ctx := context.Background()
+
+		account := getTestAccount(t, ctx)
+		repo := createTestRepo(t, ctx)
+
+		content := []byte(faker.Lorem().Paragraph(3))
+		sum := sha256.Sum256(content)
+		oid := hex.EncodeToString(sum[:])
+		size := int64(len(content))
+
+		if _, err := repo.GetLfsObjectSize(oid); err != ErrLfsObjectNotFound {
+			t.Errorf("expected error: %v, got: %v", ErrLfsObjectNotFound, err)
+		}
+
+		if err := repo.WriteLfsObject(oid, size-1, bytes.NewReader(content)); err != ErrLfsObjectMismatch {
+			t.Errorf("expected error: %v, got: %v", ErrLfsObjectMismatch, err)
+		}
+
+		if err := repo.WriteLfsObject(oid, size, bytes.NewReader(append(content, '.'))); err != ErrLfsObjectMismatch {
+			t.Errorf("expected error: %v, got: %v", ErrLfsObjectMismatch, err)
+		}
+
+		if err := repo.WriteLfsObject(oid, size, bytes.NewReader(content)); err != nil {
+			t.Fatalf("failed to write the object, got error: %s", err.Error())
+		}
+
+		if err := repo.VerifyLfsObject(oid, size); err != nil {
+			t.Errorf("expected the object to be verified, got error: %s", err.Error())
+		}
+
+		if err := repo.VerifyLfsObject(oid, size+1); err != ErrLfsObjectMismatch {
+			t.Errorf("expected error: %v, got: %v", ErrLfsObjectMismatch, err)
+		}
+
+		if r, n, err := repo.OpenLfsObject(oid); err != nil {
+			t.Fatalf("failed to open the object, got error: %s", err.Error())
+		} else {
+			defer r.Close()
+
+			if stored, _ := ioutil.ReadAll(r); n != size || !bytes.Equal(stored, content) {
+				t.Errorf("expected the stored object to match the written one")
+			}
+		}
+
+		fork, err := repo.Fork(account.GetDomain(), faker.Internet().Slug())
+		if err != nil {
+			t.Fatalf("failed to fork the repository, got error: %s", err.Error())
+		}
+
+		if err := fork.VerifyLfsObject(oid, size); err != nil {
+			t.Errorf("expected the fork to have the object, got error: %s", err.Error())
+		}
+	})
+}
@@ -0,0 +1,56 @@
+/*
+ * Copyright 2021 Meraj Sahebdar
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+
+package facade
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"bitban.io/server/internal/cfg"
+)
+
+func TestOutboundHTTPClient(t *testing.T) {
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("internal"))
+	}))
+	defer target.Close()
+
+	redirector := httptest.NewServer(http.RedirectHandler(target.URL, http.StatusFound))
+	defer redirector.Close()
+
+	client := newOutboundHTTPClient(0)
+
+	if _, err := client.Get(target.URL); !errors.Is(err, ErrAddressForbidden) {
+		t.Errorf("expected error: %v, got: %v", ErrAddressForbidden, err)
+	}
+
+	cfg.Cog.Security.AllowPrivateNetworks = true
+	defer func() { cfg.Cog.Security.AllowPrivateNetworks = false }()
+
+	res, err := client.Get(redirector.URL)
+	if err != nil {
+		t.Fatalf("got an unexpected error: %s", err.Error())
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusFound {
+		t.Errorf("expected the redirect not to be followed, got status code: %d", res.StatusCode)
+	}
+}
@@ -0,0 +1,374 @@
+/*
+ * Copyright 2021 Meraj Sahebdar
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package facade
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/uptrace/bun"
+	"bitban.io/server/internal/pkg/auth"
+	"bitban.io/server/internal/pkg/fault"
+	"bitban.io/server/internal/pkg/orm"
+	"bitban.io/server/internal/pkg/orm/entity"
+)
+
+// Organization Roles
+const (
+	OrganizationRoleOwner  = "owner"
+	OrganizationRoleMember = "member"
+)
+
+var (
+	// ErrInvalidMemberRole
+	ErrInvalidMemberRole = errors.New("the member role must be owner or member")
+
+	// ErrMemberExists
+	ErrMemberExists = errors.New("the user is already a member of the organization")
+
+	// ErrLastOwner
+	ErrLastOwner = errors.New("the organization must keep at least one owner")
+)
+
+// Organization
+type Organization struct {
+	ctx          context.Context
+	organization *entity.Organization
+}
+
+// Member
+type Member struct {
+	Account *Account
+	Role    string
+}
+
+// GetEntity
+func (f *Organization) GetEntity() *entity.Organization {
+	return f.organization
+}
+
+// GetID
+func (f *Organization) GetID() int64 {
+	return f.organization.DomainID
+}
+
+// GetDomain
+func (f *Organization) GetDomain() *entity.Domain {
+	return f.organization.Domain
+}
+
+// organizationRoleSubject Returns the casbin subject of a role within an
+// organization, which its members are grouped into.
+func organizationRoleSubject(organizationID int64, role string) string {
+	return fmt.Sprintf("/organizations/%d/roles/%s", organizationID, role)
+}
+
+// organizationRolePolicies Returns the policies of the roles within the
+// organization. The owners administer the organization and every repository
+// of its domain, while the members may only read them.
+func (f *Organization) organizationRolePolicies() [][]string {
+	dom := f.GetDomain().Address
+	obj := fmt.Sprintf("/organizations/%d", f.GetID())
+
+	owner := organizationRoleSubject(f.GetID(), OrganizationRoleOwner)
+	member := organizationRoleSubject(f.GetID(), OrganizationRoleMember)
+
+	return [][]string{
+		{owner, dom, obj, ".*"},
+		{owner, dom, obj + "/*", ".*"},
+		{owner, dom, "/repositories/*", ".*"},
+		{member, dom, obj, "^read$"},
+		{member, dom, "/repositories/*", "^read$"},
+	}
+}
+
+// isMemberRole
+func isMemberRole(role string) bool {
+	return role == OrganizationRoleOwner || role == OrganizationRoleMember
+}
+
+// GetOrganizationById
+func GetOrganizationById(ctx context.Context, id int64) (*Organization, error) {
+	organization := new(entity.Organization)
+	if err := orm.GetBunInstance().
+		NewSelect().
+		Model(organization).
+		Relation("Domain").
+		Where("? = ?", bun.Ident("organization.domain_id"), id).
+		Where("? IS NULL", bun.Ident("organization.removed_at")).
+		Scan(ctx); err != nil {
+		return nil, err
+	}
+
+	return &Organization{
+		ctx:          ctx,
+		organization: organization,
+	}, nil
+}
+
+// GetOrganizationByAddress
+//
+// ErrorsRef:
+//   - facade.GetDomainByAddress
+//   - facade.GetOrganizationById
+func GetOrganizationByAddress(ctx context.Context, address string) (*Organization, error) {
+	if domain, err := GetDomainByAddress(ctx, address); err != nil {
+		return nil, err
+	} else if domain.Type != DomainTypeOrganization {
+		return nil, fault.ErrResourceNotFound
+	} else {
+		return GetOrganizationById(ctx, domain.ID)
+	}
+}
+
+// CreateOrganization Creates an organization domain owned by the account.
+func CreateOrganization(ctx context.Context, owner *Account, name string, address string) (org *Organization, err error) {
+	var tx bun.Tx
+	if tx, err = orm.GetBunInstance().BeginTx(ctx, nil); err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if err != nil && org == nil {
+			tx.Rollback()
+		}
+	}()
+
+	domain := &entity.Domain{
+		Type:    DomainTypeOrganization,
+		Name:    name,
+		Address: address,
+		Meta:    struct{}{},
+	}
+	if _, err = tx.NewInsert().
+		Model(domain).
+		Column("type", "name", "address", "meta").
+		Returning("id", "created_at", "updated_at").
+		Exec(ctx); err != nil {
+		return nil, err
+	}
+
+	organization := &entity.Organization{
+		DomainID:   domain.ID,
+		DomainType: domain.Type,
+		Domain:     domain,
+	}
+	if _, err = tx.NewInsert().
+		Model(organization).
+		Column("domain_id", "domain_type").
+		Returning("created_at", "updated_at").
+		Exec(ctx); err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	created := &Organization{
+		ctx:          ctx,
+		organization: organization,
+	}
+
+	if _, err = auth.GetEnforcerInstance().AddNamedPolicies("p", created.organizationRolePolicies()); err != nil {
+		return nil, err
+	}
+
+	if _, err = created.AddMember(owner, OrganizationRoleOwner); err != nil {
+		return nil, err
+	}
+
+	return created, nil
+}
+
+// GetMembers Returns the members of the organization along with their roles,
+// the earliest registered users first.
+func (f *Organization) GetMembers() ([]*Member, error) {
+	enforcer := auth.GetEnforcerInstance()
+
+	members := []*Member{}
+	for _, role := range []string{OrganizationRoleOwner, OrganizationRoleMember} {
+		for _, rule := range enforcer.GetFilteredNamedGroupingPolicy(
+			"g",
+			1,
+			organizationRoleSubject(f.GetID(), role),
+			f.GetDomain().Address,
+		) {
+			id, err := strconv.ParseInt(strings.TrimPrefix(rule[0], "/users/"), 10, 64)
+			if err != nil {
+				continue
+			}
+
+			if account, err := GetAccountByUserId(f.ctx, id); err == fault.ErrResourceNotFound {
+				continue
+			} else if err != nil {
+				return nil, err
+			} else {
+				members = append(members, &Member{
+					Account: account,
+					Role:    role,
+				})
+			}
+		}
+	}
+
+	sort.Slice(members, func(i, j int) bool {
+		return members[i].Account.GetUser().DomainID < members[j].Account.GetUser().DomainID
+	})
+
+	return members, nil
+}
+
+// GetMember Returns the role of the account within the organization.
+//
+// Errors:
+//   - fault.ErrResourceNotFound if the account is not a member
+func (f *Organization) GetMember(account *Account) (*Member, error) {
+	enforcer := auth.GetEnforcerInstance()
+
+	sub := fmt.Sprintf("/users/%d", account.GetUser().DomainID)
+	for _, role := range []string{OrganizationRoleOwner, OrganizationRoleMember} {
+		if rules := enforcer.GetFilteredNamedGroupingPolicy(
+			"g",
+			0,
+			sub,
+			organizationRoleSubject(f.GetID(), role),
+			f.GetDomain().Address,
+		); len(rules) > 0 {
+			return &Member{
+				Account: account,
+				Role:    role,
+			}, nil
+		}
+	}
+
+	return nil, fault.ErrResourceNotFound
+}
+
+// countOwners
+func (f *Organization) countOwners() int {
+	return len(auth.GetEnforcerInstance().GetFilteredNamedGroupingPolicy(
+		"g",
+		1,
+		organizationRoleSubject(f.GetID(), OrganizationRoleOwner),
+		f.GetDomain().Address,
+	))
+}
+
+// AddMember Adds the account to the organization with the role.
+//
+// Errors:
+//   - facade.ErrInvalidMemberRole if the role is unknown
+//   - facade.ErrMemberExists if the account is already a member
+func (f *Organization) AddMember(account *Account, role string) (*Member, error) {
+	if !isMemberRole(role) {
+		return nil, ErrInvalidMemberRole
+	}
+
+	if _, err := f.GetMember(account); err == nil {
+		return nil, ErrMemberExists
+	} else if err != fault.ErrResourceNotFound {
+		return nil, err
+	}
+
+	if _, err := auth.GetEnforcerInstance().AddNamedGroupingPolicy(
+		"g",
+		fmt.Sprintf("/users/%d", account.GetUser().DomainID),
+		organizationRoleSubject(f.GetID(), role),
+		f.GetDomain().Address,
+	); err != nil {
+		return nil, err
+	}
+
+	return &Member{
+		Account: account,
+		Role:    role,
+	}, nil
+}
+
+// UpdateMemberRole Replaces the role of a member within the organization.
+//
+// Errors:
+//   - facade.ErrInvalidMemberRole if the role is unknown
+//   - facade.ErrLastOwner if the member is the only owner of the organization
+// ErrorsRef:
+//   - facade.Organization.GetMember
+func (f *Organization) UpdateMemberRole(account *Account, role string) (*Member, error) {
+	if !isMemberRole(role) {
+		return nil, ErrInvalidMemberRole
+	}
+
+	member, err := f.GetMember(account)
+	if err != nil {
+		return nil, err
+	} else if member.Role == role {
+		return member, nil
+	}
+
+	if member.Role == OrganizationRoleOwner && f.countOwners() <= 1 {
+		return nil, ErrLastOwner
+	}
+
+	if err := f.revokeMemberRole(member); err != nil {
+		return nil, err
+	}
+
+	return f.AddMember(account, role)
+}
+
+// revokeMemberRole
+func (f *Organization) revokeMemberRole(member *Member) error {
+	_, err := auth.GetEnforcerInstance().RemoveNamedGroupingPolicy(
+		"g",
+		fmt.Sprintf("/users/%d", member.Account.GetUser().DomainID),
+		organizationRoleSubject(f.GetID(), member.Role),
+		f.GetDomain().Address,
+	)
+
+	return err
+}
+
+// RemoveMember Removes the account from the organization and its teams.
+//
+// Errors:
+//   - facade.ErrLastOwner if the member is the only owner of the organization
+// ErrorsRef:
+//   - facade.Organization.GetMember
+func (f *Organization) RemoveMember(account *Account) (*Member, error) {
+	member, err := f.GetMember(account)
+	if err != nil {
+		return nil, err
+	}
+
+	if member.Role == OrganizationRoleOwner && f.countOwners() <= 1 {
+		return nil, ErrLastOwner
+	}
+
+	if err := f.revokeMemberRole(member); err != nil {
+		return nil, err
+	}
+
+	if err := f.removeTeamMemberships(account); err != nil {
+		return nil, err
+	}
+
+	return member, nil
+}
@@ -0,0 +1,112 @@
+/*
+ * Copyright 2021 Meraj Sahebdar
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package facade
+
+import (
+	"context"
+	"testing"
+
+	"bitban.io/server/internal/pkg/fault"
+	"syreclabs.com/go/faker"
+)
+
+func TestOrganization(t *testing.T) {
+	t.Run("organization", func(t *testing.T) {
+		ctx := context.Background()
+
+		owner := getTestAccount(t, ctx)
+		account := createTestAccount(t, ctx)
+
+		org, err := CreateOrganization(ctx, owner, faker.Company().Name(), faker.Internet().Slug())
+		if err != nil {
+			t.Fatalf("failed to create the organization, got error: %s", err.Error())
+		}
+
+		if found, err := GetOrganizationByAddress(ctx, org.GetDomain().Address); err != nil {
+			t.Errorf("failed to find the organization, got error: %s", err.Error())
+		} else if found.GetID() != org.GetID() {
+			t.Errorf("expected organization %d, got: %d", org.GetID(), found.GetID())
+		}
+
+		if _, err := GetOrganizationByAddress(ctx, owner.GetDomain().Address); err != fault.ErrResourceNotFound {
+			t.Errorf("expected error: %v, got: %v", fault.ErrResourceNotFound, err)
+		}
+
+		if err := owner.CheckDomainPermission(org.GetDomain(), "admin"); err != nil {
+			t.Errorf("expected the creator to administer the organization, got error: %s", err.Error())
+		}
+
+		if err := account.CheckDomainPermission(org.GetDomain(), "read"); err != fault.ErrForbidden {
+			t.Errorf("expected error: %v, got: %v", fault.ErrForbidden, err)
+		}
+
+		if _, err := org.AddMember(account, "admin"); err != ErrInvalidMemberRole {
+			t.Errorf("expected error: %v, got: %v", ErrInvalidMemberRole, err)
+		}
+
+		if _, err := org.AddMember(account, OrganizationRoleMember); err != nil {
+			t.Fatalf("failed to add the member, got error: %s", err.Error())
+		}
+
+		if _, err := org.AddMember(account, OrganizationRoleOwner); err != ErrMemberExists {
+			t.Errorf("expected error: %v, got: %v", ErrMemberExists, err)
+		}
+
+		if err := account.CheckDomainPermission(org.GetDomain(), "read"); err != nil {
+			t.Errorf("expected the member to read the organization, got error: %s", err.Error())
+		}
+
+		if err := account.CheckDomainPermission(org.GetDomain(), "admin"); err != fault.ErrForbidden {
+			t.Errorf("expected error: %v, got: %v", fault.ErrForbidden, err)
+		}
+
+		repo, err := CreateRepoByAddress(ctx, org.GetDomain().Address, faker.Internet().Slug())
+		if err != nil {
+			t.Fatalf("failed to create the repository, got error: %s", err.Error())
+		}
+
+		if err := CheckRepoPermission(account, repo, "read"); err != nil {
+			t.Errorf("expected the member to read the repository, got error: %s", err.Error())
+		}
+
+		if err := CheckRepoPermission(account, repo, "write"); err != fault.ErrForbidden {
+			t.Errorf("expected error: %v, got: %v", fault.ErrForbidden, err)
+		}
+
+		if err := CheckRepoPermission(owner, repo, "admin"); err != nil {
+			t.Errorf("expected the owner to administer the repository, got error: %s", err.Error())
+		}
+
+		if _, err := org.RemoveMember(owner); err != ErrLastOwner {
+			t.Errorf("expected error: %v, got: %v", ErrLastOwner, err)
+		}
+
+		if _, err := org.UpdateMemberRole(account, OrganizationRoleOwner); err != nil {
+			t.Fatalf("failed to update the member role, got error: %s", err.Error())
+		}
+
+		if _, err := org.RemoveMember(owner); err != nil {
+			t.Errorf("failed to remove the former owner, got error: %s", err.Error())
+		}
+
+		if members, err := org.GetMembers(); err != nil {
+			t.Errorf("failed to get the members, got error: %s", err.Error())
+		} else if len(members) != 1 || members[0].Role != OrganizationRoleOwner {
+			t.Errorf("expected a single owner, got: %v", members)
+		}
+	})
+}
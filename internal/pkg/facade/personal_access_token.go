@@ -0,0 +1,232 @@
+/*
+ * Copyright 2021 Meraj Sahebdar
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package facade
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/uptrace/bun"
+	"github.com/volatiletech/null/v8"
+	"bitban.io/server/internal/pkg/auth"
+	"bitban.io/server/internal/pkg/fault"
+	"bitban.io/server/internal/pkg/orm"
+	"bitban.io/server/internal/pkg/orm/entity"
+	"bitban.io/server/internal/pkg/util"
+)
+
+// Personal Access Token Scopes
+const (
+	ScopeRepoRead  = "repo:read"
+	ScopeRepoWrite = "repo:write"
+	ScopeApi       = "api"
+)
+
+// personalAccessTokenBytes
+const personalAccessTokenBytes = 20
+
+var (
+	// ErrInvalidAccessTokenScope
+	ErrInvalidAccessTokenScope = errors.New("the scopes must be repo:read, repo:write or api")
+
+	// ErrAccessTokenExpired
+	ErrAccessTokenExpired = errors.New("the expiry of the access token must be in the future")
+)
+
+// RepoScopeOf Returns the scope a personal access token needs to perform the
+// action on a repository.
+func RepoScopeOf(act string) string {
+	if act == "read" {
+		return ScopeRepoRead
+	}
+
+	return ScopeRepoWrite
+}
+
+// isAccessTokenScope
+func isAccessTokenScope(scope string) bool {
+	return scope == ScopeRepoRead || scope == ScopeRepoWrite || scope == ScopeApi
+}
+
+// hasAccessTokenScope Checks whether the scopes include the scope, the write
+// access to the repositories including the read one.
+func hasAccessTokenScope(scopes []string, scope string) bool {
+	for _, s := range scopes {
+		if s == scope || (s == ScopeRepoWrite && scope == ScopeRepoRead) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// GetPersonalAccessTokens Returns the active personal access tokens of the
+// account, including the expired ones.
+func (f *Account) GetPersonalAccessTokens() ([]*entity.PersonalAccessToken, error) {
+	var tokens []*entity.PersonalAccessToken
+	if err := orm.GetBunInstance().
+		NewSelect().
+		Model(&tokens).
+		Where("? = ?", bun.Ident("personal_access_token.user_id"), f.user.DomainID).
+		Where("? IS NULL", bun.Ident("personal_access_token.removed_at")).
+		Order("personal_access_token.id").
+		Scan(f.ctx); err != nil {
+		return nil, err
+	}
+
+	return tokens, nil
+}
+
+// CreatePersonalAccessToken Creates a personal access token of the account,
+// which never expires if the expiry is null. Only the digest of the token is
+// stored, so it is returned along with the entity once and for all.
+//
+// Errors:
+//   - facade.ErrInvalidAccessTokenScope if a scope is unknown
+//   - facade.ErrAccessTokenExpired if the expiry is not in the future
+func (f *Account) CreatePersonalAccessToken(name string, scopes []string, expiresAt null.Time) (*entity.PersonalAccessToken, string, error) {
+	if len(scopes) == 0 {
+		return nil, "", ErrInvalidAccessTokenScope
+	}
+
+	for _, scope := range scopes {
+		if !isAccessTokenScope(scope) {
+			return nil, "", ErrInvalidAccessTokenScope
+		}
+	}
+
+	if expiresAt.Valid && !expiresAt.Time.After(time.Now()) {
+		return nil, "", ErrAccessTokenExpired
+	}
+
+	secret, err := util.RandomHex(personalAccessTokenBytes)
+	if err != nil {
+		return nil, "", err
+	}
+	token := auth.PersonalAccessTokenPrefix + secret
+
+	pat := &entity.PersonalAccessToken{
+		Name:      name,
+		TokenHash: util.HashToken(token),
+		Scopes:    scopes,
+		ExpiresAt: expiresAt,
+		UserID:    null.Int64From(f.user.DomainID),
+	}
+	if _, err := orm.GetBunInstance().
+		NewInsert().
+		Model(pat).
+		Column("name", "token_hash", "scopes", "expires_at", "user_id").
+		Returning("id", "created_at", "updated_at").
+		Exec(f.ctx); err != nil {
+		return nil, "", err
+	}
+
+	return pat, token, nil
+}
+
+// RevokePersonalAccessToken
+//
+// Errors:
+//   - fault.ErrResourceNotFound if the account does not own such a token
+func (f *Account) RevokePersonalAccessToken(id int64) (*entity.PersonalAccessToken, error) {
+	pat := new(entity.PersonalAccessToken)
+	if err := orm.GetBunInstance().
+		NewSelect().
+		Model(pat).
+		Where("? = ?", bun.Ident("personal_access_token.id"), id).
+		Where("? = ?", bun.Ident("personal_access_token.user_id"), f.user.DomainID).
+		Where("? IS NULL", bun.Ident("personal_access_token.removed_at")).
+		Limit(1).
+		Scan(f.ctx); err != nil {
+		return nil, err
+	}
+
+	pat.RemovedAt = null.TimeFrom(time.Now().In(time.UTC))
+	if _, err := orm.GetBunInstance().
+		NewUpdate().
+		Model(pat).
+		Column("removed_at").
+		WherePK().
+		Exec(f.ctx); err != nil {
+		return nil, err
+	}
+
+	return pat, nil
+}
+
+// GetAccountByPersonalAccessToken Finds the account owning an unexpired
+// personal access token granted the scope, and records its use.
+//
+// Errors:
+//   - fault.ErrUnauthenticated if was not able to find the corresponding account
+//   - fault.ErrForbidden if the token is not granted the scope
+func GetAccountByPersonalAccessToken(ctx context.Context, token string, scope string) (*Account, error) {
+	if !auth.IsPersonalAccessToken(token) {
+		return nil, fault.ErrUnauthenticated
+	}
+
+	pat := new(entity.PersonalAccessToken)
+	if err := orm.GetBunInstance().
+		NewSelect().
+		Model(pat).
+		Relation("User", func(sq *bun.SelectQuery) *bun.SelectQuery {
+			return sq.Where("? = ?", bun.Ident("user.is_active"), true).
+				Where("? = ?", bun.Ident("user.is_banned"), false).
+				Where("? IS NULL", bun.Ident("user.removed_at"))
+		}).
+		Relation("User.Domain", func(sq *bun.SelectQuery) *bun.SelectQuery {
+			return sq.Where("? IS NULL", bun.Ident("user__domain.removed_at"))
+		}).
+		Where("? = ?", bun.Ident("personal_access_token.token_hash"), util.HashToken(token)).
+		Where("? IS NULL", bun.Ident("personal_access_token.removed_at")).
+		Where(
+			"(? IS NULL OR ? > ?)",
+			bun.Ident("personal_access_token.expires_at"),
+			bun.Ident("personal_access_token.expires_at"),
+			time.Now().In(time.UTC),
+		).
+		Limit(1).
+		Scan(ctx); fault.IsNonResourceNotFoundError(err) {
+		return nil, err
+	} else if fault.IsResourceNotFoundError(err) {
+		return nil, fault.ErrUnauthenticated
+	}
+
+	user := pat.User
+	if user == nil {
+		return nil, fault.ErrUnauthenticated
+	}
+
+	if !hasAccessTokenScope(pat.Scopes, scope) {
+		return nil, fault.ErrForbidden
+	}
+
+	if _, err := orm.GetBunInstance().
+		NewUpdate().
+		Model(pat).
+		Set("? = ?", bun.Ident("last_used_at"), time.Now().In(time.UTC)).
+		WherePK().
+		Exec(ctx); err != nil {
+		return nil, err
+	}
+
+	return &Account{
+		ctx:  ctx,
+		user: user,
+	}, nil
+}
@@ -0,0 +1,118 @@
+/*
+ * Copyright 2021 Meraj Sahebdar
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package facade
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/volatiletech/null/v8"
+	"bitban.io/server/internal/pkg/fault"
+	"syreclabs.com/go/faker"
+)
+
+func TestHasAccessTokenScope(t *testing.T) {
+	tests := []struct {
+		scopes []string
+		scope  string
+		want   bool
+	}{
+		{[]string{ScopeRepoRead}, ScopeRepoRead, true},
+		{[]string{ScopeRepoRead}, ScopeRepoWrite, false},
+		{[]string{ScopeRepoWrite}, ScopeRepoRead, true},
+		{[]string{ScopeRepoWrite}, ScopeApi, false},
+		{[]string{ScopeRepoRead, ScopeApi}, ScopeApi, true},
+		{[]string{}, ScopeRepoRead, false},
+	}
+
+	for _, tt := range tests {
+		if got := hasAccessTokenScope(tt.scopes, tt.scope); got != tt.want {
+			t.Errorf("expected %v for %s within %v, got: %v", tt.want, tt.scope, tt.scopes, got)
+		}
+	}
+}
+
+func TestPersonalAccessToken(t *testing.T) {
+	t.Run("personal-access-token", func(t *testing.T) {
+		ctx := context.Background()
+
+		account := getTestAccount(t, ctx)
+
+		if _, _, err := account.CreatePersonalAccessToken(faker.Lorem().Word(), []string{"admin"}, null.Time{}); err != ErrInvalidAccessTokenScope {
+			t.Errorf("expected error: %v, got: %v", ErrInvalidAccessTokenScope, err)
+		}
+
+		if _, _, err := account.CreatePersonalAccessToken(
+			faker.Lorem().Word(),
+			[]string{ScopeApi},
+			null.TimeFrom(time.Now().Add(-time.Hour)),
+		); err != ErrAccessTokenExpired {
+			t.Errorf("expected error: %v, got: %v", ErrAccessTokenExpired, err)
+		}
+
+		pat, token, err := account.CreatePersonalAccessToken(
+			faker.Lorem().Word(),
+			[]string{ScopeRepoRead},
+			null.TimeFrom(time.Now().Add(time.Hour)),
+		)
+		if err != nil {
+			t.Fatalf("failed to create the token, got error: %s", err.Error())
+		}
+
+		if pat.TokenHash == token {
+			t.Errorf("expected the token to be stored hashed")
+		}
+
+		if found, err := GetAccountByPersonalAccessToken(ctx, token, ScopeRepoRead); err != nil {
+			t.Errorf("failed to authenticate by the token, got error: %s", err.Error())
+		} else if found.GetUser().DomainID != account.GetUser().DomainID {
+			t.Errorf("expected user %d, got: %d", account.GetUser().DomainID, found.GetUser().DomainID)
+		}
+
+		if _, err := GetAccountByPersonalAccessToken(ctx, token, ScopeRepoWrite); err != fault.ErrForbidden {
+			t.Errorf("expected error: %v, got: %v", fault.ErrForbidden, err)
+		}
+
+		if tokens, err := account.GetPersonalAccessTokens(); err != nil {
+			t.Errorf("failed to get the tokens, got error: %s", err.Error())
+		} else {
+			used := false
+			for _, listed := range tokens {
+				if listed.ID == pat.ID {
+					used = listed.LastUsedAt.Valid
+				}
+			}
+
+			if !used {
+				t.Errorf("expected the use of the token to be recorded")
+			}
+		}
+
+		if _, err := account.RevokePersonalAccessToken(pat.ID); err != nil {
+			t.Errorf("failed to revoke the token, got error: %s", err.Error())
+		}
+
+		if _, err := GetAccountByPersonalAccessToken(ctx, token, ScopeRepoRead); err != fault.ErrUnauthenticated {
+			t.Errorf("expected error: %v, got: %v", fault.ErrUnauthenticated, err)
+		}
+
+		if _, err := account.RevokePersonalAccessToken(pat.ID); err != fault.ErrResourceNotFound {
+			t.Errorf("expected error: %v, got: %v", fault.ErrResourceNotFound, err)
+		}
+	})
+}
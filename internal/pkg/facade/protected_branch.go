@@ -0,0 +1,358 @@
+/*
+ * Copyright 2021 Meraj Sahebdar
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package facade
+
+import (
+	"context"
+	"errors"
+	goexec "os/exec"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/protocol/packp"
+	"github.com/uptrace/bun"
+	"github.com/volatiletech/null/v8"
+	"bitban.io/server/internal/cfg"
+	"bitban.io/server/internal/pkg/exec"
+	"bitban.io/server/internal/pkg/fault"
+	"bitban.io/server/internal/pkg/orm"
+	"bitban.io/server/internal/pkg/orm/entity"
+)
+
+var (
+	// ErrInvalidBranchPattern
+	ErrInvalidBranchPattern = errors.New("the provided pattern is not a valid branch glob pattern")
+
+	// ErrProtectedBranchExists
+	ErrProtectedBranchExists = errors.New("a protection rule with the same pattern already exists")
+
+	// ErrDeletionBlocked
+	ErrDeletionBlocked = errors.New("protected branch: deletion is not allowed")
+
+	// ErrForcePushBlocked
+	ErrForcePushBlocked = errors.New("protected branch: force-push is not allowed")
+
+	// ErrPushRestricted
+	ErrPushRestricted = errors.New("protected branch: you are not allowed to push")
+)
+
+// ProtectedBranchConfig
+type ProtectedBranchConfig struct {
+	Pattern        string
+	BlockForcePush bool
+	BlockDeletion  bool
+	RestrictPush   bool
+	PusherIDs      []int64
+}
+
+// MatchBranchPattern Checks whether the branch name matches the glob pattern
+// of a protection rule, where `*` does not match a `/`.
+func MatchBranchPattern(pattern string, branch string) bool {
+	matched, err := path.Match(pattern, branch)
+	return err == nil && matched
+}
+
+// isValidBranchPattern
+func isValidBranchPattern(pattern string) bool {
+	if pattern == "" || strings.HasPrefix(pattern, "/") {
+		return false
+	}
+
+	_, err := path.Match(pattern, "")
+	return err == nil
+}
+
+// GetProtectedBranches Returns the branch protection rules of the repository.
+func (f *Repo) GetProtectedBranches() ([]*entity.ProtectedBranch, error) {
+	var protectedBranches []*entity.ProtectedBranch
+	if err := orm.GetBunInstance().
+		NewSelect().
+		Model(&protectedBranches).
+		Where("? = ?", bun.Ident("protected_branch.repository_id"), f.GetID()).
+		Where("? IS NULL", bun.Ident("protected_branch.removed_at")).
+		Order("protected_branch.id").
+		Scan(f.ctx); err != nil {
+		return nil, err
+	}
+
+	return protectedBranches, nil
+}
+
+// getProtectedBranch
+//
+// Errors:
+//   - fault.ErrResourceNotFound if the repository does not have such a rule
+func (f *Repo) getProtectedBranch(id int64) (*entity.ProtectedBranch, error) {
+	protectedBranch := new(entity.ProtectedBranch)
+	if err := orm.GetBunInstance().
+		NewSelect().
+		Model(protectedBranch).
+		Where("? = ?", bun.Ident("protected_branch.id"), id).
+		Where("? = ?", bun.Ident("protected_branch.repository_id"), f.GetID()).
+		Where("? IS NULL", bun.Ident("protected_branch.removed_at")).
+		Limit(1).
+		Scan(f.ctx); err != nil {
+		return nil, fault.ErrResourceNotFound
+	}
+
+	return protectedBranch, nil
+}
+
+// AddProtectedBranch
+//
+// Errors:
+//   - facade.ErrInvalidBranchPattern if the pattern is malformed
+//   - facade.ErrProtectedBranchExists if the repository already has a rule with the same pattern
+func (f *Repo) AddProtectedBranch(config *ProtectedBranchConfig) (*entity.ProtectedBranch, error) {
+	if !isValidBranchPattern(config.Pattern) {
+		return nil, ErrInvalidBranchPattern
+	}
+
+	protectedBranch := &entity.ProtectedBranch{
+		Pattern:        config.Pattern,
+		BlockForcePush: config.BlockForcePush,
+		BlockDeletion:  config.BlockDeletion,
+		RestrictPush:   config.RestrictPush,
+		PusherIDs:      config.PusherIDs,
+		RepositoryID:   null.Int64From(f.GetID()),
+	}
+
+	if protectedBranch.PusherIDs == nil {
+		protectedBranch.PusherIDs = []int64{}
+	}
+
+	if _, err := orm.GetBunInstance().
+		NewInsert().
+		Model(protectedBranch).
+		Column("pattern", "block_force_push", "block_deletion", "restrict_push", "pusher_ids", "repository_id").
+		Returning("id", "created_at", "updated_at").
+		Exec(f.ctx); fault.IsPqUniqueViolationError(err) {
+		return nil, ErrProtectedBranchExists
+	} else if err != nil {
+		return nil, err
+	}
+
+	return protectedBranch, nil
+}
+
+// UpdateProtectedBranch
+//
+// Errors:
+//   - facade.ErrInvalidBranchPattern if the pattern is malformed
+//   - facade.ErrProtectedBranchExists if the repository already has another rule with the same pattern
+// ErrorsRef:
+//   - facade.Repo.getProtectedBranch
+func (f *Repo) UpdateProtectedBranch(id int64, config *ProtectedBranchConfig) (*entity.ProtectedBranch, error) {
+	if !isValidBranchPattern(config.Pattern) {
+		return nil, ErrInvalidBranchPattern
+	}
+
+	protectedBranch, err := f.getProtectedBranch(id)
+	if err != nil {
+		return nil, err
+	}
+
+	protectedBranch.UpdatedAt = time.Now().In(time.UTC)
+	protectedBranch.Pattern = config.Pattern
+	protectedBranch.BlockForcePush = config.BlockForcePush
+	protectedBranch.BlockDeletion = config.BlockDeletion
+	protectedBranch.RestrictPush = config.RestrictPush
+	protectedBranch.PusherIDs = config.PusherIDs
+
+	if protectedBranch.PusherIDs == nil {
+		protectedBranch.PusherIDs = []int64{}
+	}
+
+	if _, err := orm.GetBunInstance().
+		NewUpdate().
+		Model(protectedBranch).
+		Column("updated_at", "pattern", "block_force_push", "block_deletion", "restrict_push", "pusher_ids").
+		WherePK().
+		Exec(f.ctx); fault.IsPqUniqueViolationError(err) {
+		return nil, ErrProtectedBranchExists
+	} else if err != nil {
+		return nil, err
+	}
+
+	return protectedBranch, nil
+}
+
+// RemoveProtectedBranch
+//
+// ErrorsRef:
+//   - facade.Repo.getProtectedBranch
+func (f *Repo) RemoveProtectedBranch(id int64) (*entity.ProtectedBranch, error) {
+	protectedBranch, err := f.getProtectedBranch(id)
+	if err != nil {
+		return nil, err
+	}
+
+	protectedBranch.RemovedAt = null.TimeFrom(time.Now().In(time.UTC))
+	if _, err := orm.GetBunInstance().
+		NewUpdate().
+		Model(protectedBranch).
+		Column("removed_at").
+		WherePK().
+		Exec(f.ctx); err != nil {
+		return nil, err
+	}
+
+	return protectedBranch, nil
+}
+
+// GetRepoByProtectedBranchId Returns the repository of a branch protection rule.
+//
+// Errors:
+//   - fault.ErrResourceNotFound if there is no such rule
+// ErrorsRef:
+//   - facade.GetRepoById
+func GetRepoByProtectedBranchId(ctx context.Context, id int64) (*Repo, error) {
+	protectedBranch := new(entity.ProtectedBranch)
+	if err := orm.GetBunInstance().
+		NewSelect().
+		Model(protectedBranch).
+		Where("? = ?", bun.Ident("protected_branch.id"), id).
+		Where("? IS NULL", bun.Ident("protected_branch.removed_at")).
+		Limit(1).
+		Scan(ctx); err != nil {
+		return nil, fault.ErrResourceNotFound
+	}
+
+	return GetRepoById(ctx, protectedBranch.RepositoryID.Int64)
+}
+
+// isAncestor Checks whether the first commit is reachable from the second one.
+func (f *Repo) isAncestor(ancestor plumbing.Hash, descendant plumbing.Hash) (bool, error) {
+	if cfg.IsGoBackend() {
+		ancestorCommit, err := f.repositoryInstance.CommitObject(ancestor)
+		if err != nil {
+			return false, err
+		}
+
+		descendantCommit, err := f.repositoryInstance.CommitObject(descendant)
+		if err != nil {
+			return false, err
+		}
+
+		return ancestorCommit.IsAncestor(descendantCommit)
+	} else {
+		var exitErr *goexec.ExitError
+
+		if _, err := exec.Output(
+			f.path,
+			"git",
+			"merge-base",
+			"--is-ancestor",
+			ancestor.String(),
+			descendant.String(),
+		); err == nil {
+			return true, nil
+		} else if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 {
+			return false, nil
+		} else {
+			return false, err
+		}
+	}
+}
+
+// checkRefUpdate Checks a single reference update against the protection rules.
+//
+// Errors:
+//   - facade.ErrDeletionBlocked if a rule blocks deleting the branch
+//   - facade.ErrForcePushBlocked if a rule blocks rewriting the history of the branch
+//   - facade.ErrPushRestricted if a rule restricts pushing to other users
+func (f *Repo) checkRefUpdate(rules []*entity.ProtectedBranch, pusherID int64, cmd *packp.Command) error {
+	if !strings.HasPrefix(cmd.Name.String(), BranchRefPrefix) {
+		return nil
+	}
+
+	branch := strings.TrimPrefix(cmd.Name.String(), BranchRefPrefix)
+
+	for _, rule := range rules {
+		if !MatchBranchPattern(rule.Pattern, branch) {
+			continue
+		}
+
+		if rule.RestrictPush && !containsID(rule.PusherIDs, pusherID) {
+			return ErrPushRestricted
+		}
+
+		switch cmd.Action() {
+		case packp.Delete:
+			if rule.BlockDeletion {
+				return ErrDeletionBlocked
+			}
+		case packp.Update:
+			if rule.BlockForcePush {
+				if ok, err := f.isAncestor(cmd.Old, cmd.New); err != nil {
+					return err
+				} else if !ok {
+					return ErrForcePushBlocked
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// CheckRefUpdates Returns the reason of rejection for every reference update
+// of a push which violates the branch protection rules of the repository. The
+// pushed objects must be already available in the repository.
+func (f *Repo) CheckRefUpdates(pusherID int64, commands []*packp.Command) (map[plumbing.ReferenceName]error, error) {
+	rules, err := f.GetProtectedBranches()
+	if err != nil {
+		return nil, err
+	}
+
+	rejections := make(map[plumbing.ReferenceName]error)
+	for _, cmd := range commands {
+		if err := f.checkRefUpdate(rules, pusherID, cmd); err != nil {
+			rejections[cmd.Name] = err
+		}
+	}
+
+	return rejections, nil
+}
+
+// checkApiRefUpdate Checks a reference update made through the api against
+// the protection rules, the same way as the updates of a push.
+//
+// ErrorsRef:
+//   - facade.Repo.checkRefUpdate
+func (f *Repo) checkApiRefUpdate(pusherID int64, cmd *packp.Command) error {
+	if rejections, err := f.CheckRefUpdates(pusherID, []*packp.Command{cmd}); err != nil {
+		return err
+	} else if err, ok := rejections[cmd.Name]; ok {
+		return err
+	}
+
+	return nil
+}
+
+// containsID
+func containsID(ids []int64, id int64) bool {
+	for _, i := range ids {
+		if i == id {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,112 @@
+/*
+ * Copyright 2021 Meraj Sahebdar
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package facade
+
+import (
+	"context"
+	"testing"
+)
+
+func TestMatchBranchPattern(t *testing.T) {
+	for _, c := range []struct {
+		pattern  string
+		branch   string
+		expected bool
+	}{
+		{"main", "main", true},
+		{"main", "main2", false},
+		{"release/*", "release/1.0", true},
+		{"release/*", "release/1.0/hotfix", false},
+		{"release/*/*", "release/1.0/hotfix", true},
+		{"v[0-9]*", "v1", true},
+		{"v[0-9]*", "vx", false},
+		{"*", "feature/login", false},
+		{"[", "[", false},
+	} {
+		if got := MatchBranchPattern(c.pattern, c.branch); got != c.expected {
+			t.Errorf("expected MatchBranchPattern(%q, %q) to be %t, got: %t", c.pattern, c.branch, c.expected, got)
+		}
+	}
+}
+
+func TestIsValidBranchPattern(t *testing.T) {
+	for pattern, expected := range map[string]bool{
+		"main":      true,
+		"release/*": true,
+		"":          false,
+		"/main":     false,
+		"[":         false,
+	} {
+		if got := isValidBranchPattern(pattern); got != expected {
+			t.Errorf("expected isValidBranchPattern(%q) to be %t, got: %t", pattern, expected, got)
+		}
+	}
+}
+
+func TestApiRefUpdates(t *testing.T) {
+	ctx := context.Background()
+	repo := createTestRepo(t, ctx)
+
+	commitTestFiles(t, repo, "main", "initial", map[string]string{"README.md": "hello\n"})
+
+	const allowed, other = int64(1), int64(2)
+
+	if _, err := repo.AddProtectedBranch(&ProtectedBranchConfig{
+		Pattern:       "release/*",
+		BlockDeletion: true,
+		RestrictPush:  true,
+		PusherIDs:     []int64{allowed},
+	}); err != nil {
+		t.Fatalf("failed to protect the branches: %s", err.Error())
+	}
+
+	t.Run("create restricted", func(t *testing.T) {
+		if _, err := repo.CreateBranch("release/1.0", "main", other); err != ErrPushRestricted {
+			t.Errorf("expected error: %v, got: %v", ErrPushRestricted, err)
+		}
+
+		if _, err := repo.getRef("refs/heads/release/1.0"); err != ErrRefNotFound {
+			t.Errorf("expected the branch not to be created, got: %v", err)
+		}
+	})
+
+	t.Run("create allowed", func(t *testing.T) {
+		if _, err := repo.CreateBranch("release/1.0", "main", allowed); err != nil {
+			t.Errorf("got an unexpected error: %s", err.Error())
+		}
+	})
+
+	t.Run("delete blocked", func(t *testing.T) {
+		if _, err := repo.DeleteBranch("release/1.0", allowed); err != ErrDeletionBlocked {
+			t.Errorf("expected error: %v, got: %v", ErrDeletionBlocked, err)
+		}
+
+		if _, err := repo.getRef("refs/heads/release/1.0"); err != nil {
+			t.Errorf("expected the branch to be kept, got: %v", err)
+		}
+	})
+
+	t.Run("unprotected", func(t *testing.T) {
+		if _, err := repo.CreateBranch("feature", "main", other); err != nil {
+			t.Errorf("got an unexpected error: %s", err.Error())
+		}
+
+		if _, err := repo.DeleteBranch("feature", other); err != nil {
+			t.Errorf("got an unexpected error: %s", err.Error())
+		}
+	})
+}
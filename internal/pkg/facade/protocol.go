@@ -0,0 +1,491 @@
+/*
+ * Copyright 2021 Meraj Sahebdar
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package facade
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/format/packfile"
+	"github.com/go-git/go-git/v5/plumbing/format/pktline"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/protocol/packp/capability"
+	"github.com/go-git/go-git/v5/plumbing/protocol/packp/sideband"
+	"github.com/go-git/go-git/v5/plumbing/revlist"
+	"github.com/go-git/go-git/v5/plumbing/storer"
+)
+
+// Protocol v2 Commands
+const (
+	CommandLsRefs = "ls-refs"
+	CommandFetch  = "fetch"
+)
+
+// Protocol v2 Packets
+const (
+	pktFlush = iota
+	pktDelim
+	pktResponseEnd
+	pktData
+)
+
+// delimPkt
+var delimPkt = []byte{'0', '0', '0', '1'}
+
+// IsProtocolV2 Checks whether the value of `Git-Protocol` header or
+// `GIT_PROTOCOL` environment asks for the protocol version 2.
+func IsProtocolV2(protocol string) bool {
+	for _, param := range strings.Split(protocol, ":") {
+		if strings.TrimSpace(param) == "version=2" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// pktReader Reads pkt-lines including the special packets introduced by v2.
+type pktReader struct {
+	r io.Reader
+}
+
+// next
+func (p *pktReader) next() (int, []byte, error) {
+	var hexLen [4]byte
+	if _, err := io.ReadFull(p.r, hexLen[:]); err != nil {
+		return 0, nil, err
+	}
+
+	n, err := strconv.ParseUint(string(hexLen[:]), 16, 16)
+	if err != nil {
+		return 0, nil, pktline.ErrInvalidPktLen
+	}
+
+	switch n {
+	case 0:
+		return pktFlush, nil, nil
+	case 1:
+		return pktDelim, nil, nil
+	case 2:
+		return pktResponseEnd, nil, nil
+	case 3:
+		return 0, nil, pktline.ErrInvalidPktLen
+	}
+
+	payload := make([]byte, n-4)
+	if _, err := io.ReadFull(p.r, payload); err != nil {
+		return 0, nil, err
+	}
+
+	return pktData, payload, nil
+}
+
+// commandRequest
+type commandRequest struct {
+	command      string
+	capabilities []string
+	args         []string
+}
+
+// readCommandRequest
+//
+// Errors:
+//   - io.EOF if the client has no more commands to request
+func readCommandRequest(p *pktReader) (*commandRequest, error) {
+	req := &commandRequest{}
+
+	inArgs := false
+	for {
+		kind, payload, err := p.next()
+		if err != nil {
+			if err == io.EOF && req.command == "" && len(req.capabilities) == 0 {
+				return nil, io.EOF
+			}
+
+			return nil, err
+		}
+
+		switch kind {
+		case pktFlush, pktResponseEnd:
+			if req.command == "" {
+				return nil, io.EOF
+			}
+
+			return req, nil
+		case pktDelim:
+			inArgs = true
+		case pktData:
+			line := strings.TrimSuffix(string(payload), "\n")
+
+			if inArgs {
+				req.args = append(req.args, line)
+			} else if strings.HasPrefix(line, "command=") {
+				req.command = strings.TrimPrefix(line, "command=")
+			} else {
+				req.capabilities = append(req.capabilities, line)
+			}
+		}
+	}
+}
+
+// advertiseCapabilitiesV2
+func advertiseCapabilitiesV2(w io.Writer) error {
+	enc := pktline.NewEncoder(w)
+
+	if err := enc.EncodeString(
+		"version 2\n",
+		fmt.Sprintf("agent=%s\n", capability.DefaultAgent),
+		fmt.Sprintf("%s\n", CommandLsRefs),
+		fmt.Sprintf("%s\n", CommandFetch),
+		"server-option\n",
+		"object-format=sha1\n",
+	); err != nil {
+		return err
+	}
+
+	return enc.Flush()
+}
+
+// serveUploadPackV2 Serves commands of the protocol version 2 until the
+// client stops requesting.
+func (f *Repo) serveUploadPackV2(r io.Reader, w io.Writer, advertise bool) error {
+	if advertise {
+		if err := advertiseCapabilitiesV2(w); err != nil {
+			return err
+		}
+	}
+
+	p := &pktReader{r: r}
+	for {
+		req, err := readCommandRequest(p)
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		switch req.command {
+		case CommandLsRefs:
+			err = f.lsRefs(w, req.args)
+		case CommandFetch:
+			err = f.fetch(w, req.args)
+		default:
+			err = fmt.Errorf("unknown command: %s", req.command)
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// lsRefs Implements `ls-refs` command of the protocol version 2.
+func (f *Repo) lsRefs(w io.Writer, args []string) error {
+	var symrefs, peel bool
+	var prefixes []string
+
+	for _, arg := range args {
+		switch {
+		case arg == "symrefs":
+			symrefs = true
+		case arg == "peel":
+			peel = true
+		case strings.HasPrefix(arg, "ref-prefix "):
+			prefixes = append(prefixes, strings.TrimPrefix(arg, "ref-prefix "))
+		}
+	}
+
+	iter, err := f.storage.IterReferences()
+	if err != nil {
+		return err
+	}
+
+	var refs []*plumbing.Reference
+	if err := iter.ForEach(func(ref *plumbing.Reference) error {
+		if len(prefixes) > 0 {
+			matched := false
+			for _, prefix := range prefixes {
+				if strings.HasPrefix(ref.Name().String(), prefix) {
+					matched = true
+					break
+				}
+			}
+
+			if !matched {
+				return nil
+			}
+		}
+
+		refs = append(refs, ref)
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	sort.Slice(refs, func(i, j int) bool {
+		if refs[i].Name() == plumbing.HEAD {
+			return true
+		} else if refs[j].Name() == plumbing.HEAD {
+			return false
+		}
+
+		return refs[i].Name() < refs[j].Name()
+	})
+
+	enc := pktline.NewEncoder(w)
+	for _, ref := range refs {
+		resolved, err := storer.ResolveReference(f.storage, ref.Name())
+		if err != nil {
+			// Skip unborn references, like HEAD of an empty repository.
+			continue
+		}
+
+		line := fmt.Sprintf("%s %s", resolved.Hash(), ref.Name())
+
+		if symrefs && ref.Type() == plumbing.SymbolicReference {
+			line += fmt.Sprintf(" symref-target:%s", ref.Target())
+		}
+
+		if peel {
+			if peeled, ok := f.peelTag(resolved.Hash()); ok {
+				line += fmt.Sprintf(" peeled:%s", peeled)
+			}
+		}
+
+		if err := enc.Encodef("%s\n", line); err != nil {
+			return err
+		}
+	}
+
+	return enc.Flush()
+}
+
+// peelTag Returns the object that an annotated tag finally points to.
+func (f *Repo) peelTag(hash plumbing.Hash) (plumbing.Hash, bool) {
+	tag, err := object.GetTag(f.storage, hash)
+	if err != nil {
+		return plumbing.ZeroHash, false
+	}
+
+	for tag.TargetType == plumbing.TagObject {
+		if tag, err = object.GetTag(f.storage, tag.Target); err != nil {
+			return plumbing.ZeroHash, false
+		}
+	}
+
+	return tag.Target, true
+}
+
+// writeErrPkt Writes the error as an `ERR` pkt-line, which the clients show
+// before aborting, and returns it.
+func writeErrPkt(w io.Writer, err error) error {
+	if encErr := pktline.NewEncoder(w).Encodef("ERR %s\n", err.Error()); encErr != nil {
+		return encErr
+	}
+
+	return err
+}
+
+// advertisedHashes Returns the objects the references point to along with
+// the targets of the annotated tags, which are the ones the clients may want.
+func (f *Repo) advertisedHashes() (map[plumbing.Hash]bool, error) {
+	iter, err := f.storage.IterReferences()
+	if err != nil {
+		return nil, err
+	}
+
+	hashes := make(map[plumbing.Hash]bool)
+	if err := iter.ForEach(func(ref *plumbing.Reference) error {
+		resolved, err := storer.ResolveReference(f.storage, ref.Name())
+		if err != nil {
+			return nil
+		}
+
+		hashes[resolved.Hash()] = true
+		if peeled, ok := f.peelTag(resolved.Hash()); ok {
+			hashes[peeled] = true
+		}
+
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return hashes, nil
+}
+
+// includeTags Adds the annotated tags pointing to the objects of the pack,
+// along with the tags in between, to the pack.
+func (f *Repo) includeTags(objs []plumbing.Hash) ([]plumbing.Hash, error) {
+	packed := make(map[plumbing.Hash]bool, len(objs))
+	for _, hash := range objs {
+		packed[hash] = true
+	}
+
+	iter, err := f.storage.IterReferences()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := iter.ForEach(func(ref *plumbing.Reference) error {
+		if !ref.Name().IsTag() || ref.Type() != plumbing.HashReference {
+			return nil
+		}
+
+		var chain []plumbing.Hash
+		for hash := ref.Hash(); !packed[hash]; {
+			tag, err := object.GetTag(f.storage, hash)
+			if err != nil {
+				// A lightweight tag, or one pointing to an object out of the pack.
+				return nil
+			}
+
+			chain = append(chain, hash)
+			hash = tag.Target
+		}
+
+		for _, hash := range chain {
+			packed[hash] = true
+			objs = append(objs, hash)
+		}
+
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return objs, nil
+}
+
+// fetch Implements `fetch` command of the protocol version 2. The packs are
+// never thin, which the `thin-pack` argument only allows, and no progress is
+// sent, as `no-progress` asks.
+func (f *Repo) fetch(w io.Writer, args []string) error {
+	var wants, haves []plumbing.Hash
+	var done, ofsDelta, includeTag bool
+
+	advertised, err := f.advertisedHashes()
+	if err != nil {
+		return err
+	}
+
+	for _, arg := range args {
+		switch {
+		case strings.HasPrefix(arg, "want "):
+			want := strings.TrimPrefix(arg, "want ")
+			if !plumbing.IsHash(want) {
+				return writeErrPkt(w, fmt.Errorf("upload-pack: protocol error, expected to get oid, not '%s'", arg))
+			} else if !advertised[plumbing.NewHash(want)] {
+				return writeErrPkt(w, fmt.Errorf("upload-pack: not our ref %s", want))
+			}
+
+			wants = append(wants, plumbing.NewHash(want))
+		case strings.HasPrefix(arg, "have "):
+			have := strings.TrimPrefix(arg, "have ")
+			if !plumbing.IsHash(have) {
+				return writeErrPkt(w, fmt.Errorf("upload-pack: protocol error, expected to get oid, not '%s'", arg))
+			}
+
+			haves = append(haves, plumbing.NewHash(have))
+		case arg == "done":
+			done = true
+		case arg == "ofs-delta":
+			ofsDelta = true
+		case arg == "include-tag":
+			includeTag = true
+		}
+	}
+
+	var common []plumbing.Hash
+	for _, have := range haves {
+		if err := f.storage.HasEncodedObject(have); err == nil {
+			common = append(common, have)
+		}
+	}
+
+	enc := pktline.NewEncoder(w)
+
+	//
+	// Negotiation
+
+	if !done {
+		if err := enc.EncodeString("acknowledgments\n"); err != nil {
+			return err
+		}
+
+		if len(common) == 0 {
+			if err := enc.EncodeString("NAK\n"); err != nil {
+				return err
+			}
+
+			// Wait for the client to send more haves or to be done, unless it
+			// has nothing to negotiate with, in which case the whole history
+			// of the wants is sent right away.
+			if len(haves) > 0 {
+				return enc.Flush()
+			}
+		}
+
+		for _, hash := range common {
+			if err := enc.Encodef("ACK %s\n", hash); err != nil {
+				return err
+			}
+		}
+
+		if err := enc.EncodeString("ready\n"); err != nil {
+			return err
+		}
+
+		if _, err := w.Write(delimPkt); err != nil {
+			return err
+		}
+	}
+
+	//
+	// Packfile
+
+	commonObjs, err := revlist.Objects(f.storage, common, nil)
+	if err != nil {
+		return err
+	}
+
+	objs, err := revlist.Objects(f.storage, wants, commonObjs)
+	if err != nil {
+		return err
+	}
+
+	if includeTag {
+		if objs, err = f.includeTags(objs); err != nil {
+			return err
+		}
+	}
+
+	if err := enc.EncodeString("packfile\n"); err != nil {
+		return err
+	}
+
+	mux := sideband.NewMuxer(sideband.Sideband64k, w)
+	if _, err := packfile.NewEncoder(mux, f.storage, !ofsDelta).Encode(objs, 10); err != nil {
+		return err
+	}
+
+	return enc.Flush()
+}
@@ -0,0 +1,345 @@
+/*
+ * Copyright 2021 Meraj Sahebdar
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package facade
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/format/pktline"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// encodeTestCommand Encodes a command request of the protocol version 2.
+func encodeTestCommand(t *testing.T, command string, args ...string) *bytes.Buffer {
+	t.Helper()
+
+	buf := &bytes.Buffer{}
+	enc := pktline.NewEncoder(buf)
+
+	if err := enc.Encodef("command=%s\n", command); err != nil {
+		t.Fatal(err)
+	}
+	if err := enc.EncodeString("object-format=sha1\n"); err != nil {
+		t.Fatal(err)
+	}
+
+	buf.Write(delimPkt)
+
+	for _, arg := range args {
+		if err := enc.Encodef("%s\n", arg); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := enc.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	return buf
+}
+
+// decodeTestResponse Decodes the pkt-lines of a response, writing the special
+// packets as their raw form and collecting the packfile out of the sideband.
+func decodeTestResponse(t *testing.T, r io.Reader) ([]string, []byte) {
+	t.Helper()
+
+	var lines []string
+	var pack []byte
+	inPack := false
+
+	p := &pktReader{r: r}
+	for {
+		kind, payload, err := p.next()
+		if err == io.EOF {
+			return lines, pack
+		} else if err != nil {
+			t.Fatalf("failed to read the response: %s", err.Error())
+		}
+
+		switch kind {
+		case pktFlush:
+			lines = append(lines, "0000")
+		case pktDelim:
+			lines = append(lines, "0001")
+		case pktResponseEnd:
+			lines = append(lines, "0002")
+		case pktData:
+			if inPack {
+				if payload[0] == 1 {
+					pack = append(pack, payload[1:]...)
+				}
+				continue
+			}
+
+			line := strings.TrimSuffix(string(payload), "\n")
+			lines = append(lines, line)
+			inPack = line == "packfile"
+		}
+	}
+}
+
+// packObjectsCount
+func packObjectsCount(t *testing.T, pack []byte) uint32 {
+	t.Helper()
+
+	if len(pack) < 12 || string(pack[:4]) != "PACK" {
+		t.Fatalf("expected a packfile, got: %q", pack)
+	}
+
+	return binary.BigEndian.Uint32(pack[8:12])
+}
+
+func TestIsProtocolV2(t *testing.T) {
+	for protocol, expected := range map[string]bool{
+		"version=2":           true,
+		"version=1:version=2": true,
+		" version=2 ":         true,
+		"":                    false,
+		"version=1":           false,
+		"version=20":          false,
+	} {
+		if got := IsProtocolV2(protocol); got != expected {
+			t.Errorf("expected IsProtocolV2(%q) to be %t, got: %t", protocol, expected, got)
+		}
+	}
+}
+
+func TestPktReader(t *testing.T) {
+	p := &pktReader{r: strings.NewReader("0009hello00010000000200")}
+
+	for _, expected := range []struct {
+		kind    int
+		payload string
+	}{
+		{pktData, "hello"},
+		{pktDelim, ""},
+		{pktFlush, ""},
+		{pktResponseEnd, ""},
+	} {
+		kind, payload, err := p.next()
+		if err != nil {
+			t.Fatalf("got an unexpected error: %s", err.Error())
+		} else if kind != expected.kind || string(payload) != expected.payload {
+			t.Errorf("expected packet %d %q, got: %d %q", expected.kind, expected.payload, kind, payload)
+		}
+	}
+
+	if _, _, err := p.next(); err != io.ErrUnexpectedEOF {
+		t.Errorf("expected unexpected EOF on a truncated length, got: %v", err)
+	}
+
+	for _, invalid := range []string{"0003", "zzzz"} {
+		if _, _, err := (&pktReader{r: strings.NewReader(invalid)}).next(); err != pktline.ErrInvalidPktLen {
+			t.Errorf("expected invalid length error for %q, got: %v", invalid, err)
+		}
+	}
+}
+
+func TestReadCommandRequest(t *testing.T) {
+	buf := encodeTestCommand(t, CommandLsRefs, "peel", "ref-prefix refs/heads/")
+	buf.WriteString("0000")
+
+	p := &pktReader{r: buf}
+
+	req, err := readCommandRequest(p)
+	if err != nil {
+		t.Fatalf("got an unexpected error: %s", err.Error())
+	}
+
+	if req.command != CommandLsRefs {
+		t.Errorf("expected command %s, got: %s", CommandLsRefs, req.command)
+	}
+
+	if len(req.capabilities) != 1 || req.capabilities[0] != "object-format=sha1" {
+		t.Errorf("expected the object format capability, got: %v", req.capabilities)
+	}
+
+	if len(req.args) != 2 || req.args[0] != "peel" || req.args[1] != "ref-prefix refs/heads/" {
+		t.Errorf("expected the arguments after the delimiter, got: %v", req.args)
+	}
+
+	if _, err := readCommandRequest(p); err != io.EOF {
+		t.Errorf("expected EOF on a lone flush, got: %v", err)
+	}
+
+	if _, err := readCommandRequest(p); err != io.EOF {
+		t.Errorf("expected EOF at the end of the stream, got: %v", err)
+	}
+}
+
+func TestAdvertiseCapabilitiesV2(t *testing.T) {
+	buf := &bytes.Buffer{}
+	if err := advertiseCapabilitiesV2(buf); err != nil {
+		t.Fatalf("got an unexpected error: %s", err.Error())
+	}
+
+	lines, _ := decodeTestResponse(t, buf)
+	if lines[0] != "version 2" || lines[len(lines)-1] != "0000" {
+		t.Errorf("expected a version 2 advertisement ended by flush, got: %v", lines)
+	}
+
+	for _, capability := range []string{CommandLsRefs, CommandFetch} {
+		found := false
+		for _, line := range lines {
+			found = found || line == capability
+		}
+
+		if !found {
+			t.Errorf("expected %s to be advertised, got: %v", capability, lines)
+		}
+	}
+}
+
+func TestUploadPackV2(t *testing.T) {
+	ctx := context.Background()
+	repo := createTestRepo(t, ctx)
+
+	first := commitTestFiles(t, repo, "main", "first", map[string]string{"README.md": "hello\n"})
+	second := commitTestFiles(t, repo, "main", "second", map[string]string{"docs/guide.md": "guide\n"})
+
+	signature := &object.Signature{Name: "Bitban", Email: "bitban@bitban.io"}
+	tag, err := repo.repositoryInstance.CreateTag("v1.0.0", first, &git.CreateTagOptions{
+		Tagger:  signature,
+		Message: "v1.0.0",
+	})
+	if err != nil {
+		t.Fatalf("failed to create the tag: %s", err.Error())
+	}
+
+	serve := func(t *testing.T, command string, args ...string) ([]string, []byte) {
+		t.Helper()
+
+		out := &bytes.Buffer{}
+		if err := repo.serveUploadPackV2(encodeTestCommand(t, command, args...), out, false); err != nil {
+			t.Fatalf("got an unexpected error: %s", err.Error())
+		}
+
+		return decodeTestResponse(t, out)
+	}
+
+	expectLines := func(t *testing.T, expected []string, got []string) {
+		t.Helper()
+
+		if strings.Join(got, "\n") != strings.Join(expected, "\n") {
+			t.Errorf("expected lines:\n%s\ngot:\n%s", strings.Join(expected, "\n"), strings.Join(got, "\n"))
+		}
+	}
+
+	t.Run("ls-refs", func(t *testing.T) {
+		lines, _ := serve(t, CommandLsRefs, "symrefs", "peel")
+
+		expectLines(t, []string{
+			second.String() + " HEAD symref-target:refs/heads/main",
+			second.String() + " refs/heads/main",
+			tag.Hash().String() + " refs/tags/v1.0.0 peeled:" + first.String(),
+			"0000",
+		}, lines)
+	})
+
+	t.Run("ls-refs with prefix", func(t *testing.T) {
+		lines, _ := serve(t, CommandLsRefs, "ref-prefix refs/tags/")
+
+		expectLines(t, []string{
+			tag.Hash().String() + " refs/tags/v1.0.0",
+			"0000",
+		}, lines)
+	})
+
+	t.Run("fetch done without haves", func(t *testing.T) {
+		lines, pack := serve(t, CommandFetch, "want "+second.String(), "done")
+
+		expectLines(t, []string{"packfile", "0000"}, lines)
+
+		// 2 commits, 3 trees and 2 blobs.
+		if count := packObjectsCount(t, pack); count != 7 {
+			t.Errorf("expected 7 objects, got: %d", count)
+		}
+	})
+
+	t.Run("fetch without haves", func(t *testing.T) {
+		lines, pack := serve(t, CommandFetch, "want "+second.String())
+
+		expectLines(t, []string{"acknowledgments", "NAK", "ready", "0001", "packfile", "0000"}, lines)
+
+		if count := packObjectsCount(t, pack); count != 7 {
+			t.Errorf("expected 7 objects, got: %d", count)
+		}
+	})
+
+	t.Run("fetch with unknown haves", func(t *testing.T) {
+		unknown := plumbing.NewHash("1111111111111111111111111111111111111111")
+		lines, pack := serve(t, CommandFetch, "want "+second.String(), "have "+unknown.String())
+
+		expectLines(t, []string{"acknowledgments", "NAK", "0000"}, lines)
+
+		if pack != nil {
+			t.Errorf("expected no packfile before the negotiation is done, got: %d bytes", len(pack))
+		}
+	})
+
+	t.Run("fetch with include-tag", func(t *testing.T) {
+		_, pack := serve(t, CommandFetch, "want "+second.String(), "include-tag", "ofs-delta", "done")
+
+		// The objects of the history along with the annotated tag.
+		if count := packObjectsCount(t, pack); count != 8 {
+			t.Errorf("expected 8 objects, got: %d", count)
+		}
+	})
+
+	t.Run("fetch invalid wants", func(t *testing.T) {
+		commit, err := repo.repositoryInstance.CommitObject(second)
+		if err != nil {
+			t.Fatalf("failed to get the commit: %s", err.Error())
+		}
+
+		for want, expected := range map[string]string{
+			"want " + commit.TreeHash.String(): "ERR upload-pack: not our ref " + commit.TreeHash.String(),
+			"want main":                        "ERR upload-pack: protocol error, expected to get oid, not 'want main'",
+			"have 1234":                        "ERR upload-pack: protocol error, expected to get oid, not 'have 1234'",
+		} {
+			out := &bytes.Buffer{}
+			if err := repo.serveUploadPackV2(encodeTestCommand(t, CommandFetch, want, "done"), out, false); err == nil {
+				t.Errorf("expected an error for %q", want)
+			}
+
+			lines, pack := decodeTestResponse(t, out)
+			expectLines(t, []string{expected}, lines)
+
+			if pack != nil {
+				t.Errorf("expected no packfile for %q, got: %d bytes", want, len(pack))
+			}
+		}
+	})
+
+	t.Run("fetch with common haves", func(t *testing.T) {
+		lines, pack := serve(t, CommandFetch, "want "+second.String(), "have "+first.String())
+
+		expectLines(t, []string{"acknowledgments", "ACK " + first.String(), "ready", "0001", "packfile", "0000"}, lines)
+
+		// The second commit, its root and docs trees and the guide blob.
+		if count := packObjectsCount(t, pack); count != 4 {
+			t.Errorf("expected 4 objects, got: %d", count)
+		}
+	})
+}
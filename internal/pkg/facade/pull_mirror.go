@@ -0,0 +1,482 @@
+/*
+ * Copyright 2021 Meraj Sahebdar
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package facade
+
+import (
+	"context"
+	"errors"
+	"net"
+	goexec "os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/protocol/packp"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	gitclient "github.com/go-git/go-git/v5/plumbing/transport/client"
+	githttp "github.com/go-git/go-git/v5/plumbing/transport/http"
+	gitssh "github.com/go-git/go-git/v5/plumbing/transport/ssh"
+	"github.com/uptrace/bun"
+	"github.com/volatiletech/null/v8"
+	"go.uber.org/zap"
+	gossh "golang.org/x/crypto/ssh"
+	"bitban.io/server/internal/cfg"
+	"bitban.io/server/internal/pkg/exec"
+	"bitban.io/server/internal/pkg/fault"
+	"bitban.io/server/internal/pkg/orm"
+	"bitban.io/server/internal/pkg/orm/entity"
+)
+
+var (
+	// ErrInvalidMirrorURL
+	ErrInvalidMirrorURL = errors.New("the mirror url must be an http, https or ssh git url")
+
+	// ErrMirrorReadOnly
+	ErrMirrorReadOnly = errors.New("the repository is a mirror and does not accept pushes")
+
+	// ErrMirrorAddressForbidden
+	ErrMirrorAddressForbidden = errors.New("the mirror url must not point to a loopback, private or link-local address")
+)
+
+// mirrorRefSpec Fetches every reference of the remote as is.
+const mirrorRefSpec = "+refs/*:refs/*"
+
+// mirrorProtocols The protocols the repositories may be mirrored over.
+var mirrorProtocols = map[string]bool{
+	"http":  true,
+	"https": true,
+	"ssh":   true,
+}
+
+// mirrorSshCommand Keeps the ssh agent, the keys and the configs of the server
+// user out of the connections of the git binary to the mirrors.
+const mirrorSshCommand = "ssh -F /dev/null -o IdentityAgent=none -o IdentitiesOnly=yes -o IdentityFile=/dev/null -o BatchMode=yes"
+
+func init() {
+	// The fetches and pushes of go-git neither reach the forbidden addresses
+	// nor follow redirects, and ignore the ssh configs of the server user.
+	client := githttp.NewClient(newOutboundHTTPClient(0))
+	gitclient.InstallProtocol("http", client)
+	gitclient.InstallProtocol("https", client)
+	gitssh.DefaultSSHConfig = nil
+}
+
+// checkMirrorAddress Resolves the host of the mirror url, rejecting the ones
+// pointing to the server itself or its private networks.
+//
+// Errors:
+//   - facade.ErrMirrorAddressForbidden if the host resolves to a forbidden address
+func checkMirrorAddress(ctx context.Context, ep *transport.Endpoint) error {
+	if ep.Protocol == "file" {
+		return nil
+	}
+
+	addrs, err := net.DefaultResolver.LookupIPAddr(ctx, ep.Host)
+	if err != nil {
+		return err
+	}
+
+	for _, addr := range addrs {
+		if isForbiddenIP(addr.IP) {
+			return ErrMirrorAddressForbidden
+		}
+	}
+
+	return nil
+}
+
+// mirrorAuthFrom Returns the auth of the mirror endpoint for go-git, which
+// does not offer any identity over ssh instead of falling back to the ssh
+// agent and the keys of the server user, and checks the address the host is
+// actually connected to before the host key.
+func mirrorAuthFrom(ep *transport.Endpoint) transport.AuthMethod {
+	if ep.Protocol != "ssh" {
+		return nil
+	}
+
+	return &gitssh.PublicKeysCallback{
+		User: ep.User,
+		Callback: func() ([]gossh.Signer, error) {
+			return nil, nil
+		},
+		HostKeyCallbackHelper: gitssh.HostKeyCallbackHelper{
+			HostKeyCallback: func(hostname string, remote net.Addr, key gossh.PublicKey) error {
+				if addr, ok := remote.(*net.TCPAddr); !ok || isForbiddenIP(addr.IP) {
+					return ErrAddressForbidden
+				}
+
+				knownHosts, err := gitssh.NewKnownHostsCallback()
+				if err != nil {
+					return err
+				}
+
+				return knownHosts(hostname, remote, key)
+			},
+		},
+	}
+}
+
+// ValidateMirrorURL Checks whether the repositories may be mirrored from the
+// url. Local paths are only allowed if `git.mirror.allowFile` is enabled.
+//
+// Errors:
+//   - facade.ErrInvalidMirrorURL if the url is not supported
+func ValidateMirrorURL(rawURL string) error {
+	if strings.HasPrefix(rawURL, "-") {
+		return ErrInvalidMirrorURL
+	}
+
+	if ep, err := transport.NewEndpoint(rawURL); err != nil {
+		return ErrInvalidMirrorURL
+	} else if ep.Protocol == "file" && cfg.Cog.Git.Mirror.AllowFile {
+		return nil
+	} else if !mirrorProtocols[ep.Protocol] || ep.Host == "" {
+		return ErrInvalidMirrorURL
+	}
+
+	return nil
+}
+
+// GetMirror Returns the pull mirror configuration of the repository, or nil
+// if it is not a mirror.
+func (f *Repo) GetMirror() (*entity.PullMirror, error) {
+	mirror := new(entity.PullMirror)
+	if err := orm.
+		GetBunInstance().
+		NewSelect().
+		Model(mirror).
+		Where("? = ?", bun.Ident("pull_mirror.repository_id"), f.GetID()).
+		Scan(f.ctx); err == fault.ErrResourceNotFound {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	return mirror, nil
+}
+
+// IsMirror
+func (f *Repo) IsMirror() (bool, error) {
+	if count, err := orm.
+		GetBunInstance().
+		NewSelect().
+		Model((*entity.PullMirror)(nil)).
+		Where("? = ?", bun.Ident("pull_mirror.repository_id"), f.GetID()).
+		Count(f.ctx); err != nil {
+		return false, err
+	} else {
+		return count > 0, nil
+	}
+}
+
+// checkWritable
+//
+// Errors:
+//   - facade.ErrMirrorReadOnly if the repository is a mirror
+func (f *Repo) checkWritable() error {
+	if isMirror, err := f.IsMirror(); err != nil {
+		return err
+	} else if isMirror {
+		return ErrMirrorReadOnly
+	}
+
+	return nil
+}
+
+// SetMirror Makes the repository a pull mirror of the url, which is synced by
+// the next run of the mirror job.
+//
+// Errors:
+//   - facade.ErrInvalidMirrorURL if the url is not supported
+func (f *Repo) SetMirror(rawURL string) (*entity.PullMirror, error) {
+	if err := ValidateMirrorURL(rawURL); err != nil {
+		return nil, err
+	}
+
+	mirror := &entity.PullMirror{
+		URL:          rawURL,
+		RepositoryID: null.Int64From(f.GetID()),
+	}
+	if _, err := orm.GetBunInstance().
+		NewInsert().
+		Model(mirror).
+		Column("url", "repository_id").
+		On("CONFLICT (repository_id) DO UPDATE").
+		Set("url = EXCLUDED.url").
+		Set("synced_at = NULL").
+		Set("sync_error = NULL").
+		Set("updated_at = NOW()").
+		Exec(f.ctx); err != nil {
+		return nil, err
+	}
+
+	return f.GetMirror()
+}
+
+// RemoveMirror Stops mirroring, making the repository writable again.
+//
+// Errors:
+//   - fault.ErrResourceNotFound if the repository is not a mirror
+func (f *Repo) RemoveMirror() (*entity.PullMirror, error) {
+	mirror, err := f.GetMirror()
+	if err != nil {
+		return nil, err
+	} else if mirror == nil {
+		return nil, fault.ErrResourceNotFound
+	}
+
+	if _, err := orm.GetBunInstance().
+		NewDelete().
+		Model(mirror).
+		WherePK().
+		Exec(f.ctx); err != nil {
+		return nil, err
+	}
+
+	return mirror, nil
+}
+
+// mirrorLocks Keeps the same mirror from being synced concurrently.
+var mirrorLocks sync.Map
+
+// fetchMirror Updates the references of the repository to match the remote,
+// removing the ones the remote no longer has.
+//
+// ErrorsRef:
+//   - facade.ValidateMirrorURL
+//   - facade.checkMirrorAddress
+func (f *Repo) fetchMirror(rawURL string) error {
+	// The url is checked again, as the config may have changed since it was set.
+	if err := ValidateMirrorURL(rawURL); err != nil {
+		return err
+	}
+
+	ep, err := transport.NewEndpoint(rawURL)
+	if err != nil {
+		return ErrInvalidMirrorURL
+	}
+
+	if err := checkMirrorAddress(f.ctx, ep); err != nil {
+		return err
+	}
+
+	if cfg.IsGoBackend() {
+		remote := git.NewRemote(f.storage, &config.RemoteConfig{
+			Name: "mirror",
+			URLs: []string{rawURL},
+		})
+
+		auth := mirrorAuthFrom(ep)
+
+		refs, err := remote.ListContext(f.ctx, &git.ListOptions{Auth: auth})
+		if err != nil && err != transport.ErrEmptyRemoteRepository {
+			return err
+		}
+
+		if len(refs) > 0 {
+			if err := remote.FetchContext(f.ctx, &git.FetchOptions{
+				RemoteName: "mirror",
+				RefSpecs:   []config.RefSpec{mirrorRefSpec},
+				Tags:       git.NoTags,
+				Force:      true,
+				Auth:       auth,
+			}); err != nil && err != git.NoErrAlreadyUpToDate {
+				return err
+			}
+		}
+
+		remoteRefs := make(map[plumbing.ReferenceName]bool)
+		for _, ref := range refs {
+			remoteRefs[ref.Name()] = true
+
+			if ref.Name() == plumbing.HEAD && ref.Type() == plumbing.SymbolicReference {
+				if err := f.storage.SetReference(ref); err != nil {
+					return err
+				}
+			}
+		}
+
+		iter, err := f.storage.IterReferences()
+		if err != nil {
+			return err
+		}
+		defer iter.Close()
+
+		var stale []plumbing.ReferenceName
+		iter.ForEach(func(ref *plumbing.Reference) error {
+			if strings.HasPrefix(ref.Name().String(), "refs/") && !remoteRefs[ref.Name()] {
+				stale = append(stale, ref.Name())
+			}
+
+			return nil
+		})
+
+		for _, name := range stale {
+			if err := f.storage.RemoveReference(name); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	} else {
+		env := []string{
+			"GIT_TERMINAL_PROMPT=0",
+			"GIT_SSH_COMMAND=" + mirrorSshCommand,
+			"SSH_AUTH_SOCK=",
+			// Redirects could lead the fetch to a forbidden address.
+			"GIT_CONFIG_COUNT=1",
+			"GIT_CONFIG_KEY_0=http.followRedirects",
+			"GIT_CONFIG_VALUE_0=false",
+		}
+
+		if _, err := exec.OutputWithEnv(f.path, env, "git", "fetch", "--prune", "--force", "--no-tags", "--", rawURL, mirrorRefSpec); err != nil {
+			return err
+		}
+
+		// Follow the default branch of the remote.
+		if out, err := exec.OutputWithEnv(f.path, env, "git", "ls-remote", "--symref", "--", rawURL, "HEAD"); err != nil {
+			return err
+		} else if line := strings.SplitN(string(out), "\n", 2)[0]; strings.HasPrefix(line, "ref: ") {
+			target := strings.TrimSpace(strings.TrimSuffix(strings.TrimPrefix(line, "ref: "), "HEAD"))
+			if _, err := exec.Output(f.path, "git", "symbolic-ref", "HEAD", target); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+}
+
+// syncErrorFrom Returns the message recorded for a failed sync, including the
+// output of git binary if any.
+func syncErrorFrom(err error) string {
+	var exitErr *goexec.ExitError
+	if errors.As(err, &exitErr) && len(exitErr.Stderr) > 0 {
+		return strings.TrimSpace(string(exitErr.Stderr))
+	}
+
+	return err.Error()
+}
+
+// SyncMirror Fetches every reference of the remote, and records the time and
+// the error of the sync. A failing fetch is only recorded, not returned.
+//
+// Errors:
+//   - fault.ErrResourceNotFound if the repository is not a mirror
+func (f *Repo) SyncMirror() (*entity.PullMirror, error) {
+	lock, _ := mirrorLocks.LoadOrStore(f.GetID(), &sync.Mutex{})
+	lock.(*sync.Mutex).Lock()
+	defer lock.(*sync.Mutex).Unlock()
+
+	mirror, err := f.GetMirror()
+	if err != nil {
+		return nil, err
+	} else if mirror == nil {
+		return nil, fault.ErrResourceNotFound
+	}
+
+	now := time.Now()
+
+	mirror.UpdatedAt = now
+	mirror.SyncedAt = null.TimeFrom(now)
+	mirror.SyncError = null.String{}
+	if err := f.fetchMirror(mirror.URL); err != nil {
+		mirror.SyncError = null.StringFrom(syncErrorFrom(err))
+	}
+
+	if _, err := orm.GetBunInstance().
+		NewUpdate().
+		Model(mirror).
+		Column("updated_at", "synced_at", "sync_error").
+		WherePK().
+		Exec(f.ctx); err != nil {
+		return nil, err
+	}
+
+	return mirror, nil
+}
+
+// SyncDueMirrors Syncs the mirrors which are not synced within the sync
+// interval, and returns how many of them failed.
+func SyncDueMirrors(ctx context.Context) (int, error) {
+	var mirrors []*entity.PullMirror
+	if err := orm.
+		GetBunInstance().
+		NewSelect().
+		Model(&mirrors).
+		Relation("Repository").
+		Where(
+			"(? IS NULL OR ? < ?)",
+			bun.Ident("pull_mirror.synced_at"),
+			bun.Ident("pull_mirror.synced_at"),
+			time.Now().Add(-time.Duration(cfg.Cog.Git.Mirror.SyncInterval)*time.Minute),
+		).
+		Where("? IS NULL", bun.Ident("repository.removed_at")).
+		Order("pull_mirror.id").
+		Scan(ctx); err != nil {
+		return 0, err
+	}
+
+	failed := 0
+	for _, mirror := range mirrors {
+		if ctx.Err() != nil {
+			break
+		}
+
+		repo, err := GetRepoById(ctx, mirror.RepositoryID.Int64)
+		if err != nil {
+			return failed, err
+		}
+
+		if mirror, err := repo.SyncMirror(); err != nil {
+			return failed, err
+		} else if mirror.SyncError.Valid {
+			failed++
+			cfg.Log.Warn(
+				"failed to sync a mirror",
+				zap.Int64("repository", repo.GetID()),
+				zap.String("error", mirror.SyncError.String),
+			)
+		}
+	}
+
+	return failed, nil
+}
+
+// mirrorHook Rejects the pushes to the mirrors, which only follow their remote.
+type mirrorHook struct{}
+
+// PreReceive
+func (h *mirrorHook) PreReceive(ctx context.Context, repo *Repo, commands []*packp.Command) error {
+	return repo.checkWritable()
+}
+
+// PostReceive
+func (h *mirrorHook) PostReceive(ctx context.Context, repo *Repo, updates []*packp.Command) error {
+	return nil
+}
+
+// newMirrorHook
+func newMirrorHook() ReceiveHook {
+	return &mirrorHook{}
+}
+
+// MirrorHookOpt Rejects the pushes to the mirrors.
+var MirrorHookOpt = AsReceiveHook(newMirrorHook)
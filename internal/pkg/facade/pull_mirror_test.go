@@ -0,0 +1,109 @@
+/*
+ * Copyright 2021 Meraj Sahebdar
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package facade
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	"bitban.io/server/internal/cfg"
+)
+
+func TestValidateMirrorURL(t *testing.T) {
+	for url, expected := range map[string]error{
+		"https://github.com/bitban/server.git": nil,
+		"http://example.com/server.git":        nil,
+		"ssh://git@example.com/server.git":     nil,
+		"git@example.com:bitban/server.git":    nil,
+		"file:///var/repos/server.git":         ErrInvalidMirrorURL,
+		"/var/repos/server.git":                ErrInvalidMirrorURL,
+		"ftp://example.com/server.git":         ErrInvalidMirrorURL,
+		"-oProxyCommand=id":                    ErrInvalidMirrorURL,
+	} {
+		if err := ValidateMirrorURL(url); err != expected {
+			t.Errorf("expected ValidateMirrorURL(%q) to be %v, got: %v", url, expected, err)
+		}
+	}
+
+	cfg.Cog.Git.Mirror.AllowFile = true
+	defer func() { cfg.Cog.Git.Mirror.AllowFile = false }()
+
+	if err := ValidateMirrorURL("file:///var/repos/server.git"); err != nil {
+		t.Errorf("expected local paths to be allowed, got: %v", err)
+	}
+}
+
+func TestCheckMirrorAddress(t *testing.T) {
+	for url, expected := range map[string]error{
+		"https://93.184.216.34/server.git":         nil,
+		"ssh://git@[2606:2800:220:1::]/server.git": nil,
+		"file:///var/repos/server.git":             nil,
+		"https://127.0.0.1/server.git":             ErrMirrorAddressForbidden,
+		"http://localhost:8080/server.git":         ErrMirrorAddressForbidden,
+		"ssh://git@10.1.2.3/server.git":            ErrMirrorAddressForbidden,
+		"git@192.168.1.1:bitban/server.git":        ErrMirrorAddressForbidden,
+		"http://169.254.169.254/latest/meta-data":  ErrMirrorAddressForbidden,
+		"http://0.0.0.0/server.git":                ErrMirrorAddressForbidden,
+		"https://[::1]/server.git":                 ErrMirrorAddressForbidden,
+		"https://[::ffff:127.0.0.1]/server.git":    ErrMirrorAddressForbidden,
+		"https://[fd00::1]/server.git":             ErrMirrorAddressForbidden,
+		"https://[fe80::1]/server.git":             ErrMirrorAddressForbidden,
+	} {
+		ep, err := transport.NewEndpoint(url)
+		if err != nil {
+			t.Fatalf("failed to parse %q, got error: %s", url, err.Error())
+		}
+
+		if err := checkMirrorAddress(context.Background(), ep); err != expected {
+			t.Errorf("expected checkMirrorAddress(%q) to be %v, got: %v", url, expected, err)
+		}
+	}
+}
+
+func TestMirror(t *testing.T) {
+	t.Run("mirror", func(t *testing.T) {
+		ctx := context.Background()
+
+		account := getTestAccount(t, ctx)
+		repo := createTestRepo(t, ctx)
+
+		if _, err := repo.SetMirror("ftp://example.com/server.git"); err != ErrInvalidMirrorURL {
+			t.Errorf("expected error: %v, got: %v", ErrInvalidMirrorURL, err)
+		}
+
+		if _, err := repo.SetMirror("https://example.com/server.git"); err != nil {
+			t.Fatalf("failed to set the mirror, got error: %s", err.Error())
+		}
+
+		if _, err := repo.CreateBranch("feature", "HEAD", account.GetUser().DomainID); err == nil {
+			t.Errorf("expected creating a branch on the mirror to fail")
+		}
+
+		if err := repo.checkWritable(); err != ErrMirrorReadOnly {
+			t.Errorf("expected error: %v, got: %v", ErrMirrorReadOnly, err)
+		}
+
+		if _, err := repo.RemoveMirror(); err != nil {
+			t.Fatalf("failed to remove the mirror, got error: %s", err.Error())
+		}
+
+		if err := repo.checkWritable(); err != nil {
+			t.Errorf("got an unexpected error: %s", err.Error())
+		}
+	})
+}
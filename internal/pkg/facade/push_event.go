@@ -0,0 +1,229 @@
+/*
+ * Copyright 2021 Meraj Sahebdar
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package facade
+
+import (
+	"context"
+	"strings"
+
+	"github.com/go-git/go-git/v5/plumbing/protocol/packp"
+	"github.com/uptrace/bun"
+	"github.com/volatiletech/null/v8"
+	"bitban.io/server/internal/pkg/orm"
+	"bitban.io/server/internal/pkg/orm/entity"
+)
+
+// ActivityConfig Filters the push events of an activity feed.
+type ActivityConfig struct {
+	// Ref Limits the events to the ones updating the reference, either by its
+	// full or short name.
+	Ref string
+
+	// ForcedOnly Limits the events to the ones containing a forced update.
+	ForcedOnly bool
+
+	Skip  int
+	Limit int
+}
+
+// refNamesOf Returns the full reference names which a short name may refer to.
+func refNamesOf(ref string) []string {
+	if strings.HasPrefix(ref, "refs/") {
+		return []string{ref}
+	}
+
+	return []string{
+		"refs/heads/" + ref,
+		"refs/tags/" + ref,
+	}
+}
+
+// pushRefUpdateFrom Describes a reference update applied by a push.
+//
+// ErrorsRef:
+//   - facade.Repo.isAncestor
+func (f *Repo) pushRefUpdateFrom(update *packp.Command) (*entity.PushRefUpdate, error) {
+	refUpdate := &entity.PushRefUpdate{
+		Ref:    update.Name.String(),
+		OldSha: update.Old.String(),
+		NewSha: update.New.String(),
+		Action: string(update.Action()),
+	}
+
+	if update.Action() == packp.Update {
+		if ok, err := f.isAncestor(update.Old, update.New); err != nil {
+			return nil, err
+		} else {
+			refUpdate.IsForced = !ok
+		}
+	}
+
+	return refUpdate, nil
+}
+
+// RecordPushEvent Persists a push along with the reference updates it applied.
+//
+// ErrorsRef:
+//   - facade.Repo.pushRefUpdateFrom
+func (f *Repo) RecordPushEvent(pushInfo *PushInfo, updates []*packp.Command) (pushEvent *entity.PushEvent, err error) {
+	pushEvent = &entity.PushEvent{
+		Protocol:     pushInfo.Protocol,
+		RemoteAddr:   pushInfo.RemoteAddr,
+		RepositoryID: null.Int64From(f.GetID()),
+	}
+
+	if pushInfo.PusherID != 0 {
+		pushEvent.PusherID = null.Int64From(pushInfo.PusherID)
+	}
+
+	for _, update := range updates {
+		if refUpdate, err := f.pushRefUpdateFrom(update); err != nil {
+			return nil, err
+		} else {
+			pushEvent.RefUpdates = append(pushEvent.RefUpdates, refUpdate)
+		}
+	}
+
+	var tx bun.Tx
+	if tx, err = orm.GetBunInstance().BeginTx(f.ctx, nil); err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if _, err = tx.NewInsert().
+		Model(pushEvent).
+		Column("protocol", "remote_addr", "pusher_id", "repository_id").
+		Returning("id", "created_at").
+		Exec(f.ctx); err != nil {
+		return nil, err
+	}
+
+	for _, refUpdate := range pushEvent.RefUpdates {
+		refUpdate.PushEventID = null.Int64From(pushEvent.ID)
+	}
+
+	if len(pushEvent.RefUpdates) > 0 {
+		if _, err = tx.NewInsert().
+			Model(&pushEvent.RefUpdates).
+			Column("ref", "old_sha", "new_sha", "action", "is_forced", "push_event_id").
+			Returning("id").
+			Exec(f.ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return pushEvent, nil
+}
+
+// selectActivity Returns the query listing the push events of an activity feed.
+func selectActivity(pushEvents *[]*entity.PushEvent, config *ActivityConfig) *bun.SelectQuery {
+	query := orm.GetBunInstance().
+		NewSelect().
+		Model(pushEvents).
+		Relation("Pusher").
+		Relation("RefUpdates", func(sq *bun.SelectQuery) *bun.SelectQuery {
+			return sq.Order("push_ref_update.id")
+		})
+
+	if config.Ref != "" || config.ForcedOnly {
+		sq := orm.GetBunInstance().
+			NewSelect().
+			Model((*entity.PushRefUpdate)(nil)).
+			ColumnExpr("1").
+			Where("? = ?", bun.Ident("push_ref_update.push_event_id"), bun.Ident("push_event.id"))
+
+		if config.Ref != "" {
+			sq = sq.Where("? IN (?)", bun.Ident("push_ref_update.ref"), bun.In(refNamesOf(config.Ref)))
+		}
+
+		if config.ForcedOnly {
+			sq = sq.Where("? IS TRUE", bun.Ident("push_ref_update.is_forced"))
+		}
+
+		query = query.Where("EXISTS (?)", sq)
+	}
+
+	return query.
+		Order("push_event.id DESC").
+		Offset(config.Skip).
+		Limit(config.Limit)
+}
+
+// GetActivity Returns the push events of the repository, the latest first.
+func (f *Repo) GetActivity(config *ActivityConfig) ([]*entity.PushEvent, error) {
+	var pushEvents []*entity.PushEvent
+	if err := selectActivity(&pushEvents, config).
+		Where("? = ?", bun.Ident("push_event.repository_id"), f.GetID()).
+		Scan(f.ctx); err != nil {
+		return nil, err
+	}
+
+	for _, pushEvent := range pushEvents {
+		pushEvent.Repository = f.GetEntity()
+	}
+
+	return pushEvents, nil
+}
+
+// GetActivity Returns the push events of the user, the latest first.
+func (f *Account) GetActivity(config *ActivityConfig) ([]*entity.PushEvent, error) {
+	var pushEvents []*entity.PushEvent
+	if err := selectActivity(&pushEvents, config).
+		Relation("Repository").
+		Where("? = ?", bun.Ident("push_event.pusher_id"), f.user.DomainID).
+		Where("? IS NULL", bun.Ident("repository.removed_at")).
+		Scan(f.ctx); err != nil {
+		return nil, err
+	}
+
+	return pushEvents, nil
+}
+
+// pushEventHook Records every push in the activity feeds.
+type pushEventHook struct{}
+
+// PreReceive
+func (h *pushEventHook) PreReceive(ctx context.Context, repo *Repo, commands []*packp.Command) error {
+	return nil
+}
+
+// PostReceive
+func (h *pushEventHook) PostReceive(ctx context.Context, repo *Repo, updates []*packp.Command) error {
+	if len(updates) == 0 {
+		return nil
+	}
+
+	_, err := repo.RecordPushEvent(GetPushInfo(ctx), updates)
+	return err
+}
+
+// newPushEventHook
+func newPushEventHook() ReceiveHook {
+	return &pushEventHook{}
+}
+
+// PushEventHookOpt Records the push events after every push.
+var PushEventHookOpt = AsReceiveHook(newPushEventHook)
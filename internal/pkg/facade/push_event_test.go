@@ -0,0 +1,78 @@
+/*
+ * Copyright 2021 Meraj Sahebdar
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package facade
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/protocol/packp"
+)
+
+func TestPushEvent(t *testing.T) {
+	t.Run("push event", func(t *testing.T) {
+		ctx := context.Background()
+
+		account := getTestAccount(t, ctx)
+		repo := createTestRepo(t, ctx)
+
+		sha := plumbing.NewHash("5d41402abc4b2a76b9719d911017c592ae4b9f6a")
+
+		pushEvent, err := repo.RecordPushEvent(&PushInfo{
+			PusherID:   account.GetUser().DomainID,
+			Protocol:   PushProtocolSsh,
+			RemoteAddr: "192.0.2.1",
+		}, []*packp.Command{
+			{Name: "refs/heads/main", Old: plumbing.ZeroHash, New: sha},
+			{Name: "refs/tags/v1", Old: sha, New: plumbing.ZeroHash},
+		})
+		if err != nil {
+			t.Fatalf("failed to record the push event, got error: %s", err.Error())
+		}
+
+		if len(pushEvent.RefUpdates) != 2 ||
+			pushEvent.RefUpdates[0].Action != "create" ||
+			pushEvent.RefUpdates[1].Action != "delete" {
+			t.Errorf("expected a create and a delete update, got: %v", pushEvent.RefUpdates)
+		}
+
+		t.Run("repository activity", func(t *testing.T) {
+			if pushEvents, err := repo.GetActivity(&ActivityConfig{Ref: "main", Limit: 10}); err != nil {
+				t.Errorf("got an unexpected error: %s", err.Error())
+			} else if len(pushEvents) != 1 || pushEvents[0].ID != pushEvent.ID {
+				t.Errorf("expected the push event to be listed for main")
+			} else if pushEvents[0].Pusher == nil || pushEvents[0].RemoteAddr != "192.0.2.1" {
+				t.Errorf("expected the pusher and the remote address to be recorded")
+			}
+
+			if pushEvents, err := repo.GetActivity(&ActivityConfig{ForcedOnly: true, Limit: 10}); err != nil {
+				t.Errorf("got an unexpected error: %s", err.Error())
+			} else if len(pushEvents) != 0 {
+				t.Errorf("expected no forced push events, got: %d", len(pushEvents))
+			}
+		})
+
+		t.Run("user activity", func(t *testing.T) {
+			if pushEvents, err := account.GetActivity(&ActivityConfig{Limit: 1}); err != nil {
+				t.Errorf("got an unexpected error: %s", err.Error())
+			} else if len(pushEvents) != 1 || pushEvents[0].ID != pushEvent.ID {
+				t.Errorf("expected the latest push event of the user to be listed")
+			}
+		})
+	})
+}
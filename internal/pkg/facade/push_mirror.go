@@ -0,0 +1,451 @@
+/*
+ * Copyright 2021 Meraj Sahebdar
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package facade
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/protocol/packp"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	"github.com/go-git/go-git/v5/plumbing/transport/http"
+	"github.com/uptrace/bun"
+	"github.com/volatiletech/null/v8"
+	"go.uber.org/zap"
+	"bitban.io/server/internal/cfg"
+	"bitban.io/server/internal/pkg/exec"
+	"bitban.io/server/internal/pkg/fault"
+	"bitban.io/server/internal/pkg/orm"
+	"bitban.io/server/internal/pkg/orm/entity"
+	"bitban.io/server/internal/pkg/util"
+)
+
+var (
+	// PushMirrorTimeout The time to wait for a single push to a mirror.
+	PushMirrorTimeout = 5 * time.Minute
+
+	// PushMirrorMaxElapsedTime The time to keep retrying a failed push.
+	PushMirrorMaxElapsedTime = 10 * time.Minute
+)
+
+var (
+	// ErrInvalidMirrorCredentials
+	ErrInvalidMirrorCredentials = errors.New("the credentials are only supported over http and https")
+)
+
+// PushMirrorConfig
+type PushMirrorConfig struct {
+	URL      string
+	Username string
+	Password string
+	IsActive bool
+}
+
+// checkPushMirrorConfig
+//
+// Errors:
+//   - facade.ErrInvalidMirrorCredentials if the credentials are given for an ssh url
+// ErrorsRef:
+//   - facade.ValidateMirrorURL
+func checkPushMirrorConfig(config *PushMirrorConfig) error {
+	if err := ValidateMirrorURL(config.URL); err != nil {
+		return err
+	}
+
+	if config.Username != "" || config.Password != "" {
+		if ep, err := transport.NewEndpoint(config.URL); err != nil {
+			return ErrInvalidMirrorURL
+		} else if ep.Protocol != "http" && ep.Protocol != "https" {
+			return ErrInvalidMirrorCredentials
+		}
+	}
+
+	return nil
+}
+
+// GetPushMirrors Returns the push mirrors of the repository.
+func (f *Repo) GetPushMirrors() ([]*entity.PushMirror, error) {
+	var mirrors []*entity.PushMirror
+	if err := orm.GetBunInstance().
+		NewSelect().
+		Model(&mirrors).
+		Where("? = ?", bun.Ident("push_mirror.repository_id"), f.GetID()).
+		Order("push_mirror.id").
+		Scan(f.ctx); err != nil {
+		return nil, err
+	}
+
+	return mirrors, nil
+}
+
+// getPushMirror
+//
+// Errors:
+//   - fault.ErrResourceNotFound if the repository does not have such a push mirror
+func (f *Repo) getPushMirror(id int64) (*entity.PushMirror, error) {
+	mirror := new(entity.PushMirror)
+	if err := orm.GetBunInstance().
+		NewSelect().
+		Model(mirror).
+		Where("? = ?", bun.Ident("push_mirror.id"), id).
+		Where("? = ?", bun.Ident("push_mirror.repository_id"), f.GetID()).
+		Limit(1).
+		Scan(f.ctx); err != nil {
+		return nil, fault.ErrResourceNotFound
+	}
+
+	return mirror, nil
+}
+
+// AddPushMirror Registers a remote receiving the references updated by every
+// push, keeping its password encrypted.
+//
+// ErrorsRef:
+//   - facade.checkPushMirrorConfig
+func (f *Repo) AddPushMirror(config *PushMirrorConfig) (*entity.PushMirror, error) {
+	if err := checkPushMirrorConfig(config); err != nil {
+		return nil, err
+	}
+
+	mirror := &entity.PushMirror{
+		URL:          config.URL,
+		Username:     config.Username,
+		IsActive:     config.IsActive,
+		RepositoryID: null.Int64From(f.GetID()),
+	}
+
+	if config.Password != "" {
+		if password, err := util.Encrypt(cfg.Cog.Security.EncryptionKey, config.Password); err != nil {
+			return nil, err
+		} else {
+			mirror.Password = password
+		}
+	}
+
+	if _, err := orm.GetBunInstance().
+		NewInsert().
+		Model(mirror).
+		Column("url", "username", "password", "is_active", "repository_id").
+		Returning("id", "created_at", "updated_at").
+		Exec(f.ctx); err != nil {
+		return nil, err
+	}
+
+	return mirror, nil
+}
+
+// RemovePushMirror
+//
+// ErrorsRef:
+//   - facade.Repo.getPushMirror
+func (f *Repo) RemovePushMirror(id int64) (*entity.PushMirror, error) {
+	mirror, err := f.getPushMirror(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := orm.GetBunInstance().
+		NewDelete().
+		Model(mirror).
+		WherePK().
+		Exec(f.ctx); err != nil {
+		return nil, err
+	}
+
+	return mirror, nil
+}
+
+// GetRepoByPushMirrorId Returns the repository of a push mirror.
+//
+// Errors:
+//   - fault.ErrResourceNotFound if there is no such push mirror
+// ErrorsRef:
+//   - facade.GetRepoById
+func GetRepoByPushMirrorId(ctx context.Context, id int64) (*Repo, error) {
+	mirror := new(entity.PushMirror)
+	if err := orm.GetBunInstance().
+		NewSelect().
+		Model(mirror).
+		Where("? = ?", bun.Ident("push_mirror.id"), id).
+		Limit(1).
+		Scan(ctx); err != nil {
+		return nil, fault.ErrResourceNotFound
+	}
+
+	return GetRepoById(ctx, mirror.RepositoryID.Int64)
+}
+
+// pushToMirror Makes a single attempt to push the refspecs to the mirror,
+// pruning the references the repository does not have if asked.
+//
+// ErrorsRef:
+//   - facade.ValidateMirrorURL
+//   - facade.checkMirrorAddress
+func (f *Repo) pushToMirror(ctx context.Context, mirror *entity.PushMirror, refSpecs []string, prune bool) error {
+	// The url is checked again, as the config may have changed since it was set.
+	if err := ValidateMirrorURL(mirror.URL); err != nil {
+		return err
+	}
+
+	ep, err := transport.NewEndpoint(mirror.URL)
+	if err != nil {
+		return ErrInvalidMirrorURL
+	}
+
+	if err := checkMirrorAddress(ctx, ep); err != nil {
+		return err
+	}
+
+	password := ""
+	if mirror.Password != "" {
+		if password, err = util.Decrypt(cfg.Cog.Security.EncryptionKey, mirror.Password); err != nil {
+			return err
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, PushMirrorTimeout)
+	defer cancel()
+
+	if cfg.IsGoBackend() {
+		remote := git.NewRemote(f.storage, &config.RemoteConfig{
+			Name: "mirror",
+			URLs: []string{mirror.URL},
+		})
+
+		pushOptions := &git.PushOptions{
+			RemoteName: "mirror",
+			Auth:       mirrorAuthFrom(ep),
+		}
+
+		if mirror.Username != "" || password != "" {
+			pushOptions.Auth = &http.BasicAuth{
+				Username: mirror.Username,
+				Password: password,
+			}
+		}
+
+		// The prune option of go-git also deletes some of the pushed
+		// references, so the stale ones are deleted explicitly instead.
+		if prune {
+			refs, err := remote.ListContext(ctx, &git.ListOptions{Auth: pushOptions.Auth})
+			if err != nil && err != transport.ErrEmptyRemoteRepository {
+				return err
+			}
+
+			for _, ref := range refs {
+				if strings.HasPrefix(ref.Name().String(), "refs/") {
+					if _, err := f.storage.Reference(ref.Name()); err == plumbing.ErrReferenceNotFound {
+						refSpecs = append(refSpecs, ":"+ref.Name().String())
+					}
+				}
+			}
+		}
+
+		for _, refSpec := range refSpecs {
+			pushOptions.RefSpecs = append(pushOptions.RefSpecs, config.RefSpec(refSpec))
+		}
+
+		if err := remote.PushContext(ctx, pushOptions); err != nil && err != git.NoErrAlreadyUpToDate {
+			return err
+		}
+
+		return nil
+	} else {
+		env := []string{
+			"GIT_TERMINAL_PROMPT=0",
+			"GIT_SSH_COMMAND=" + mirrorSshCommand,
+			"SSH_AUTH_SOCK=",
+			// Redirects could lead the push to a forbidden address.
+			"GIT_CONFIG_KEY_0=http.followRedirects",
+			"GIT_CONFIG_VALUE_0=false",
+		}
+
+		if mirror.Username != "" || password != "" {
+			// Passed through the environment to be kept out of the process list.
+			env = append(
+				env,
+				"GIT_CONFIG_COUNT=2",
+				"GIT_CONFIG_KEY_1=http.extraHeader",
+				"GIT_CONFIG_VALUE_1=Authorization: Basic "+base64.StdEncoding.EncodeToString(
+					[]byte(mirror.Username+":"+password),
+				),
+			)
+		} else {
+			env = append(env, "GIT_CONFIG_COUNT=1")
+		}
+
+		args := []string{"push", "--force"}
+		if prune {
+			args = append(args, "--prune")
+		}
+
+		args = append(args, "--", mirror.URL)
+		args = append(args, refSpecs...)
+
+		if _, err := exec.OutputWithEnv(f.path, env, "git", args...); err != nil {
+			return errors.New(syncErrorFrom(err))
+		}
+
+		return nil
+	}
+}
+
+// recordPushMirrorAttempt Records the result of an attempt to push to the
+// mirror. A failed attempt of a queued push is scheduled again with an
+// exponential backoff until the push is queued for facade.PushMirrorMaxElapsedTime.
+func recordPushMirrorAttempt(ctx context.Context, mirror *entity.PushMirror, err error) error {
+	mirror.UpdatedAt = time.Now().In(time.UTC)
+	mirror.NextAttemptAt = null.Time{}
+
+	if err != nil {
+		mirror.Attempts++
+		mirror.PushError = null.StringFrom(err.Error())
+
+		if mirror.QueuedAt.Valid {
+			next := mirror.UpdatedAt.Add(retryIntervalFrom(mirror.Attempts))
+			if next.Sub(mirror.QueuedAt.Time) < PushMirrorMaxElapsedTime {
+				mirror.NextAttemptAt = null.TimeFrom(next)
+			}
+		}
+	} else {
+		mirror.Attempts = 0
+		mirror.PushedAt = null.TimeFrom(mirror.UpdatedAt)
+		mirror.PushError = null.String{}
+	}
+
+	if !mirror.NextAttemptAt.Valid {
+		mirror.QueuedAt = null.Time{}
+	}
+
+	_, dbErr := orm.GetBunInstance().
+		NewUpdate().
+		Model(mirror).
+		Column("updated_at", "attempts", "pushed_at", "push_error", "queued_at", "next_attempt_at").
+		WherePK().
+		Exec(ctx)
+
+	return dbErr
+}
+
+// SyncPushMirror Pushes every reference of the repository to the mirror once,
+// removing the ones the repository does not have. A failing push is only
+// recorded, not returned.
+//
+// ErrorsRef:
+//   - facade.Repo.getPushMirror
+func (f *Repo) SyncPushMirror(id int64) (*entity.PushMirror, error) {
+	mirror, err := f.getPushMirror(id)
+	if err != nil {
+		return nil, err
+	}
+
+	pushErr := f.pushToMirror(f.ctx, mirror, []string{mirrorRefSpec}, true)
+	if err := recordPushMirrorAttempt(f.ctx, mirror, pushErr); err != nil {
+		return nil, err
+	}
+
+	return mirror, nil
+}
+
+// SyncDuePushMirrors Syncs the active push mirrors whose queued pushes are due,
+// and returns how many of them failed.
+func SyncDuePushMirrors(ctx context.Context) (int, error) {
+	var mirrors []*entity.PushMirror
+	if err := orm.
+		GetBunInstance().
+		NewSelect().
+		Model(&mirrors).
+		Relation("Repository").
+		Where("? <= ?", bun.Ident("push_mirror.next_attempt_at"), time.Now().In(time.UTC)).
+		Where("? = ?", bun.Ident("push_mirror.is_active"), true).
+		Where("? IS NULL", bun.Ident("repository.removed_at")).
+		Order("push_mirror.id").
+		Scan(ctx); err != nil {
+		return 0, err
+	}
+
+	failed := 0
+	for _, mirror := range mirrors {
+		if ctx.Err() != nil {
+			break
+		}
+
+		repo, err := GetRepoById(ctx, mirror.RepositoryID.Int64)
+		if err != nil {
+			return failed, err
+		}
+
+		if mirror, err := repo.SyncPushMirror(mirror.ID); err != nil {
+			return failed, err
+		} else if mirror.PushError.Valid {
+			failed++
+			cfg.Log.Warn(
+				"failed to push to a mirror",
+				zap.Int64("pushMirrorId", mirror.ID),
+				zap.String("error", mirror.PushError.String),
+			)
+		}
+	}
+
+	return failed, nil
+}
+
+// queuePushMirrors Queues a sync of the active push mirrors of the repository,
+// which is done by facade.SyncDuePushMirrors.
+func (f *Repo) queuePushMirrors(ctx context.Context) error {
+	now := time.Now().In(time.UTC)
+
+	if _, err := orm.GetBunInstance().
+		NewUpdate().
+		Model((*entity.PushMirror)(nil)).
+		Set("? = ?", bun.Ident("queued_at"), now).
+		Set("? = ?", bun.Ident("next_attempt_at"), now).
+		Where("? = ?", bun.Ident("repository_id"), f.GetID()).
+		Where("? = ?", bun.Ident("is_active"), true).
+		Exec(ctx); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// pushMirrorHook Queues the syncs of the mirrors of the repository.
+type pushMirrorHook struct{}
+
+// PreReceive
+func (h *pushMirrorHook) PreReceive(ctx context.Context, repo *Repo, commands []*packp.Command) error {
+	return nil
+}
+
+// PostReceive
+func (h *pushMirrorHook) PostReceive(ctx context.Context, repo *Repo, updates []*packp.Command) error {
+	return repo.queuePushMirrors(ctx)
+}
+
+// newPushMirrorHook
+func newPushMirrorHook() ReceiveHook {
+	return &pushMirrorHook{}
+}
+
+// PushMirrorHookOpt Queues the syncs of the mirrors after every push.
+var PushMirrorHookOpt = AsReceiveHook(newPushMirrorHook)
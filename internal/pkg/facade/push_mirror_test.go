@@ -0,0 +1,156 @@
+/*
+ * Copyright 2021 Meraj Sahebdar
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package facade
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"bitban.io/server/internal/cfg"
+	"bitban.io/server/internal/pkg/util"
+)
+
+func TestCheckPushMirrorConfig(t *testing.T) {
+	for _, tc := range []struct {
+		config   PushMirrorConfig
+		expected error
+	}{
+		{PushMirrorConfig{URL: "https://example.com/server.git"}, nil},
+		{PushMirrorConfig{URL: "https://example.com/server.git", Username: "neo", Password: "secret"}, nil},
+		{PushMirrorConfig{URL: "git@example.com:bitban/server.git"}, nil},
+		{PushMirrorConfig{URL: "git@example.com:bitban/server.git", Password: "secret"}, ErrInvalidMirrorCredentials},
+		{PushMirrorConfig{URL: "ftp://example.com/server.git"}, ErrInvalidMirrorURL},
+	} {
+		if err := checkPushMirrorConfig(&tc.config); err != tc.expected {
+			t.Errorf("expected checkPushMirrorConfig(%q) to be %v, got: %v", tc.config.URL, tc.expected, err)
+		}
+	}
+}
+
+func TestPushMirror(t *testing.T) {
+	t.Run("push mirror", func(t *testing.T) {
+		ctx := context.Background()
+
+		repo := createTestRepo(t, ctx)
+
+		mirror, err := repo.AddPushMirror(&PushMirrorConfig{
+			URL:      "https://example.com/server.git",
+			Username: "neo",
+			Password: "secret",
+			IsActive: true,
+		})
+		if err != nil {
+			t.Fatalf("failed to add the push mirror, got error: %s", err.Error())
+		}
+
+		if mirror.Password == "secret" {
+			t.Errorf("expected the password to be stored encrypted")
+		} else if password, err := util.Decrypt(cfg.Cog.Security.EncryptionKey, mirror.Password); err != nil || password != "secret" {
+			t.Errorf("expected the password to be decrypted, got error: %v", err)
+		}
+
+		if mirrors, err := repo.GetPushMirrors(); err != nil {
+			t.Errorf("got an unexpected error: %s", err.Error())
+		} else if len(mirrors) != 1 {
+			t.Errorf("expected 1 push mirror, got: %d", len(mirrors))
+		}
+
+		if _, err := repo.RemovePushMirror(mirror.ID); err != nil {
+			t.Errorf("got an unexpected error: %s", err.Error())
+		}
+	})
+
+	t.Run("queue", func(t *testing.T) {
+		ctx := context.Background()
+
+		cfg.Cog.Git.Mirror.AllowFile = true
+		defer func() { cfg.Cog.Git.Mirror.AllowFile = false }()
+
+		repo := createTestRepo(t, ctx)
+		hash := commitTestFiles(t, repo, "main", "init", map[string]string{"README.md": "# Mirror\n"})
+
+		remotePath := t.TempDir()
+		remote, err := git.PlainInit(remotePath, true)
+		if err != nil {
+			t.Fatalf("failed to init the remote, got error: %s", err.Error())
+		}
+
+		mirror, err := repo.AddPushMirror(&PushMirrorConfig{URL: remotePath, IsActive: true})
+		if err != nil {
+			t.Fatalf("failed to add the push mirror, got error: %s", err.Error())
+		}
+
+		broken, err := repo.AddPushMirror(&PushMirrorConfig{URL: remotePath + "/missing", IsActive: true})
+		if err != nil {
+			t.Fatalf("failed to add the push mirror, got error: %s", err.Error())
+		}
+
+		if err := repo.queuePushMirrors(ctx); err != nil {
+			t.Fatalf("failed to queue the push mirrors, got error: %s", err.Error())
+		}
+
+		if _, err := SyncDuePushMirrors(ctx); err != nil {
+			t.Fatalf("got an unexpected error: %s", err.Error())
+		}
+
+		if ref, err := remote.Reference(plumbing.NewBranchReferenceName("main"), false); err != nil || ref.Hash() != hash {
+			t.Errorf("expected the branch to be pushed to the mirror, got error: %v", err)
+		}
+
+		var retryAt time.Time
+		if mirrors, err := repo.GetPushMirrors(); err != nil {
+			t.Errorf("got an unexpected error: %s", err.Error())
+		} else {
+			for _, m := range mirrors {
+				switch m.ID {
+				case mirror.ID:
+					if !m.PushedAt.Valid || m.PushError.Valid || m.QueuedAt.Valid || m.NextAttemptAt.Valid {
+						t.Errorf("expected the push to be done, got: %+v", m)
+					}
+				case broken.ID:
+					if m.Attempts != 1 || !m.PushError.Valid || !m.QueuedAt.Valid || !m.NextAttemptAt.Valid {
+						t.Errorf("expected the push to be retried later, got: %+v", m)
+					}
+
+					retryAt = m.NextAttemptAt.Time
+				}
+			}
+		}
+
+		PushMirrorMaxElapsedTime = 0
+		defer func() { PushMirrorMaxElapsedTime = 10 * time.Minute }()
+
+		time.Sleep(time.Until(retryAt))
+
+		if _, err := SyncDuePushMirrors(ctx); err != nil {
+			t.Fatalf("got an unexpected error: %s", err.Error())
+		}
+
+		if mirrors, err := repo.GetPushMirrors(); err != nil {
+			t.Errorf("got an unexpected error: %s", err.Error())
+		} else {
+			for _, m := range mirrors {
+				if m.ID == broken.ID && (m.Attempts != 2 || m.QueuedAt.Valid || m.NextAttemptAt.Valid) {
+					t.Errorf("expected the push to be given up, got: %+v", m)
+				}
+			}
+		}
+	})
+}
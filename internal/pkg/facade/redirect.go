@@ -0,0 +1,297 @@
+/*
+ * Copyright 2021 Meraj Sahebdar
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package facade
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/uptrace/bun"
+	"github.com/volatiletech/null/v8"
+	"bitban.io/server/internal/pkg/auth"
+	"bitban.io/server/internal/pkg/fault"
+	"bitban.io/server/internal/pkg/orm"
+	"bitban.io/server/internal/pkg/orm/entity"
+)
+
+// GetRepoByRedirect Returns the repository formerly reachable by the address.
+//
+// ErrorsRef:
+//   - facade.GetRepoById
+func GetRepoByRedirect(ctx context.Context, domainAddress string, repoAddress string) (*Repo, error) {
+	redirect := new(entity.RepositoryRedirect)
+	if err := orm.
+		GetBunInstance().
+		NewSelect().
+		Model(redirect).
+		Relation("Domain", func(sq *bun.SelectQuery) *bun.SelectQuery {
+			return sq.Where("? = ?", bun.Ident("domain.address"), domainAddress)
+		}).
+		Where("? = ?", bun.Ident("repository_redirect.address"), repoAddress).
+		Scan(ctx); err != nil {
+		return nil, err
+	}
+
+	return GetRepoById(ctx, redirect.RepositoryID.Int64)
+}
+
+// ResolveRepoByAddress Returns the repository by its current address or else
+// by one of its former ones, reporting whether it was redirected.
+//
+// ErrorsRef:
+//   - facade.GetRepoByAddress
+//   - facade.GetRepoByRedirect
+func ResolveRepoByAddress(ctx context.Context, domainAddress string, repoAddress string) (*Repo, bool, error) {
+	if repo, err := GetRepoByAddress(ctx, domainAddress, repoAddress); err == nil {
+		return repo, false, nil
+	} else if err != fault.ErrResourceNotFound {
+		return nil, false, err
+	}
+
+	if repo, err := GetRepoByRedirect(ctx, domainAddress, repoAddress); err != nil {
+		return nil, false, err
+	} else {
+		return repo, true, nil
+	}
+}
+
+// checkTeamGrants
+//
+// Errors:
+//   - facade.ErrRepositoryGrantedToTeams if a team of the domain is granted the repository
+func checkTeamGrants(id int64, domainAddress string) error {
+	enforcer := auth.GetEnforcerInstance()
+
+	obj := fmt.Sprintf("/repositories/%d", id)
+	for _, o := range []string{obj, obj + "/*"} {
+		for _, rule := range enforcer.GetFilteredNamedPolicy("p", 1, domainAddress, o) {
+			if isTeamSubject(rule[0]) {
+				return ErrRepositoryGrantedToTeams
+			}
+		}
+	}
+
+	return nil
+}
+
+// transferPolicies Moves the policies and the collaborators of the repository
+// to another domain. The grants of the teams are left in place, since the
+// teams are bound to the former domain.
+func transferPolicies(id int64, from string, to string) error {
+	enforcer := auth.GetEnforcerInstance()
+
+	obj := fmt.Sprintf("/repositories/%d", id)
+	for _, o := range []string{obj, obj + "/*"} {
+		rules := enforcer.GetFilteredNamedPolicy("p", 1, from, o)
+		if len(rules) == 0 {
+			continue
+		}
+
+		removed := make([][]string, 0, len(rules))
+		moved := make([][]string, 0, len(rules))
+		for _, rule := range rules {
+			if isTeamSubject(rule[0]) {
+				continue
+			}
+
+			removed = append(removed, rule)
+
+			rule = append([]string{}, rule...)
+			rule[1] = to
+
+			moved = append(moved, rule)
+		}
+
+		if len(moved) == 0 {
+			continue
+		}
+
+		if _, err := enforcer.RemoveNamedPolicies("p", removed); err != nil {
+			return err
+		}
+
+		if _, err := enforcer.AddNamedPolicies("p", moved); err != nil {
+			return err
+		}
+	}
+
+	// The collaborators keep their roles in the new domain.
+	for _, r := range collaboratorRoleActs {
+		rules := enforcer.GetFilteredNamedGroupingPolicy("g", 1, collaboratorRoleSubject(id, r.role), from)
+		if len(rules) == 0 {
+			continue
+		}
+
+		moved := make([][]string, 0, len(rules))
+		for _, rule := range rules {
+			rule = append([]string{}, rule...)
+			rule[2] = to
+
+			moved = append(moved, rule)
+		}
+
+		if _, err := enforcer.RemoveNamedGroupingPolicies("g", rules); err != nil {
+			return err
+		}
+
+		if _, err := enforcer.AddNamedGroupingPolicies("g", moved); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// move Moves the repository to the address within the domain along with its
+// storage, keeping the former address as a redirect.
+//
+// Errors:
+//   - facade.ErrRepositoryAddressTaken if the address is used by another repository
+// ErrorsRef:
+//   - facade.checkRepoAddress
+//   - facade.checkTeamGrants
+func (f *Repo) move(domain *entity.Domain, address string) (err error) {
+	repositoryEntity := f.repositoryEntity
+	if repositoryEntity.DomainID.Int64 == domain.ID && repositoryEntity.Address == address {
+		return nil
+	}
+
+	if err := checkRepoAddress(address); err != nil {
+		return err
+	}
+
+	transfer := domain.Address != f.domainAddress
+	if transfer {
+		if err := checkTeamGrants(repositoryEntity.ID, f.domainAddress); err != nil {
+			return err
+		}
+	}
+
+	if count, err := orm.GetBunInstance().
+		NewSelect().
+		Model((*entity.Repository)(nil)).
+		Where("? = ?", bun.Ident("repository.domain_id"), domain.ID).
+		Where("? = ?", bun.Ident("repository.address"), address).
+		Where("? IS NULL", bun.Ident("repository.removed_at")).
+		Count(f.ctx); err != nil {
+		return err
+	} else if count > 0 {
+		return ErrRepositoryAddressTaken
+	}
+
+	path, err := getStoragePath("repos", domain.Address, address)
+	if err != nil {
+		return err
+	}
+
+	var tx bun.Tx
+	if tx, err = orm.GetBunInstance().BeginTx(f.ctx, nil); err != nil {
+		return err
+	}
+
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	// Keep the former address, taking it over from any other repository.
+	redirect := &entity.RepositoryRedirect{
+		Address:      repositoryEntity.Address,
+		DomainID:     repositoryEntity.DomainID,
+		RepositoryID: null.Int64From(repositoryEntity.ID),
+	}
+	if _, err = tx.NewInsert().
+		Model(redirect).
+		Column("address", "domain_id", "repository_id").
+		On("CONFLICT (domain_id, address) DO UPDATE").
+		Set("repository_id = EXCLUDED.repository_id").
+		Set("updated_at = NOW()").
+		Exec(f.ctx); err != nil {
+		return err
+	}
+
+	// The new address belongs to the repository from now on.
+	if _, err = tx.NewDelete().
+		Model((*entity.RepositoryRedirect)(nil)).
+		Where("? = ?", bun.Ident("domain_id"), domain.ID).
+		Where("? = ?", bun.Ident("address"), address).
+		Exec(f.ctx); err != nil {
+		return err
+	}
+
+	moved := *repositoryEntity
+	moved.Address = address
+	moved.DomainID = null.Int64From(domain.ID)
+	moved.Domain = domain
+	moved.UpdatedAt = time.Now()
+	if _, err = tx.NewUpdate().
+		Model(&moved).
+		Column("updated_at", "address", "domain_id").
+		WherePK().
+		Exec(f.ctx); err != nil {
+		return err
+	}
+
+	if err = moveStorage(f.path, path); err != nil {
+		return err
+	}
+
+	// The policies are kept outside of the transaction, so they are moved
+	// back along with the storage if the move fails from here on.
+	if transfer {
+		if err = transferPolicies(moved.ID, f.domainAddress, domain.Address); err != nil {
+			transferPolicies(moved.ID, domain.Address, f.domainAddress)
+			moveStorage(path, f.path)
+			return err
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		if transfer {
+			transferPolicies(moved.ID, domain.Address, f.domainAddress)
+		}
+
+		moveStorage(path, f.path)
+		return err
+	}
+
+	if repo, err := repoFrom(f.ctx, &moved); err != nil {
+		return err
+	} else {
+		*f = *repo
+	}
+
+	return nil
+}
+
+// Rename Changes the address of the repository within its domain.
+//
+// ErrorsRef:
+//   - facade.Repo.move
+func (f *Repo) Rename(address string) error {
+	return f.move(f.repositoryEntity.Domain, address)
+}
+
+// Transfer Moves the repository to another domain, keeping its address.
+//
+// ErrorsRef:
+//   - facade.Repo.move
+func (f *Repo) Transfer(domain *entity.Domain) error {
+	return f.move(domain, f.repositoryEntity.Address)
+}
@@ -0,0 +1,95 @@
+/*
+ * Copyright 2021 Meraj Sahebdar
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package facade
+
+import (
+	"context"
+	"testing"
+
+	"syreclabs.com/go/faker"
+	"bitban.io/server/internal/pkg/fault"
+)
+
+func TestRedirect(t *testing.T) {
+	t.Run("rename", func(t *testing.T) {
+		ctx := context.Background()
+
+		account := getTestAccount(t, ctx)
+
+		domainAddress := account.GetDomain().Address
+		oldAddress := faker.Internet().Slug()
+		newAddress := faker.Internet().Slug()
+
+		repo, err := CreateRepoByAddress(ctx, domainAddress, oldAddress)
+		if err != nil {
+			t.Fatalf("failed to create the repository, got error: %s", err.Error())
+		}
+
+		if err := repo.Rename(newAddress); err != nil {
+			t.Fatalf("failed to rename the repository, got error: %s", err.Error())
+		}
+
+		if repo.GetEntity().Address != newAddress {
+			t.Errorf("expected address: %s, got: %s", newAddress, repo.GetEntity().Address)
+		}
+
+		if _, err := GetRepoByAddress(ctx, domainAddress, oldAddress); err != fault.ErrResourceNotFound {
+			t.Errorf("expected error: %v, got: %v", fault.ErrResourceNotFound, err)
+		}
+
+		if resolved, redirected, err := ResolveRepoByAddress(ctx, domainAddress, oldAddress); err != nil {
+			t.Errorf("got an unexpected error: %s", err.Error())
+		} else if !redirected || resolved.GetID() != repo.GetID() {
+			t.Errorf("expected the former address to redirect to the repository")
+		}
+
+		if _, redirected, err := ResolveRepoByAddress(ctx, domainAddress, newAddress); err != nil || redirected {
+			t.Errorf("expected the current address to resolve without a redirect, got error: %v", err)
+		}
+
+		// Renaming back takes over the redirect of the former address.
+		if err := repo.Rename(oldAddress); err != nil {
+			t.Fatalf("failed to rename the repository, got error: %s", err.Error())
+		}
+
+		if _, redirected, err := ResolveRepoByAddress(ctx, domainAddress, oldAddress); err != nil || redirected {
+			t.Errorf("expected the restored address to resolve without a redirect, got error: %v", err)
+		}
+
+		if err := repo.Rename("../" + newAddress); err != ErrInvalidRepositoryAddress {
+			t.Errorf("expected error: %v, got: %v", ErrInvalidRepositoryAddress, err)
+		}
+
+		other, err := CreateRepoByAddress(ctx, domainAddress, faker.Internet().Slug())
+		if err != nil {
+			t.Fatalf("failed to create the repository, got error: %s", err.Error())
+		}
+
+		if err := repo.Rename(other.GetEntity().Address); err != ErrRepositoryAddressTaken {
+			t.Errorf("expected error: %v, got: %v", ErrRepositoryAddressTaken, err)
+		}
+
+		// The address of a deleted repository is free to take.
+		if err := other.Delete(); err != nil {
+			t.Fatalf("failed to delete the repository, got error: %s", err.Error())
+		}
+
+		if err := repo.Rename(other.GetEntity().Address); err != nil {
+			t.Errorf("expected the address of the deleted repository to be free, got error: %v", err)
+		}
+	})
+}
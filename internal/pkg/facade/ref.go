@@ -0,0 +1,427 @@
+/*
+ * Copyright 2021 Meraj Sahebdar
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package facade
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/protocol/packp"
+	"bitban.io/server/internal/cfg"
+	"bitban.io/server/internal/pkg/exec"
+)
+
+// Ref Prefixes
+const (
+	BranchRefPrefix = "refs/heads/"
+	TagRefPrefix    = "refs/tags/"
+)
+
+var (
+	// ErrInvalidRefName
+	ErrInvalidRefName = errors.New("the provided name is not a valid git reference name")
+
+	// ErrRefAlreadyExists
+	ErrRefAlreadyExists = errors.New("a reference with the same name already exists")
+
+	// ErrRefNotFound
+	ErrRefNotFound = errors.New("the requested reference does not exist")
+
+	// ErrDefaultBranch
+	ErrDefaultBranch = errors.New("the default branch of the repository cannot be deleted")
+)
+
+// Ref
+type Ref struct {
+	Name   string
+	Target string
+}
+
+// TagConfig
+type TagConfig struct {
+	Name    string
+	Rev     string
+	Message string
+	Tagger  *Signature
+}
+
+// IsValidRefName Checks a branch or tag name against the rules of `git check-ref-format`.
+func IsValidRefName(name string) bool {
+	if name == "" || name == "@" ||
+		strings.HasPrefix(name, "-") ||
+		strings.HasPrefix(name, "/") ||
+		strings.HasSuffix(name, "/") ||
+		strings.HasSuffix(name, ".") ||
+		strings.Contains(name, "..") ||
+		strings.Contains(name, "//") ||
+		strings.Contains(name, "@{") ||
+		strings.ContainsAny(name, " ~^:?*[\\\x7f") {
+		return false
+	}
+
+	for _, r := range name {
+		if r < 0x20 {
+			return false
+		}
+	}
+
+	for _, component := range strings.Split(name, "/") {
+		if strings.HasPrefix(component, ".") || strings.HasSuffix(component, ".lock") {
+			return false
+		}
+	}
+
+	return true
+}
+
+// GetRefs Returns the references of the repository starting with the prefix.
+func (f *Repo) GetRefs(prefix string) ([]*Ref, error) {
+	refs := []*Ref{}
+
+	if cfg.IsGoBackend() {
+		iter, err := f.repositoryInstance.References()
+		if err != nil {
+			return nil, err
+		}
+
+		if err := iter.ForEach(func(ref *plumbing.Reference) error {
+			if ref.Type() == plumbing.HashReference && strings.HasPrefix(ref.Name().String(), prefix) {
+				refs = append(refs, &Ref{
+					Name:   ref.Name().String(),
+					Target: ref.Hash().String(),
+				})
+			}
+
+			return nil
+		}); err != nil {
+			return nil, err
+		}
+	} else {
+		out, err := exec.Output(
+			f.path,
+			"git",
+			"for-each-ref",
+			"--format=%(objectname) %(refname)",
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, line := range strings.Split(string(out), "\n") {
+			if fields := strings.SplitN(line, " ", 2); len(fields) == 2 && strings.HasPrefix(fields[1], prefix) {
+				refs = append(refs, &Ref{
+					Name:   fields[1],
+					Target: fields[0],
+				})
+			}
+		}
+	}
+
+	sort.Slice(refs, func(i, j int) bool {
+		return refs[i].Name < refs[j].Name
+	})
+
+	return refs, nil
+}
+
+// getRef
+//
+// Errors:
+//   - facade.ErrRefNotFound if the reference does not exist
+func (f *Repo) getRef(name string) (*Ref, error) {
+	if cfg.IsGoBackend() {
+		if ref, err := f.repositoryInstance.Reference(plumbing.ReferenceName(name), false); err != nil {
+			return nil, ErrRefNotFound
+		} else {
+			return &Ref{
+				Name:   ref.Name().String(),
+				Target: ref.Hash().String(),
+			}, nil
+		}
+	} else {
+		if out, err := exec.Output(
+			f.path,
+			"git",
+			"rev-parse",
+			"--verify",
+			"--quiet",
+			name,
+		); err != nil {
+			return nil, ErrRefNotFound
+		} else {
+			return &Ref{
+				Name:   name,
+				Target: strings.TrimSpace(string(out)),
+			}, nil
+		}
+	}
+}
+
+// resolveCommitHash Returns the hash of the commit that the revision points to.
+//
+// Errors:
+//   - facade.ErrRevisionNotFound if the revision does not point to any commit
+func (f *Repo) resolveCommitHash(rev string) (string, error) {
+	if cfg.IsGoBackend() {
+		if commit, err := f.resolveCommit(rev); err != nil {
+			return "", err
+		} else {
+			return commit.Hash.String(), nil
+		}
+	} else {
+		if err := f.verifyCommit(rev); err != nil {
+			return "", err
+		}
+
+		if out, err := exec.Output(
+			f.path,
+			"git",
+			"rev-parse",
+			"--verify",
+			fmt.Sprintf("%s^{commit}", rev),
+		); err != nil {
+			return "", ErrRevisionNotFound
+		} else {
+			return strings.TrimSpace(string(out)), nil
+		}
+	}
+}
+
+// GetDefaultBranch Returns the name of the branch that HEAD points to.
+func (f *Repo) GetDefaultBranch() (string, error) {
+	if cfg.IsGoBackend() {
+		if head, err := f.storage.Reference(plumbing.HEAD); err != nil {
+			return "", err
+		} else {
+			return head.Target().String(), nil
+		}
+	} else {
+		if out, err := exec.Output(f.path, "git", "symbolic-ref", "HEAD"); err != nil {
+			return "", err
+		} else {
+			return strings.TrimSpace(string(out)), nil
+		}
+	}
+}
+
+// createRef Creates a new reference pointing to the hash, failing if it already
+// exists or the repository is a mirror.
+func (f *Repo) createRef(name string, hash string) error {
+	if err := f.checkWritable(); err != nil {
+		return err
+	}
+
+	if _, err := f.getRef(name); err == nil {
+		return ErrRefAlreadyExists
+	}
+
+	if cfg.IsGoBackend() {
+		return f.storage.SetReference(
+			plumbing.NewHashReference(plumbing.ReferenceName(name), plumbing.NewHash(hash)),
+		)
+	} else {
+		// An empty old value makes git ensure that the reference does not exist yet.
+		if _, err := exec.Output(f.path, "git", "update-ref", name, hash, ""); err != nil {
+			return ErrRefAlreadyExists
+		}
+
+		return nil
+	}
+}
+
+// deleteRef
+func (f *Repo) deleteRef(name string) (*Ref, error) {
+	if err := f.checkWritable(); err != nil {
+		return nil, err
+	}
+
+	ref, err := f.getRef(name)
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.IsGoBackend() {
+		if err := f.storage.RemoveReference(plumbing.ReferenceName(name)); err != nil {
+			return nil, err
+		}
+	} else {
+		if _, err := exec.Output(f.path, "git", "update-ref", "-d", name, ref.Target); err != nil {
+			return nil, err
+		}
+	}
+
+	return ref, nil
+}
+
+// CreateBranch Creates a new branch pointing to the commit of the revision,
+// if the protection rules allow the pusher to.
+//
+// Errors:
+//   - facade.ErrInvalidRefName if the name is not a valid branch name
+//   - facade.ErrRevisionNotFound if the revision does not point to any commit
+//   - facade.ErrRefAlreadyExists if the branch already exists
+//   - facade.ErrMirrorReadOnly if the repository is a mirror
+// ErrorsRef:
+//   - facade.Repo.checkApiRefUpdate
+func (f *Repo) CreateBranch(name string, rev string, pusherID int64) (*Ref, error) {
+	if !IsValidRefName(name) {
+		return nil, ErrInvalidRefName
+	}
+
+	hash, err := f.resolveCommitHash(rev)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := f.checkApiRefUpdate(pusherID, &packp.Command{
+		Name: plumbing.ReferenceName(BranchRefPrefix + name),
+		Old:  plumbing.ZeroHash,
+		New:  plumbing.NewHash(hash),
+	}); err != nil {
+		return nil, err
+	}
+
+	if err := f.createRef(BranchRefPrefix+name, hash); err != nil {
+		return nil, err
+	}
+
+	return &Ref{
+		Name:   BranchRefPrefix + name,
+		Target: hash,
+	}, nil
+}
+
+// DeleteBranch Deletes the branch, if the protection rules allow the pusher to.
+//
+// Errors:
+//   - facade.ErrInvalidRefName if the name is not a valid branch name
+//   - facade.ErrRefNotFound if the branch does not exist
+//   - facade.ErrDefaultBranch if the branch is the default branch
+//   - facade.ErrMirrorReadOnly if the repository is a mirror
+// ErrorsRef:
+//   - facade.Repo.checkApiRefUpdate
+func (f *Repo) DeleteBranch(name string, pusherID int64) (*Ref, error) {
+	if !IsValidRefName(name) {
+		return nil, ErrInvalidRefName
+	}
+
+	if defaultBranch, err := f.GetDefaultBranch(); err != nil {
+		return nil, err
+	} else if defaultBranch == BranchRefPrefix+name {
+		return nil, ErrDefaultBranch
+	}
+
+	ref, err := f.getRef(BranchRefPrefix + name)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := f.checkApiRefUpdate(pusherID, &packp.Command{
+		Name: plumbing.ReferenceName(ref.Name),
+		Old:  plumbing.NewHash(ref.Target),
+		New:  plumbing.ZeroHash,
+	}); err != nil {
+		return nil, err
+	}
+
+	return f.deleteRef(ref.Name)
+}
+
+// CreateTag Creates a lightweight tag, or an annotated one if the message is not empty.
+//
+// Errors:
+//   - facade.ErrInvalidRefName if the name is not a valid tag name
+//   - facade.ErrRevisionNotFound if the revision does not point to any commit
+//   - facade.ErrRefAlreadyExists if the tag already exists
+//   - facade.ErrMirrorReadOnly if the repository is a mirror
+func (f *Repo) CreateTag(tagConfig *TagConfig) (*Ref, error) {
+	if !IsValidRefName(tagConfig.Name) {
+		return nil, ErrInvalidRefName
+	}
+
+	hash, err := f.resolveCommitHash(tagConfig.Rev)
+	if err != nil {
+		return nil, err
+	}
+
+	name := TagRefPrefix + tagConfig.Name
+
+	if tagConfig.Message == "" {
+		if err := f.createRef(name, hash); err != nil {
+			return nil, err
+		}
+
+		return &Ref{
+			Name:   name,
+			Target: hash,
+		}, nil
+	}
+
+	if _, err := f.getRef(name); err == nil {
+		return nil, ErrRefAlreadyExists
+	}
+
+	if cfg.IsGoBackend() {
+		if _, err := f.repositoryInstance.CreateTag(tagConfig.Name, plumbing.NewHash(hash), &git.CreateTagOptions{
+			Tagger: &object.Signature{
+				Name:  tagConfig.Tagger.Name,
+				Email: tagConfig.Tagger.Email,
+				When:  tagConfig.Tagger.When,
+			},
+			Message: tagConfig.Message,
+		}); err == git.ErrTagExists {
+			return nil, ErrRefAlreadyExists
+		} else if err != nil {
+			return nil, err
+		}
+	} else {
+		if _, err := exec.Output(
+			f.path,
+			"git",
+			"-c", fmt.Sprintf("user.name=%s", tagConfig.Tagger.Name),
+			"-c", fmt.Sprintf("user.email=%s", tagConfig.Tagger.Email),
+			"tag",
+			"--annotate",
+			fmt.Sprintf("--message=%s", tagConfig.Message),
+			tagConfig.Name,
+			hash,
+		); err != nil {
+			return nil, err
+		}
+	}
+
+	return f.getRef(name)
+}
+
+// DeleteTag
+//
+// Errors:
+//   - facade.ErrInvalidRefName if the name is not a valid tag name
+//   - facade.ErrRefNotFound if the tag does not exist
+//   - facade.ErrMirrorReadOnly if the repository is a mirror
+func (f *Repo) DeleteTag(name string) (*Ref, error) {
+	if !IsValidRefName(name) {
+		return nil, ErrInvalidRefName
+	}
+
+	return f.deleteRef(TagRefPrefix + name)
+}
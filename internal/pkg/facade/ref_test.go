@@ -0,0 +1,122 @@
+/*
+ * Copyright 2021 Meraj Sahebdar
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package facade
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+func TestIsValidRefName(t *testing.T) {
+	for name, expected := range map[string]bool{
+		"main":            true,
+		"feature/login":   true,
+		"v1.0.0":          true,
+		"":                false,
+		"@":               false,
+		"-main":           false,
+		"/main":           false,
+		"main/":           false,
+		"main.":           false,
+		"a..b":            false,
+		"a//b":            false,
+		"a@{b":            false,
+		"a b":             false,
+		"a:b":             false,
+		"feature/.hidden": false,
+		"main.lock":       false,
+	} {
+		if got := IsValidRefName(name); got != expected {
+			t.Errorf("expected IsValidRefName(%q) to be %t, got: %t", name, expected, got)
+		}
+	}
+}
+
+func TestDeleteRef(t *testing.T) {
+	ctx := context.Background()
+	repo := createTestRepo(t, ctx)
+
+	head := commitTestFiles(t, repo, "main", "initial", map[string]string{"README.md": "hello\n"})
+	for _, name := range []string{"refs/heads/feature", "refs/tags/v1.0.0"} {
+		if err := repo.storage.SetReference(plumbing.NewHashReference(plumbing.ReferenceName(name), head)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// expectRef Checks that the reference is still there.
+	expectRef := func(t *testing.T, name plumbing.ReferenceName) {
+		t.Helper()
+
+		if _, err := repo.storage.Reference(name); err != nil {
+			t.Errorf("expected %s to exist, got error: %v", name, err)
+		}
+	}
+
+	t.Run("tag traversal", func(t *testing.T) {
+		for _, name := range []string{"../heads/main", "../../HEAD", "v1.0.0/../../heads/feature"} {
+			if _, err := repo.DeleteTag(name); err != ErrInvalidRefName {
+				t.Errorf("expected error: %v for %q, got: %v", ErrInvalidRefName, name, err)
+			}
+		}
+
+		expectRef(t, "refs/heads/main")
+		expectRef(t, "refs/heads/feature")
+		expectRef(t, plumbing.HEAD)
+	})
+
+	t.Run("branch traversal", func(t *testing.T) {
+		for _, name := range []string{"../../HEAD", "../tags/v1.0.0", "feature/../main"} {
+			if _, err := repo.DeleteBranch(name, 0); err != ErrInvalidRefName {
+				t.Errorf("expected error: %v for %q, got: %v", ErrInvalidRefName, name, err)
+			}
+		}
+
+		expectRef(t, plumbing.HEAD)
+		expectRef(t, "refs/heads/main")
+		expectRef(t, "refs/tags/v1.0.0")
+	})
+
+	t.Run("default branch", func(t *testing.T) {
+		if _, err := repo.DeleteBranch("main", 0); err != ErrDefaultBranch {
+			t.Errorf("expected error: %v, got: %v", ErrDefaultBranch, err)
+		}
+	})
+
+	t.Run("branch", func(t *testing.T) {
+		if ref, err := repo.DeleteBranch("feature", 0); err != nil {
+			t.Errorf("got an unexpected error: %s", err.Error())
+		} else if ref.Name != "refs/heads/feature" || ref.Target != head.String() {
+			t.Errorf("expected the deleted branch, got: %+v", ref)
+		}
+
+		if _, err := repo.DeleteBranch("feature", 0); err != ErrRefNotFound {
+			t.Errorf("expected error: %v, got: %v", ErrRefNotFound, err)
+		}
+	})
+
+	t.Run("tag", func(t *testing.T) {
+		if _, err := repo.DeleteTag("v1.0.0"); err != nil {
+			t.Errorf("got an unexpected error: %s", err.Error())
+		}
+
+		if _, err := repo.DeleteTag("v1.0.0"); err != ErrRefNotFound {
+			t.Errorf("expected error: %v, got: %v", ErrRefNotFound, err)
+		}
+	})
+}
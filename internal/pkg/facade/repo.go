@@ -18,8 +18,10 @@ package facade
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
+	"os"
 	"strings"
 	"sync"
 
@@ -29,7 +31,9 @@ import (
 	"github.com/go-git/go-git/v5"
 	"github.com/go-git/go-git/v5/plumbing"
 	"github.com/go-git/go-git/v5/plumbing/cache"
+	"github.com/go-git/go-git/v5/plumbing/format/packfile"
 	"github.com/go-git/go-git/v5/plumbing/format/pktline"
+	"github.com/go-git/go-git/v5/plumbing/object"
 	"github.com/go-git/go-git/v5/plumbing/protocol/packp"
 	"github.com/go-git/go-git/v5/plumbing/protocol/packp/capability"
 	"github.com/go-git/go-git/v5/plumbing/storer"
@@ -48,8 +52,14 @@ import (
 
 // Services
 const (
-	GitReceivePack = "git-receive-pack"
-	GitUploadPack  = "git-upload-pack"
+	GitReceivePack   = "git-receive-pack"
+	GitUploadPack    = "git-upload-pack"
+	GitUploadArchive = "git-upload-archive"
+)
+
+var (
+	// ErrRevisionNotFound
+	ErrRevisionNotFound = errors.New("the requested revision does not exist")
 )
 
 // Capabilities
@@ -59,10 +69,31 @@ const (
 
 // ServerPackConfig
 type ServerPackConfig struct {
-	R       io.Reader
-	W       io.Writer
-	Service string
-	IsSsh   bool
+	R          io.Reader
+	W          io.Writer
+	Service    string
+	Protocol   string
+	IsSsh      bool
+	Pusher     *Account
+	RemoteAddr string
+}
+
+// getPushInfo Returns the push described by the config.
+func (c *ServerPackConfig) getPushInfo() *PushInfo {
+	pushInfo := &PushInfo{
+		Protocol:   PushProtocolHttp,
+		RemoteAddr: c.RemoteAddr,
+	}
+
+	if c.IsSsh {
+		pushInfo.Protocol = PushProtocolSsh
+	}
+
+	if c.Pusher != nil {
+		pushInfo.PusherID = c.Pusher.GetUser().DomainID
+	}
+
+	return pushInfo
 }
 
 // repoBackend
@@ -135,8 +166,16 @@ func (f *Repo) advertiseRefs(w io.Writer, sess transport.Session) error {
 }
 
 // AdvertiseRefs
-func (f *Repo) AdvertiseRefs(w io.Writer, service string) error {
+func (f *Repo) AdvertiseRefs(w io.Writer, service string, protocol string) error {
 	if cfg.IsGoBackend() {
+		if service == GitUploadPack && IsProtocolV2(protocol) {
+			enc := pktline.NewEncoder(w)
+			enc.Encodef("# service=%s\n", service)
+			enc.Flush()
+
+			return advertiseCapabilitiesV2(w)
+		}
+
 		var err error
 
 		var sess transport.Session
@@ -174,6 +213,7 @@ func (f *Repo) AdvertiseRefs(w io.Writer, service string) error {
 			return err
 		} else {
 			cmd.Dir = f.path
+			cmd.Env = withProtocolEnv(protocol)
 
 			if err := cmd.Start(); err != nil {
 				return err
@@ -181,6 +221,7 @@ func (f *Repo) AdvertiseRefs(w io.Writer, service string) error {
 
 			enc := pktline.NewEncoder(w)
 			enc.Encodef("# service=%s\n", service)
+			enc.Flush()
 
 			io.Copy(w, stdout)
 			io.Copy(w, stderr)
@@ -218,12 +259,18 @@ func (f *Repo) ServePack(serveConfig *ServerPackConfig) error {
 				return err
 			}
 
-			if status, err := sess.ReceivePack(f.ctx, req); status != nil {
+			if status, err := f.receivePack(sess, req, serveConfig.getPushInfo()); status != nil {
 				return status.Encode(w)
 			} else {
 				return err
 			}
+		case GitUploadArchive:
+			return f.serveUploadArchive(r, w)
 		case GitUploadPack:
+			if IsProtocolV2(serveConfig.Protocol) {
+				return f.serveUploadPackV2(r, w, serveConfig.IsSsh)
+			}
+
 			sess, err := f.initUploadPackSession()
 			if err != nil {
 				return err
@@ -253,10 +300,21 @@ func (f *Repo) ServePack(serveConfig *ServerPackConfig) error {
 		//
 		// Serve by git binary.
 
-		args := []string{
-			strings.TrimPrefix(serveConfig.Service, "git-"),
+		var args []string
+
+		env := withProtocolEnv(serveConfig.Protocol)
+
+		if serveConfig.Service == GitReceivePack {
+			if hooksPath, err := getHooksPath(); err != nil {
+				return err
+			} else {
+				args = append(args, "-c", "core.hooksPath="+hooksPath)
+				env = append(env, hookEnv(f.GetID(), serveConfig.getPushInfo())...)
+			}
 		}
 
+		args = append(args, strings.TrimPrefix(serveConfig.Service, "git-"))
+
 		if !serveConfig.IsSsh {
 			args = append(args, "--stateless-rpc")
 		}
@@ -270,6 +328,7 @@ func (f *Repo) ServePack(serveConfig *ServerPackConfig) error {
 			return err
 		} else {
 			cmd.Dir = f.path
+			cmd.Env = env
 
 			if err := cmd.Start(); err != nil {
 				return err
@@ -286,18 +345,162 @@ func (f *Repo) ServePack(serveConfig *ServerPackConfig) error {
 	}
 }
 
+// receivePack Stores the pushed objects, and updates the references which are
+// not rejected by the pre-receive hooks or the branch protection rules. The
+// rejected references are reported along with the others in the report status.
+func (f *Repo) receivePack(sess transport.ReceivePackSession, req *packp.ReferenceUpdateRequest, pushInfo *PushInfo) (*packp.ReportStatus, error) {
+	if req.Packfile != nil {
+		err := packfile.UpdateObjectStorage(f.storage, req.Packfile)
+		req.Packfile.Close()
+		req.Packfile = nil
+
+		if err != nil {
+			if !req.Capabilities.Supports(capability.ReportStatus) {
+				return nil, err
+			}
+
+			status := packp.NewReportStatus()
+			status.UnpackStatus = err.Error()
+
+			return status, err
+		}
+	}
+
+	hookCtx := withPushInfo(f.ctx, pushInfo)
+
+	var rejections map[plumbing.ReferenceName]error
+
+	if err := runPreReceiveHooks(hookCtx, f, req.Commands); err != nil {
+		rejections = make(map[plumbing.ReferenceName]error)
+		for _, cmd := range req.Commands {
+			rejections[cmd.Name] = err
+		}
+	} else if rejections, err = f.CheckRefUpdates(pushInfo.PusherID, req.Commands); err != nil {
+		return nil, err
+	}
+
+	var rejected []*packp.Command
+
+	accepted := make([]*packp.Command, 0, len(req.Commands))
+	for _, cmd := range req.Commands {
+		if _, ok := rejections[cmd.Name]; ok {
+			rejected = append(rejected, cmd)
+		} else {
+			accepted = append(accepted, cmd)
+		}
+	}
+
+	req.Commands = accepted
+
+	status, err := sess.ReceivePack(f.ctx, req)
+	if status != nil {
+		for _, cmd := range rejected {
+			status.CommandStatuses = append(status.CommandStatuses, &packp.CommandStatus{
+				ReferenceName: cmd.Name,
+				Status:        rejections[cmd.Name].Error(),
+			})
+		}
+	}
+
+	if updates := f.appliedCommands(accepted); len(updates) > 0 {
+		runPostReceiveHooks(hookCtx, f, updates)
+	}
+
+	return status, err
+}
+
+// appliedCommands Returns the commands which the references are updated by.
+func (f *Repo) appliedCommands(commands []*packp.Command) []*packp.Command {
+	var applied []*packp.Command
+	for _, cmd := range commands {
+		ref, err := f.storage.Reference(cmd.Name)
+
+		switch cmd.Action() {
+		case packp.Delete:
+			if err == plumbing.ErrReferenceNotFound {
+				applied = append(applied, cmd)
+			}
+		default:
+			if err == nil && ref.Hash() == cmd.New {
+				applied = append(applied, cmd)
+			}
+		}
+	}
+
+	return applied
+}
+
+// resolveCommit
+//
+// Errors:
+//   - facade.ErrRevisionNotFound if the revision does not point to any commit
+func (f *Repo) resolveCommit(rev string) (*object.Commit, error) {
+	if hash, err := f.repositoryInstance.ResolveRevision(plumbing.Revision(rev)); err != nil {
+		return nil, ErrRevisionNotFound
+	} else {
+		return f.repositoryInstance.CommitObject(*hash)
+	}
+}
+
+// verifyCommit Checks whether the revision points to a commit using git binary.
+//
+// Errors:
+//   - facade.ErrRevisionNotFound if the revision does not point to any commit
+func (f *Repo) verifyCommit(rev string) error {
+	if strings.HasPrefix(rev, "-") {
+		return ErrRevisionNotFound
+	}
+
+	if _, err := exec.Output(
+		f.path,
+		"git",
+		"rev-parse",
+		"--verify",
+		"--quiet",
+		fmt.Sprintf("%s^{commit}", rev),
+	); err != nil {
+		return ErrRevisionNotFound
+	}
+
+	return nil
+}
+
+// withProtocolEnv Returns the environment to run git binary with the requested protocol.
+func withProtocolEnv(protocol string) []string {
+	env := os.Environ()
+	if protocol != "" {
+		env = append(env, fmt.Sprintf("GIT_PROTOCOL=%s", protocol))
+	}
+
+	return env
+}
+
 // GetID
 func (f *Repo) GetID() int64 {
 	return f.repositoryEntity.ID
 }
 
+// GetDomainAddress
+func (f *Repo) GetDomainAddress() string {
+	return f.domainAddress
+}
+
 // GetEntity
 func (f *Repo) GetEntity() *entity.Repository {
 	return f.repositoryEntity
 }
 
 // CreateRepoByAddress
+//
+// Errors:
+//   - facade.ErrRepositoryAddressTaken if the address is used by another repository
+// ErrorsRef:
+//   - facade.checkRepoAddress
 func CreateRepoByAddress(ctx context.Context, domainAddress string, repoAddress string) (repo *Repo, err error) {
+	if err := checkRepoAddress(repoAddress); err != nil {
+		return nil, err
+	}
+
 	domain := new(entity.Domain)
 	if err := orm.
 		GetBunInstance().
@@ -308,6 +511,18 @@ func CreateRepoByAddress(ctx context.Context, domainAddress string, repoAddress
 		return nil, err
 	}
 
+	if count, err := orm.GetBunInstance().
+		NewSelect().
+		Model((*entity.Repository)(nil)).
+		Where("? = ?", bun.Ident("repository.domain_id"), domain.ID).
+		Where("? = ?", bun.Ident("repository.address"), repoAddress).
+		Where("? IS NULL", bun.Ident("repository.removed_at")).
+		Count(ctx); err != nil {
+		return nil, err
+	} else if count > 0 {
+		return nil, ErrRepositoryAddressTaken
+	}
+
 	var tx bun.Tx
 	if tx, err = orm.GetBunInstance().BeginTx(ctx, nil); err != nil {
 		return nil, err
@@ -320,13 +535,15 @@ func CreateRepoByAddress(ctx context.Context, domainAddress string, repoAddress
 	}()
 
 	repositoryEntity := &entity.Repository{
-		Address:  repoAddress,
-		DomainID: null.Int64From(domain.ID),
+		Address:    repoAddress,
+		Visibility: VisibilityPrivate,
+		DomainID:   null.Int64From(domain.ID),
+		Domain:     domain,
 	}
 	if _, err := tx.
 		NewInsert().
 		Model(repositoryEntity).
-		Column("address", "domain_id").
+		Column("address", "visibility", "domain_id").
 		Exec(ctx); err != nil {
 		return nil, err
 	}
@@ -393,10 +610,36 @@ func GetRepoByAddress(ctx context.Context, domainAddress string, repoAddress str
 			return sq.Where("? = ?", bun.Ident("domain.address"), domainAddress)
 		}).
 		Where("? = ?", bun.Ident("repository.address"), repoAddress).
+		Where("? IS NULL", bun.Ident("repository.removed_at")).
+		Scan(ctx); err != nil {
+		return nil, err
+	}
+
+	return repoFrom(ctx, repositoryEntity)
+}
+
+// GetRepoById
+func GetRepoById(ctx context.Context, id int64) (*Repo, error) {
+	repositoryEntity := new(entity.Repository)
+	if err := orm.
+		GetBunInstance().
+		NewSelect().
+		Model(repositoryEntity).
+		Relation("Domain").
+		Where("? = ?", bun.Ident("repository.id"), id).
+		Where("? IS NULL", bun.Ident("repository.removed_at")).
 		Scan(ctx); err != nil {
 		return nil, err
 	}
 
+	return repoFrom(ctx, repositoryEntity)
+}
+
+// repoFrom Opens the git repository of an entity loaded with its domain.
+func repoFrom(ctx context.Context, repositoryEntity *entity.Repository) (*Repo, error) {
+	domainAddress := repositoryEntity.Domain.Address
+	repoAddress := repositoryEntity.Address
+
 	path, err := getPath(domainAddress, repoAddress)
 	if err != nil {
 		return nil, err
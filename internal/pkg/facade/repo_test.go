@@ -44,11 +44,71 @@ func TestRepo(t *testing.T) {
 				}
 			})
 
+			t.Run("create with a taken address", func(t *testing.T) {
+				if _, err := CreateRepoByAddress(ctx, newInput.domain, newInput.repo); err != ErrRepositoryAddressTaken {
+					t.Errorf("expected error: %v, got: %v", ErrRepositoryAddressTaken, err)
+				}
+
+				if _, err := CreateRepoByAddress(ctx, newInput.domain, "../"+newInput.repo); err != ErrInvalidRepositoryAddress {
+					t.Errorf("expected error: %v, got: %v", ErrInvalidRepositoryAddress, err)
+				}
+
+				if _, err := CreateRepoByAddress(ctx, createTestAccount(t, ctx).GetDomain().Address, newInput.repo); err != nil {
+					t.Errorf("expected the address to be free within another domain, got error: %s", err.Error())
+				}
+			})
+
+			t.Run("create with the address of a deleted repository", func(t *testing.T) {
+				address := faker.Internet().Slug()
+
+				if repo, err := CreateRepoByAddress(ctx, newInput.domain, address); err != nil {
+					t.Fatalf("failed to create the repository: %s", err.Error())
+				} else if err := repo.Delete(); err != nil {
+					t.Fatalf("failed to delete the repository, got error: %s", err.Error())
+				}
+
+				if _, err := CreateRepoByAddress(ctx, newInput.domain, address); err != nil {
+					t.Errorf("expected the address to be free after the deletion, got error: %s", err.Error())
+				}
+			})
+
 			t.Run("read", func(t *testing.T) {
 				if _, err := GetRepoByAddress(ctx, newInput.domain, newInput.repo); err != nil {
 					t.Errorf("got an unexpected error: %s", err.Error())
 				}
 			})
+
+			t.Run("read by id", func(t *testing.T) {
+				if repo, err := GetRepoByAddress(ctx, newInput.domain, newInput.repo); err != nil {
+					t.Errorf("got an unexpected error: %s", err.Error())
+				} else {
+					if repo, err := GetRepoById(ctx, repo.GetID()); err != nil {
+						t.Errorf("got an unexpected error: %s", err.Error())
+					} else if repo.GetDomainAddress() != newInput.domain {
+						t.Errorf("expected domain: %s, got: %s", newInput.domain, repo.GetDomainAddress())
+					}
+				}
+			})
+
+			t.Run("tree of an empty repository", func(t *testing.T) {
+				if repo, err := GetRepoByAddress(ctx, newInput.domain, newInput.repo); err != nil {
+					t.Errorf("got an unexpected error: %s", err.Error())
+				} else {
+					if _, err := repo.GetTree("HEAD", ""); err != ErrRevisionNotFound {
+						t.Errorf("expected error: %v, got: %v", ErrRevisionNotFound, err)
+					}
+				}
+			})
+
+			t.Run("log of an empty repository", func(t *testing.T) {
+				if repo, err := GetRepoByAddress(ctx, newInput.domain, newInput.repo); err != nil {
+					t.Errorf("got an unexpected error: %s", err.Error())
+				} else {
+					if _, err := repo.Log(&LogConfig{Rev: "HEAD", Limit: 10}); err != ErrRevisionNotFound {
+						t.Errorf("expected error: %v, got: %v", ErrRevisionNotFound, err)
+					}
+				}
+			})
 		}
 	})
 }
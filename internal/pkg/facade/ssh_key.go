@@ -0,0 +1,180 @@
+/*
+ * Copyright 2021 Meraj Sahebdar
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package facade
+
+import (
+	"context"
+	"crypto/rsa"
+	"errors"
+	"time"
+
+	"github.com/uptrace/bun"
+	"github.com/volatiletech/null/v8"
+	gossh "golang.org/x/crypto/ssh"
+	"bitban.io/server/internal/pkg/dto"
+	"bitban.io/server/internal/pkg/fault"
+	"bitban.io/server/internal/pkg/orm"
+	"bitban.io/server/internal/pkg/orm/entity"
+)
+
+// minRsaKeyBits
+const minRsaKeyBits = 2048
+
+var (
+	// ErrMalformedSshKey
+	ErrMalformedSshKey = errors.New("the public key is malformed")
+
+	// ErrWeakSshKey
+	ErrWeakSshKey = errors.New("the public key is too weak")
+)
+
+// sshKeyInputError Wraps the error as a user input error of the key field.
+func sshKeyInputError(tag string, err error) error {
+	errUserInput := fault.UserInputErrorFrom(err)
+	errUserInput.AddError("AddSshKeyInput.key", tag, err.Error())
+
+	return errUserInput
+}
+
+// checkSshKeyStrength
+//
+// Errors:
+//   - facade.ErrWeakSshKey if the key is using a deprecated algorithm or a short modulus
+func checkSshKeyStrength(key gossh.PublicKey) error {
+	switch key.Type() {
+	case gossh.KeyAlgoDSA:
+		return ErrWeakSshKey
+	case gossh.KeyAlgoRSA:
+		if cryptoKey, ok := key.(gossh.CryptoPublicKey); !ok {
+			return ErrMalformedSshKey
+		} else if rsaKey, ok := cryptoKey.CryptoPublicKey().(*rsa.PublicKey); !ok {
+			return ErrMalformedSshKey
+		} else if rsaKey.N.BitLen() < minRsaKeyBits {
+			return ErrWeakSshKey
+		}
+	}
+
+	return nil
+}
+
+// AddSshKey
+//
+// Errors:
+//   - fault.UserInputError if the key is malformed, weak, or already registered
+func (f *Account) AddSshKey(input dto.AddSshKeyInput) (*entity.SshKey, error) {
+	key, _, _, _, err := gossh.ParseAuthorizedKey([]byte(input.Key))
+	if err != nil {
+		return nil, sshKeyInputError("sshkey", ErrMalformedSshKey)
+	}
+
+	if err := checkSshKeyStrength(key); err != nil {
+		return nil, sshKeyInputError("sshkey", err)
+	}
+
+	sshKey := &entity.SshKey{
+		Title:       input.Title,
+		Type:        key.Type(),
+		Fingerprint: gossh.FingerprintSHA256(key),
+		Content:     string(gossh.MarshalAuthorizedKey(key)),
+		UserID:      null.Int64From(f.user.DomainID),
+	}
+
+	if count, err := orm.GetBunInstance().
+		NewSelect().
+		Model((*entity.SshKey)(nil)).
+		Where("? = ?", bun.Ident("ssh_key.fingerprint"), sshKey.Fingerprint).
+		Where("? IS NULL", bun.Ident("ssh_key.removed_at")).
+		Count(f.ctx); err != nil {
+		return nil, err
+	} else if count > 0 {
+		return nil, sshKeyInputError("notexistsin", errors.New("key must be unique"))
+	}
+
+	if _, err := orm.GetBunInstance().
+		NewInsert().
+		Model(sshKey).
+		Column("title", "type", "fingerprint", "content", "user_id").
+		Returning("id", "created_at", "updated_at").
+		Exec(f.ctx); fault.IsPqUniqueViolationError(err) {
+		return nil, sshKeyInputError("notexistsin", errors.New("key must be unique"))
+	} else if err != nil {
+		return nil, err
+	}
+
+	return sshKey, nil
+}
+
+// RemoveSshKey
+//
+// Errors:
+//   - fault.ErrResourceNotFound if the account does not own such a key
+func (f *Account) RemoveSshKey(id int64) (*entity.SshKey, error) {
+	sshKey := new(entity.SshKey)
+	if err := orm.GetBunInstance().
+		NewSelect().
+		Model(sshKey).
+		Where("? = ?", bun.Ident("ssh_key.id"), id).
+		Where("? = ?", bun.Ident("ssh_key.user_id"), f.user.DomainID).
+		Where("? IS NULL", bun.Ident("ssh_key.removed_at")).
+		Limit(1).
+		Scan(f.ctx); err != nil {
+		return nil, err
+	}
+
+	sshKey.RemovedAt = null.TimeFrom(time.Now().In(time.UTC))
+	if _, err := orm.GetBunInstance().
+		NewUpdate().
+		Model(sshKey).
+		Column("removed_at").
+		WherePK().
+		Exec(f.ctx); err != nil {
+		return nil, err
+	}
+
+	return sshKey, nil
+}
+
+// GetSshKeys
+func (f *Account) GetSshKeys() ([]*entity.SshKey, error) {
+	var sshKeys []*entity.SshKey
+	if err := orm.GetBunInstance().
+		NewSelect().
+		Model(&sshKeys).
+		Where("? = ?", bun.Ident("ssh_key.user_id"), f.user.DomainID).
+		Where("? IS NULL", bun.Ident("ssh_key.removed_at")).
+		Order("ssh_key.id").
+		Scan(f.ctx); err != nil {
+		return nil, err
+	}
+
+	return sshKeys, nil
+}
+
+// TouchSshKey Records the current time as the last use of the public key.
+func TouchSshKey(ctx context.Context, fingerprint string) error {
+	if _, err := orm.GetBunInstance().
+		NewUpdate().
+		Model((*entity.SshKey)(nil)).
+		Set("? = ?", bun.Ident("last_used_at"), time.Now().In(time.UTC)).
+		Where("? = ?", bun.Ident("ssh_key.fingerprint"), fingerprint).
+		Where("? IS NULL", bun.Ident("ssh_key.removed_at")).
+		Exec(ctx); err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,113 @@
+/*
+ * Copyright 2021 Meraj Sahebdar
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package facade
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"testing"
+
+	gossh "golang.org/x/crypto/ssh"
+	"bitban.io/server/internal/pkg/dto"
+	"bitban.io/server/internal/pkg/fault"
+	"syreclabs.com/go/faker"
+)
+
+// marshalTestSshKey
+func marshalTestSshKey(t *testing.T, pub interface{}) string {
+	if key, err := gossh.NewPublicKey(pub); err != nil {
+		t.Fatalf("failed to marshal the public key, got error: %s", err.Error())
+		return ""
+	} else {
+		return string(gossh.MarshalAuthorizedKey(key))
+	}
+}
+
+func TestSshKey(t *testing.T) {
+	t.Run("ssh-key", func(t *testing.T) {
+		ctx := context.Background()
+
+		account := getTestAccount(t, ctx)
+
+		edPub, _, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			t.Fatalf("failed to generate ed25519 key, got error: %s", err.Error())
+		}
+
+		rsaPriv, err := rsa.GenerateKey(rand.Reader, 1024)
+		if err != nil {
+			t.Fatalf("failed to generate rsa key, got error: %s", err.Error())
+		}
+
+		validKey := marshalTestSshKey(t, edPub)
+		weakKey := marshalTestSshKey(t, &rsaPriv.PublicKey)
+
+		t.Run("add-valid", func(t *testing.T) {
+			if sshKey, err := account.AddSshKey(dto.AddSshKeyInput{
+				Title: faker.Lorem().Word(),
+				Key:   validKey,
+			}); err != nil {
+				t.Errorf("failed to add the key, got error: %s", err.Error())
+			} else {
+				t.Run("authenticate", func(t *testing.T) {
+					if _, err := GetAccountBySshKey(ctx, sshKey.Fingerprint); err != nil {
+						t.Errorf("failed to authenticate by the key, got error: %s", err.Error())
+					}
+				})
+
+				t.Run("add-duplicate", func(t *testing.T) {
+					if _, err := account.AddSshKey(dto.AddSshKeyInput{
+						Title: faker.Lorem().Word(),
+						Key:   validKey,
+					}); !fault.IsUserInputError(err) {
+						t.Errorf("expected a user input error for a duplicate key, got: %v", err)
+					}
+				})
+
+				t.Run("remove", func(t *testing.T) {
+					if _, err := account.RemoveSshKey(sshKey.ID); err != nil {
+						t.Errorf("failed to remove the key, got error: %s", err.Error())
+					}
+
+					if _, err := GetAccountBySshKey(ctx, sshKey.Fingerprint); !fault.IsUnauthenticatedError(err) {
+						t.Errorf("expected the removed key to be rejected, got: %v", err)
+					}
+				})
+			}
+		})
+
+		t.Run("add-weak", func(t *testing.T) {
+			if _, err := account.AddSshKey(dto.AddSshKeyInput{
+				Title: faker.Lorem().Word(),
+				Key:   weakKey,
+			}); !fault.IsUserInputError(err) {
+				t.Errorf("expected a user input error for a weak key, got: %v", err)
+			}
+		})
+
+		t.Run("add-malformed", func(t *testing.T) {
+			if _, err := account.AddSshKey(dto.AddSshKeyInput{
+				Title: faker.Lorem().Word(),
+				Key:   "ssh-ed25519 invalid",
+			}); !fault.IsUserInputError(err) {
+				t.Errorf("expected a user input error for a malformed key, got: %v", err)
+			}
+		})
+	})
+}
@@ -0,0 +1,484 @@
+/*
+ * Copyright 2021 Meraj Sahebdar
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package facade
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/uptrace/bun"
+	"github.com/volatiletech/null/v8"
+	"bitban.io/server/internal/pkg/auth"
+	"bitban.io/server/internal/pkg/fault"
+	"bitban.io/server/internal/pkg/orm"
+	"bitban.io/server/internal/pkg/orm/entity"
+)
+
+var (
+	// ErrTeamNameTaken
+	ErrTeamNameTaken = errors.New("the organization already has a team with the name")
+
+	// ErrNotOrganizationMember
+	ErrNotOrganizationMember = errors.New("the user is not a member of the organization")
+
+	// ErrTeamMemberExists
+	ErrTeamMemberExists = errors.New("the user is already a member of the team")
+
+	// ErrRepositoryGrantedToTeams
+	ErrRepositoryGrantedToTeams = errors.New("the repository is granted to teams of its organization, which have to be revoked first")
+
+	// ErrRepositoryNotInOrganization
+	ErrRepositoryNotInOrganization = errors.New("the repository does not belong to the organization")
+)
+
+// Team
+type Team struct {
+	ctx          context.Context
+	team         *entity.Team
+	organization *Organization
+}
+
+// TeamRepository
+type TeamRepository struct {
+	Repo *Repo
+	Role string
+}
+
+// GetEntity
+func (f *Team) GetEntity() *entity.Team {
+	return f.team
+}
+
+// GetID
+func (f *Team) GetID() int64 {
+	return f.team.ID
+}
+
+// GetOrganization
+func (f *Team) GetOrganization() *Organization {
+	return f.organization
+}
+
+// teamSubject Returns the casbin subject of the team, which its members are
+// grouped into within the domain of the organization.
+func teamSubject(teamID int64) string {
+	return fmt.Sprintf("/teams/%d", teamID)
+}
+
+// isTeamSubject
+func isTeamSubject(sub string) bool {
+	return strings.HasPrefix(sub, "/teams/")
+}
+
+// checkTeamName
+//
+// Errors:
+//   - facade.ErrTeamNameTaken if another team of the organization has the name
+func (f *Organization) checkTeamName(name string, exceptID int64) error {
+	if count, err := orm.GetBunInstance().
+		NewSelect().
+		Model((*entity.Team)(nil)).
+		Where("? = ?", bun.Ident("team.organization_id"), f.GetID()).
+		Where("? = ?", bun.Ident("team.name"), name).
+		Where("? <> ?", bun.Ident("team.id"), exceptID).
+		Count(f.ctx); err != nil {
+		return err
+	} else if count > 0 {
+		return ErrTeamNameTaken
+	}
+
+	return nil
+}
+
+// GetTeams Returns the teams of the organization ordered by their names.
+func (f *Organization) GetTeams() ([]*Team, error) {
+	var teamEntities []*entity.Team
+	if err := orm.GetBunInstance().
+		NewSelect().
+		Model(&teamEntities).
+		Where("? = ?", bun.Ident("team.organization_id"), f.GetID()).
+		Order("team.name").
+		Scan(f.ctx); err != nil {
+		return nil, err
+	}
+
+	teams := make([]*Team, 0, len(teamEntities))
+	for _, teamEntity := range teamEntities {
+		teamEntity.Organization = f.GetEntity()
+		teams = append(teams, &Team{
+			ctx:          f.ctx,
+			team:         teamEntity,
+			organization: f,
+		})
+	}
+
+	return teams, nil
+}
+
+// CreateTeam
+//
+// ErrorsRef:
+//   - facade.Organization.checkTeamName
+func (f *Organization) CreateTeam(name string, description string) (*Team, error) {
+	if err := f.checkTeamName(name, 0); err != nil {
+		return nil, err
+	}
+
+	team := &entity.Team{
+		Name:           name,
+		Description:    description,
+		OrganizationID: null.Int64From(f.GetID()),
+		Organization:   f.GetEntity(),
+	}
+	if _, err := orm.GetBunInstance().
+		NewInsert().
+		Model(team).
+		Column("name", "description", "organization_id").
+		Returning("id", "created_at", "updated_at").
+		Exec(f.ctx); err != nil {
+		return nil, err
+	}
+
+	return &Team{
+		ctx:          f.ctx,
+		team:         team,
+		organization: f,
+	}, nil
+}
+
+// GetTeamById
+//
+// ErrorsRef:
+//   - facade.GetOrganizationById
+func GetTeamById(ctx context.Context, id int64) (*Team, error) {
+	team := new(entity.Team)
+	if err := orm.GetBunInstance().
+		NewSelect().
+		Model(team).
+		Where("? = ?", bun.Ident("team.id"), id).
+		Scan(ctx); err != nil {
+		return nil, err
+	}
+
+	organization, err := GetOrganizationById(ctx, team.OrganizationID.Int64)
+	if err != nil {
+		return nil, err
+	}
+	team.Organization = organization.GetEntity()
+
+	return &Team{
+		ctx:          ctx,
+		team:         team,
+		organization: organization,
+	}, nil
+}
+
+// Update Changes the name and the description of the team.
+//
+// ErrorsRef:
+//   - facade.Organization.checkTeamName
+func (f *Team) Update(name string, description string) error {
+	if err := f.organization.checkTeamName(name, f.GetID()); err != nil {
+		return err
+	}
+
+	f.team.UpdatedAt = time.Now().In(time.UTC)
+	f.team.Name = name
+	f.team.Description = description
+
+	if _, err := orm.GetBunInstance().
+		NewUpdate().
+		Model(f.team).
+		Column("updated_at", "name", "description").
+		WherePK().
+		Exec(f.ctx); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Delete Removes the team along with its memberships and repository grants.
+func (f *Team) Delete() error {
+	if _, err := orm.GetBunInstance().
+		NewDelete().
+		Model(f.team).
+		WherePK().
+		Exec(f.ctx); err != nil {
+		return err
+	}
+
+	enforcer := auth.GetEnforcerInstance()
+
+	sub := teamSubject(f.GetID())
+	if _, err := enforcer.RemoveFilteredNamedPolicy("p", 0, sub); err != nil {
+		return err
+	}
+
+	if _, err := enforcer.RemoveFilteredNamedGroupingPolicy("g", 1, sub); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// GetMembers Returns the members of the team, the earliest registered users
+// first.
+func (f *Team) GetMembers() ([]*Account, error) {
+	members := []*Account{}
+	for _, rule := range auth.GetEnforcerInstance().GetFilteredNamedGroupingPolicy(
+		"g",
+		1,
+		teamSubject(f.GetID()),
+		f.organization.GetDomain().Address,
+	) {
+		id, err := strconv.ParseInt(strings.TrimPrefix(rule[0], "/users/"), 10, 64)
+		if err != nil {
+			continue
+		}
+
+		if account, err := GetAccountByUserId(f.ctx, id); err == fault.ErrResourceNotFound {
+			continue
+		} else if err != nil {
+			return nil, err
+		} else {
+			members = append(members, account)
+		}
+	}
+
+	sort.Slice(members, func(i, j int) bool {
+		return members[i].GetUser().DomainID < members[j].GetUser().DomainID
+	})
+
+	return members, nil
+}
+
+// hasMember
+func (f *Team) hasMember(account *Account) bool {
+	return len(auth.GetEnforcerInstance().GetFilteredNamedGroupingPolicy(
+		"g",
+		0,
+		fmt.Sprintf("/users/%d", account.GetUser().DomainID),
+		teamSubject(f.GetID()),
+		f.organization.GetDomain().Address,
+	)) > 0
+}
+
+// AddMember Adds a member of the organization to the team.
+//
+// Errors:
+//   - facade.ErrNotOrganizationMember if the account is not a member of the organization
+//   - facade.ErrTeamMemberExists if the account is already a member of the team
+func (f *Team) AddMember(account *Account) error {
+	if _, err := f.organization.GetMember(account); err == fault.ErrResourceNotFound {
+		return ErrNotOrganizationMember
+	} else if err != nil {
+		return err
+	}
+
+	if f.hasMember(account) {
+		return ErrTeamMemberExists
+	}
+
+	if _, err := auth.GetEnforcerInstance().AddNamedGroupingPolicy(
+		"g",
+		fmt.Sprintf("/users/%d", account.GetUser().DomainID),
+		teamSubject(f.GetID()),
+		f.organization.GetDomain().Address,
+	); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// RemoveMember Removes the account from the team.
+//
+// Errors:
+//   - fault.ErrResourceNotFound if the account is not a member of the team
+func (f *Team) RemoveMember(account *Account) error {
+	if !f.hasMember(account) {
+		return fault.ErrResourceNotFound
+	}
+
+	if _, err := auth.GetEnforcerInstance().RemoveNamedGroupingPolicy(
+		"g",
+		fmt.Sprintf("/users/%d", account.GetUser().DomainID),
+		teamSubject(f.GetID()),
+		f.organization.GetDomain().Address,
+	); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// teamRepositoryFrom Returns the repository of a grant along with the role.
+//
+// Errors:
+//   - fault.ErrResourceNotFound if the grant is not of a known role or repository
+func (f *Team) teamRepositoryFrom(rule []string) (*TeamRepository, error) {
+	id, err := strconv.ParseInt(strings.TrimPrefix(rule[2], "/repositories/"), 10, 64)
+	if err != nil {
+		return nil, fault.ErrResourceNotFound
+	}
+
+	role := ""
+	for _, r := range collaboratorRoleActs {
+		if r.act == rule[3] {
+			role = r.role
+		}
+	}
+	if role == "" {
+		return nil, fault.ErrResourceNotFound
+	}
+
+	if repo, err := GetRepoById(f.ctx, id); err != nil {
+		return nil, err
+	} else {
+		return &TeamRepository{
+			Repo: repo,
+			Role: role,
+		}, nil
+	}
+}
+
+// getRepositoryRules
+func (f *Team) getRepositoryRules(fieldValues ...string) [][]string {
+	return auth.GetEnforcerInstance().GetFilteredNamedPolicy(
+		"p",
+		0,
+		append([]string{teamSubject(f.GetID()), f.organization.GetDomain().Address}, fieldValues...)...,
+	)
+}
+
+// GetRepositories Returns the repositories granted to the team along with
+// their roles, ordered by their ids.
+func (f *Team) GetRepositories() ([]*TeamRepository, error) {
+	repos := []*TeamRepository{}
+	for _, rule := range f.getRepositoryRules() {
+		if repo, err := f.teamRepositoryFrom(rule); err == fault.ErrResourceNotFound {
+			continue
+		} else if err != nil {
+			return nil, err
+		} else {
+			repos = append(repos, repo)
+		}
+	}
+
+	sort.Slice(repos, func(i, j int) bool {
+		return repos[i].Repo.GetID() < repos[j].Repo.GetID()
+	})
+
+	return repos, nil
+}
+
+// GrantRepository Grants the role on a repository of the organization to the
+// members of the team, replacing the role granted before.
+//
+// Errors:
+//   - facade.ErrInvalidCollaboratorRole if the role is unknown
+//   - facade.ErrRepositoryNotInOrganization if the repository belongs to another domain
+func (f *Team) GrantRepository(repo *Repo, role string) (*TeamRepository, error) {
+	act := ""
+	for _, r := range collaboratorRoleActs {
+		if r.role == role {
+			act = r.act
+		}
+	}
+	if act == "" {
+		return nil, ErrInvalidCollaboratorRole
+	}
+
+	if repo.GetEntity().DomainID.Int64 != f.organization.GetID() {
+		return nil, ErrRepositoryNotInOrganization
+	}
+
+	enforcer := auth.GetEnforcerInstance()
+
+	obj := fmt.Sprintf("/repositories/%d", repo.GetID())
+	if rules := f.getRepositoryRules(obj); len(rules) > 0 {
+		if _, err := enforcer.RemoveNamedPolicies("p", rules); err != nil {
+			return nil, err
+		}
+	}
+
+	if _, err := enforcer.AddNamedPolicy(
+		"p",
+		teamSubject(f.GetID()),
+		f.organization.GetDomain().Address,
+		obj,
+		act,
+	); err != nil {
+		return nil, err
+	}
+
+	return &TeamRepository{
+		Repo: repo,
+		Role: role,
+	}, nil
+}
+
+// RevokeRepository Revokes the role granted to the team on the repository.
+//
+// Errors:
+//   - fault.ErrResourceNotFound if the repository is not granted to the team
+func (f *Team) RevokeRepository(repo *Repo) (*TeamRepository, error) {
+	rules := f.getRepositoryRules(fmt.Sprintf("/repositories/%d", repo.GetID()))
+	if len(rules) == 0 {
+		return nil, fault.ErrResourceNotFound
+	}
+
+	granted, err := f.teamRepositoryFrom(rules[0])
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := auth.GetEnforcerInstance().RemoveNamedPolicies("p", rules); err != nil {
+		return nil, err
+	}
+
+	return granted, nil
+}
+
+// removeTeamMemberships Removes the account from every team of the
+// organization.
+func (f *Organization) removeTeamMemberships(account *Account) error {
+	enforcer := auth.GetEnforcerInstance()
+
+	dom := f.GetDomain().Address
+	for _, rule := range enforcer.GetFilteredNamedGroupingPolicy(
+		"g",
+		0,
+		fmt.Sprintf("/users/%d", account.GetUser().DomainID),
+	) {
+		if rule[2] != dom || !isTeamSubject(rule[1]) {
+			continue
+		}
+
+		if _, err := enforcer.RemoveNamedGroupingPolicy("g", rule); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
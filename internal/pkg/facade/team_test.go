@@ -0,0 +1,142 @@
+/*
+ * Copyright 2021 Meraj Sahebdar
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package facade
+
+import (
+	"context"
+	"testing"
+
+	"bitban.io/server/internal/pkg/fault"
+	"syreclabs.com/go/faker"
+)
+
+func TestTeam(t *testing.T) {
+	t.Run("team", func(t *testing.T) {
+		ctx := context.Background()
+
+		owner := getTestAccount(t, ctx)
+		account := createTestAccount(t, ctx)
+
+		org, err := CreateOrganization(ctx, owner, faker.Company().Name(), faker.Internet().Slug())
+		if err != nil {
+			t.Fatalf("failed to create the organization, got error: %s", err.Error())
+		}
+
+		repo, err := CreateRepoByAddress(ctx, org.GetDomain().Address, faker.Internet().Slug())
+		if err != nil {
+			t.Fatalf("failed to create the repository, got error: %s", err.Error())
+		}
+
+		team, err := org.CreateTeam("developers", "")
+		if err != nil {
+			t.Fatalf("failed to create the team, got error: %s", err.Error())
+		}
+
+		if _, err := org.CreateTeam("developers", ""); err != ErrTeamNameTaken {
+			t.Errorf("expected error: %v, got: %v", ErrTeamNameTaken, err)
+		}
+
+		if err := team.AddMember(account); err != ErrNotOrganizationMember {
+			t.Errorf("expected error: %v, got: %v", ErrNotOrganizationMember, err)
+		}
+
+		if _, err := org.AddMember(account, OrganizationRoleMember); err != nil {
+			t.Fatalf("failed to add the member, got error: %s", err.Error())
+		}
+
+		if err := team.AddMember(account); err != nil {
+			t.Fatalf("failed to add the team member, got error: %s", err.Error())
+		}
+
+		if err := team.AddMember(account); err != ErrTeamMemberExists {
+			t.Errorf("expected error: %v, got: %v", ErrTeamMemberExists, err)
+		}
+
+		if err := CheckRepoPermission(account, repo, "write"); err != fault.ErrForbidden {
+			t.Errorf("expected error: %v, got: %v", fault.ErrForbidden, err)
+		}
+
+		if _, err := team.GrantRepository(repo, CollaboratorRoleWrite); err != nil {
+			t.Fatalf("failed to grant the repository, got error: %s", err.Error())
+		}
+
+		if err := CheckRepoPermission(account, repo, "write"); err != nil {
+			t.Errorf("expected the team member to write the repository, got error: %s", err.Error())
+		}
+
+		if err := CheckRepoPermission(account, repo, "admin"); err != fault.ErrForbidden {
+			t.Errorf("expected error: %v, got: %v", fault.ErrForbidden, err)
+		}
+
+		if repos, err := team.GetRepositories(); err != nil {
+			t.Errorf("failed to get the repositories, got error: %s", err.Error())
+		} else if len(repos) != 1 || repos[0].Role != CollaboratorRoleWrite {
+			t.Errorf("expected a single write repository, got: %v", repos)
+		}
+
+		if _, err := team.GrantRepository(repo, CollaboratorRoleAdmin); err != nil {
+			t.Fatalf("failed to replace the grant, got error: %s", err.Error())
+		}
+
+		if err := CheckRepoPermission(account, repo, "admin"); err != nil {
+			t.Errorf("expected the team member to administer the repository, got error: %s", err.Error())
+		}
+
+		other, err := CreateRepoByAddress(ctx, owner.GetDomain().Address, faker.Internet().Slug())
+		if err != nil {
+			t.Fatalf("failed to create the repository, got error: %s", err.Error())
+		}
+
+		if _, err := team.GrantRepository(other, CollaboratorRoleRead); err != ErrRepositoryNotInOrganization {
+			t.Errorf("expected error: %v, got: %v", ErrRepositoryNotInOrganization, err)
+		}
+
+		if _, err := org.RemoveMember(account); err != nil {
+			t.Fatalf("failed to remove the member, got error: %s", err.Error())
+		}
+
+		if members, err := team.GetMembers(); err != nil {
+			t.Errorf("failed to get the team members, got error: %s", err.Error())
+		} else if len(members) != 0 {
+			t.Errorf("expected the removed member to leave the team, got: %v", members)
+		}
+
+		if err := CheckRepoPermission(account, repo, "read"); err != fault.ErrForbidden {
+			t.Errorf("expected error: %v, got: %v", fault.ErrForbidden, err)
+		}
+
+		if err := repo.Transfer(owner.GetDomain()); err != ErrRepositoryGrantedToTeams {
+			t.Errorf("expected error: %v, got: %v", ErrRepositoryGrantedToTeams, err)
+		}
+
+		if _, err := team.RevokeRepository(repo); err != nil {
+			t.Errorf("failed to revoke the repository, got error: %s", err.Error())
+		}
+
+		if err := repo.Transfer(owner.GetDomain()); err != nil {
+			t.Errorf("expected the repository to be transferable without team grants, got error: %v", err)
+		}
+
+		if err := team.Delete(); err != nil {
+			t.Errorf("failed to delete the team, got error: %s", err.Error())
+		}
+
+		if _, err := GetTeamById(ctx, team.GetID()); err != fault.ErrResourceNotFound {
+			t.Errorf("expected error: %v, got: %v", fault.ErrResourceNotFound, err)
+		}
+	})
+}
@@ -0,0 +1,378 @@
+/*
+ * Copyright 2021 Meraj Sahebdar
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package facade
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-billy/v5"
+	"github.com/go-git/go-billy/v5/util"
+	"github.com/uptrace/bun"
+	"github.com/volatiletech/null/v8"
+	"go.uber.org/zap"
+	"bitban.io/server/internal/cfg"
+	"bitban.io/server/internal/pkg/auth"
+	"bitban.io/server/internal/pkg/orm"
+	"bitban.io/server/internal/pkg/orm/entity"
+)
+
+var (
+	// ErrRetentionExpired
+	ErrRetentionExpired = errors.New("the repository was deleted before the retention window")
+
+	// ErrRepositoryAddressTaken
+	ErrRepositoryAddressTaken = errors.New("another repository with the same address already exists")
+
+	// ErrInvalidRepositoryAddress
+	ErrInvalidRepositoryAddress = errors.New("the address may only contain letters, digits, '-', '_' and dots between them")
+)
+
+// repoAddressRegexp Keeps the addresses to a single element of the storage
+// paths, which neither climbs up nor is hidden.
+var repoAddressRegexp = regexp.MustCompile(`^[A-Za-z0-9_-]+(\.[A-Za-z0-9_-]+)*$`)
+
+// GetTrashRetention Returns how long the deleted repositories are kept in trash.
+func GetTrashRetention() time.Duration {
+	return time.Duration(cfg.Cog.Git.Trash.Retention) * time.Minute
+}
+
+// getStoragePath Returns the storage path of the elements without requiring
+// it to exist, in the same form as `getPath`.
+func getStoragePath(elem ...string) (string, error) {
+	if cfg.Cog.Git.Storage == cfg.GitStorageFs {
+		if root, err := cfg.GetVarPath(); err != nil {
+			return "", err
+		} else {
+			return root + "/" + strings.Join(elem, "/"), nil
+		}
+	}
+
+	return "/" + strings.Join(elem, "/"), nil
+}
+
+// checkRepoAddress
+//
+// Errors:
+//   - facade.ErrInvalidRepositoryAddress if the address is not a valid one
+func checkRepoAddress(address string) error {
+	if len(address) > 250 || !repoAddressRegexp.MatchString(address) {
+		return ErrInvalidRepositoryAddress
+	}
+
+	return nil
+}
+
+// getTrashPath Returns the path the storage of a deleted repository is kept in.
+func getTrashPath(id int64) (string, error) {
+	return getStoragePath("trash", strconv.FormatInt(id, 10))
+}
+
+// copyDir Copies a directory of the filesystem recursively.
+func copyDir(fs billy.Filesystem, from string, to string) error {
+	if err := fs.MkdirAll(to, 0755); err != nil {
+		return err
+	}
+
+	infos, err := fs.ReadDir(from)
+	if err != nil {
+		return err
+	}
+
+	for _, info := range infos {
+		src := fs.Join(from, info.Name())
+		dst := fs.Join(to, info.Name())
+
+		if info.IsDir() {
+			if err := copyDir(fs, src, dst); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		if byt, err := util.ReadFile(fs, src); err != nil {
+			return err
+		} else if err := util.WriteFile(fs, dst, byt, info.Mode()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// moveStorage Moves the storage of a repository to another path.
+func moveStorage(from string, to string) error {
+	if cfg.Cog.Git.Storage == cfg.GitStorageFs {
+		if err := os.MkdirAll(filepath.Dir(to), 0755); err != nil {
+			return err
+		}
+
+		return os.Rename(from, to)
+	}
+
+	// The memory filesystem renames every path sharing the prefix, which would
+	// take the sibling repositories along, so the storage is copied instead.
+	fs, err := getFs("/")
+	if err != nil {
+		return err
+	}
+
+	if err := copyDir(fs, from, to); err != nil {
+		return err
+	}
+
+	return util.RemoveAll(fs, from)
+}
+
+// removeStorage Removes the storage of a repository.
+func removeStorage(path string) error {
+	if cfg.Cog.Git.Storage == cfg.GitStorageFs {
+		return os.RemoveAll(path)
+	}
+
+	if fs, err := getFs("/"); err != nil {
+		return err
+	} else {
+		return util.RemoveAll(fs, path)
+	}
+}
+
+// Delete Marks the repository as removed and moves its storage to trash, from
+// where it can be restored within the retention window.
+func (f *Repo) Delete() (err error) {
+	trashPath, err := getTrashPath(f.GetID())
+	if err != nil {
+		return err
+	}
+
+	var tx bun.Tx
+	if tx, err = orm.GetBunInstance().BeginTx(f.ctx, nil); err != nil {
+		return err
+	}
+
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	now := time.Now()
+
+	f.repositoryEntity.UpdatedAt = now
+	f.repositoryEntity.RemovedAt = null.TimeFrom(now)
+	if _, err = tx.NewUpdate().
+		Model(f.repositoryEntity).
+		Column("updated_at", "removed_at").
+		WherePK().
+		Exec(f.ctx); err != nil {
+		return err
+	}
+
+	if err = moveStorage(f.path, trashPath); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		moveStorage(trashPath, f.path)
+		return err
+	}
+
+	return nil
+}
+
+// getTrashedRepoEntity Returns a deleted repository loaded with its domain.
+func getTrashedRepoEntity(ctx context.Context, id int64) (*entity.Repository, error) {
+	repositoryEntity := new(entity.Repository)
+	if err := orm.
+		GetBunInstance().
+		NewSelect().
+		Model(repositoryEntity).
+		Relation("Domain").
+		Where("? = ?", bun.Ident("repository.id"), id).
+		Where("? IS NOT NULL", bun.Ident("repository.removed_at")).
+		Scan(ctx); err != nil {
+		return nil, err
+	}
+
+	return repositoryEntity, nil
+}
+
+// GetTrashedRepoDomainAddress Returns the domain address of a deleted
+// repository, to authorize restoring it.
+//
+// ErrorsRef:
+//   - facade.getTrashedRepoEntity
+func GetTrashedRepoDomainAddress(ctx context.Context, id int64) (string, error) {
+	if repositoryEntity, err := getTrashedRepoEntity(ctx, id); err != nil {
+		return "", err
+	} else {
+		return repositoryEntity.Domain.Address, nil
+	}
+}
+
+// RestoreRepoById Restores a deleted repository along with its storage.
+//
+// Errors:
+//   - fault.ErrResourceNotFound if there is no such deleted repository
+//   - facade.ErrRetentionExpired if the retention window is over
+//   - facade.ErrRepositoryAddressTaken if the address is used by another repository
+func RestoreRepoById(ctx context.Context, id int64) (repo *Repo, err error) {
+	repositoryEntity, err := getTrashedRepoEntity(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if time.Since(repositoryEntity.RemovedAt.Time) > GetTrashRetention() {
+		return nil, ErrRetentionExpired
+	}
+
+	if count, err := orm.GetBunInstance().
+		NewSelect().
+		Model((*entity.Repository)(nil)).
+		Where("? = ?", bun.Ident("repository.domain_id"), repositoryEntity.DomainID).
+		Where("? = ?", bun.Ident("repository.address"), repositoryEntity.Address).
+		Where("? IS NULL", bun.Ident("repository.removed_at")).
+		Count(ctx); err != nil {
+		return nil, err
+	} else if count > 0 {
+		return nil, ErrRepositoryAddressTaken
+	}
+
+	trashPath, err := getTrashPath(id)
+	if err != nil {
+		return nil, err
+	}
+
+	path, err := getStoragePath("repos", repositoryEntity.Domain.Address, repositoryEntity.Address)
+	if err != nil {
+		return nil, err
+	}
+
+	var tx bun.Tx
+	if tx, err = orm.GetBunInstance().BeginTx(ctx, nil); err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if err != nil && repo == nil {
+			tx.Rollback()
+		}
+	}()
+
+	repositoryEntity.UpdatedAt = time.Now()
+	repositoryEntity.RemovedAt = null.Time{}
+	if _, err = tx.NewUpdate().
+		Model(repositoryEntity).
+		Column("updated_at", "removed_at").
+		WherePK().
+		Exec(ctx); err != nil {
+		return nil, err
+	}
+
+	if err = moveStorage(trashPath, path); err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		moveStorage(path, trashPath)
+		return nil, err
+	}
+
+	return repoFrom(ctx, repositoryEntity)
+}
+
+// purgeRepo Removes a deleted repository along with its storage, lfs objects
+// and policies.
+func purgeRepo(ctx context.Context, repositoryEntity *entity.Repository) error {
+	if trashPath, err := getTrashPath(repositoryEntity.ID); err != nil {
+		return err
+	} else if err := removeStorage(trashPath); err != nil {
+		return err
+	}
+
+	if lfsPath, err := getLfsPath(repositoryEntity.ID); err != nil {
+		return err
+	} else if err := removeStorage(lfsPath); err != nil {
+		return err
+	}
+
+	if _, err := orm.GetBunInstance().
+		NewDelete().
+		Model(repositoryEntity).
+		WherePK().
+		Exec(ctx); err != nil {
+		return err
+	}
+
+	obj := fmt.Sprintf("/repositories/%d", repositoryEntity.ID)
+	for _, o := range []string{obj, obj + "/*"} {
+		if _, err := auth.GetEnforcerInstance().RemoveFilteredNamedPolicy("p", 2, o); err != nil {
+			return err
+		}
+	}
+
+	for _, r := range collaboratorRoleActs {
+		if _, err := auth.GetEnforcerInstance().RemoveFilteredNamedGroupingPolicy(
+			"g",
+			1,
+			collaboratorRoleSubject(repositoryEntity.ID, r.role),
+		); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// PurgeTrashedRepos Removes the repositories deleted before the retention
+// window for good, and returns the number of the purged ones.
+func PurgeTrashedRepos(ctx context.Context) (int, error) {
+	var repositoryEntities []*entity.Repository
+	if err := orm.GetBunInstance().
+		NewSelect().
+		Model(&repositoryEntities).
+		Where("? < ?", bun.Ident("repository.removed_at"), time.Now().Add(-GetTrashRetention())).
+		Order("repository.id").
+		Scan(ctx); err != nil {
+		return 0, err
+	}
+
+	purged := 0
+	for _, repositoryEntity := range repositoryEntities {
+		if err := purgeRepo(ctx, repositoryEntity); err != nil {
+			cfg.Log.Error(
+				"failed to purge a repository",
+				zap.Int64("repositoryId", repositoryEntity.ID),
+				zap.Error(err),
+			)
+
+			continue
+		}
+
+		purged++
+	}
+
+	return purged, nil
+}
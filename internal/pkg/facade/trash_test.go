@@ -0,0 +1,112 @@
+/*
+ * Copyright 2021 Meraj Sahebdar
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package facade
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-git/go-billy/v5/util"
+	"bitban.io/server/internal/cfg"
+	"bitban.io/server/internal/pkg/fault"
+)
+
+func TestCheckRepoAddress(t *testing.T) {
+	for address, expected := range map[string]error{
+		"server":         nil,
+		"bitban-server":  nil,
+		"bitban_server":  nil,
+		"server.go":      nil,
+		"v1.2.3":         nil,
+		"":               ErrInvalidRepositoryAddress,
+		".":              ErrInvalidRepositoryAddress,
+		"..":             ErrInvalidRepositoryAddress,
+		".hidden":        ErrInvalidRepositoryAddress,
+		"server.":        ErrInvalidRepositoryAddress,
+		"a..b":           ErrInvalidRepositoryAddress,
+		"../../etc":      ErrInvalidRepositoryAddress,
+		"domain/server":  ErrInvalidRepositoryAddress,
+		"domain\\server": ErrInvalidRepositoryAddress,
+		"bitban server":  ErrInvalidRepositoryAddress,
+	} {
+		if err := checkRepoAddress(address); err != expected {
+			t.Errorf("expected checkRepoAddress(%q) to be %v, got: %v", address, expected, err)
+		}
+	}
+}
+
+func TestMoveStorage(t *testing.T) {
+	if cfg.Cog.Git.Storage != cfg.GitStorageMem {
+		t.Skip("the test runs on the memory storage")
+	}
+
+	fs, err := getFs("/")
+	if err != nil {
+		t.Fatalf("failed to get the filesystem, got error: %s", err.Error())
+	}
+
+	for _, path := range []string{"/repos/trash/a/HEAD", "/repos/trash/a/objects/info/packs", "/repos/trash/ab/HEAD"} {
+		if err := util.WriteFile(fs, path, []byte(path), 0644); err != nil {
+			t.Fatalf("failed to write %s, got error: %s", path, err.Error())
+		}
+	}
+
+	if err := moveStorage("/repos/trash/a", "/trash/a"); err != nil {
+		t.Fatalf("failed to move the storage, got error: %s", err.Error())
+	}
+
+	for path, exists := range map[string]bool{
+		"/repos/trash/a/HEAD":         false,
+		"/trash/a/HEAD":               true,
+		"/trash/a/objects/info/packs": true,
+		"/repos/trash/ab/HEAD":        true,
+	} {
+		if _, err := fs.Stat(path); (err == nil) != exists {
+			t.Errorf("expected %s to exist: %t, got error: %v", path, exists, err)
+		}
+	}
+}
+
+func TestTrash(t *testing.T) {
+	t.Run("trash", func(t *testing.T) {
+		ctx := context.Background()
+
+		repo := createTestRepo(t, ctx)
+
+		if err := repo.Delete(); err != nil {
+			t.Fatalf("failed to delete the repository, got error: %s", err.Error())
+		}
+
+		if _, err := GetRepoById(ctx, repo.GetID()); err != fault.ErrResourceNotFound {
+			t.Errorf("expected error: %v, got: %v", fault.ErrResourceNotFound, err)
+		}
+
+		if _, err := GetRepoByAddress(ctx, repo.GetDomainAddress(), repo.GetEntity().Address); err != fault.ErrResourceNotFound {
+			t.Errorf("expected error: %v, got: %v", fault.ErrResourceNotFound, err)
+		}
+
+		if restored, err := RestoreRepoById(ctx, repo.GetID()); err != nil {
+			t.Errorf("got an unexpected error: %s", err.Error())
+		} else if restored.GetEntity().RemovedAt.Valid {
+			t.Errorf("expected the repository not to be removed")
+		}
+
+		if _, err := RestoreRepoById(ctx, repo.GetID()); err != fault.ErrResourceNotFound {
+			t.Errorf("expected error: %v, got: %v", fault.ErrResourceNotFound, err)
+		}
+	})
+}
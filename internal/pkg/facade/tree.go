@@ -0,0 +1,313 @@
+/*
+ * Copyright 2021 Meraj Sahebdar
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package facade
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/utils/binary"
+	"bitban.io/server/internal/cfg"
+	"bitban.io/server/internal/pkg/exec"
+)
+
+// Tree Entry Types
+const (
+	TreeEntryBlob   = "blob"
+	TreeEntryTree   = "tree"
+	TreeEntryCommit = "commit"
+)
+
+// MaxBlobSize The largest file in bytes that its content is returned.
+const MaxBlobSize = 1 << 20
+
+var (
+	// ErrPathNotFound
+	ErrPathNotFound = errors.New("the requested path does not exist")
+)
+
+// TreeEntry
+type TreeEntry struct {
+	Name string
+	Path string
+	Mode filemode.FileMode
+	Type string
+	Size *int64
+}
+
+// Blob
+type Blob struct {
+	Name       string
+	Path       string
+	Size       int64
+	Content    []byte
+	IsBinary   bool
+	IsTooLarge bool
+}
+
+// treeEntryTypeOf
+func treeEntryTypeOf(mode filemode.FileMode) string {
+	switch mode {
+	case filemode.Dir:
+		return TreeEntryTree
+	case filemode.Submodule:
+		return TreeEntryCommit
+	}
+
+	return TreeEntryBlob
+}
+
+// cleanPath Normalizes a path received from clients to a tree path.
+func cleanPath(path string) string {
+	return strings.Trim(path, "/")
+}
+
+// joinPath
+func joinPath(dir string, name string) string {
+	if dir == "" {
+		return name
+	}
+
+	return dir + "/" + name
+}
+
+// baseName
+func baseName(path string) string {
+	return path[strings.LastIndex(path, "/")+1:]
+}
+
+// GetTree Returns the entries of the directory at the path within the revision.
+//
+// Errors:
+//   - facade.ErrRevisionNotFound if the revision does not point to any commit
+//   - facade.ErrPathNotFound if the path is not a directory in the revision
+func (f *Repo) GetTree(rev string, path string) ([]*TreeEntry, error) {
+	path = cleanPath(path)
+
+	if cfg.IsGoBackend() {
+		commit, err := f.resolveCommit(rev)
+		if err != nil {
+			return nil, err
+		}
+
+		tree, err := commit.Tree()
+		if err != nil {
+			return nil, err
+		}
+
+		if path != "" {
+			if tree, err = tree.Tree(path); err != nil {
+				return nil, ErrPathNotFound
+			}
+		}
+
+		entries := make([]*TreeEntry, 0, len(tree.Entries))
+		for _, e := range tree.Entries {
+			entry := &TreeEntry{
+				Name: e.Name,
+				Path: joinPath(path, e.Name),
+				Mode: e.Mode,
+				Type: treeEntryTypeOf(e.Mode),
+			}
+
+			if entry.Type == TreeEntryBlob {
+				if blob, err := f.repositoryInstance.BlobObject(e.Hash); err != nil {
+					return nil, err
+				} else {
+					entry.Size = &blob.Size
+				}
+			}
+
+			entries = append(entries, entry)
+		}
+
+		return entries, nil
+	} else {
+		if err := f.verifyCommit(rev); err != nil {
+			return nil, err
+		}
+
+		out, err := exec.Output(
+			f.path,
+			"git",
+			"ls-tree",
+			"-l",
+			"-z",
+			fmt.Sprintf("%s:%s", rev, path),
+		)
+		if err != nil {
+			return nil, ErrPathNotFound
+		}
+
+		entries := []*TreeEntry{}
+		for _, line := range bytes.Split(out, []byte{0}) {
+			if len(line) == 0 {
+				continue
+			}
+
+			// <mode> SP <type> SP <object> SP+ <size> TAB <name>
+			parts := strings.SplitN(string(line), "\t", 2)
+			if len(parts) != 2 {
+				continue
+			}
+
+			fields := strings.Fields(parts[0])
+			if len(fields) != 4 {
+				continue
+			}
+
+			mode, err := filemode.New(fields[0])
+			if err != nil {
+				return nil, err
+			}
+
+			entry := &TreeEntry{
+				Name: parts[1],
+				Path: joinPath(path, parts[1]),
+				Mode: mode,
+				Type: fields[1],
+			}
+
+			if size, err := strconv.ParseInt(fields[3], 10, 64); err == nil {
+				entry.Size = &size
+			}
+
+			entries = append(entries, entry)
+		}
+
+		return entries, nil
+	}
+}
+
+// GetBlob Returns the file at the path within the revision. The content of
+// the files larger than MaxBlobSize is omitted.
+//
+// Errors:
+//   - facade.ErrRevisionNotFound if the revision does not point to any commit
+//   - facade.ErrPathNotFound if the path is not a file in the revision
+func (f *Repo) GetBlob(rev string, path string) (*Blob, error) {
+	path = cleanPath(path)
+
+	blob := &Blob{
+		Name: baseName(path),
+		Path: path,
+	}
+
+	if cfg.IsGoBackend() {
+		commit, err := f.resolveCommit(rev)
+		if err != nil {
+			return nil, err
+		}
+
+		var file *object.File
+		if file, err = commit.File(path); err != nil {
+			return nil, ErrPathNotFound
+		}
+
+		r, err := file.Reader()
+		if err != nil {
+			return nil, err
+		}
+		defer r.Close()
+
+		blob.Size = file.Size
+		blob.IsTooLarge = file.Size > MaxBlobSize
+
+		if blob.IsTooLarge {
+			if blob.IsBinary, err = binary.IsBinary(r); err != nil {
+				return nil, err
+			}
+
+			return blob, nil
+		}
+
+		if blob.Content, err = io.ReadAll(r); err != nil {
+			return nil, err
+		}
+	} else {
+		if err := f.verifyCommit(rev); err != nil {
+			return nil, err
+		}
+
+		spec := fmt.Sprintf("%s:%s", rev, path)
+
+		if out, err := exec.Output(f.path, "git", "cat-file", "-t", spec); err != nil || strings.TrimSpace(string(out)) != "blob" {
+			return nil, ErrPathNotFound
+		}
+
+		out, err := exec.Output(f.path, "git", "cat-file", "-s", spec)
+		if err != nil {
+			return nil, ErrPathNotFound
+		}
+
+		if blob.Size, err = strconv.ParseInt(strings.TrimSpace(string(out)), 10, 64); err != nil {
+			return nil, err
+		}
+
+		blob.IsTooLarge = blob.Size > MaxBlobSize
+
+		if blob.IsTooLarge {
+			if blob.IsBinary, err = f.isBinaryObject(spec); err != nil {
+				return nil, err
+			}
+
+			return blob, nil
+		}
+
+		if blob.Content, err = exec.Output(f.path, "git", "cat-file", "blob", spec); err != nil {
+			return nil, ErrPathNotFound
+		}
+	}
+
+	isBinary, err := binary.IsBinary(bytes.NewReader(blob.Content))
+	if err != nil {
+		return nil, err
+	}
+
+	blob.IsBinary = isBinary
+
+	return blob, nil
+}
+
+// isBinaryObject Checks whether the blob is binary by the beginning of its
+// content, using git binary.
+func (f *Repo) isBinaryObject(spec string) (bool, error) {
+	cmd, _, stdout, _, err := exec.Create("git", "cat-file", "blob", spec)
+	if err != nil {
+		return false, err
+	}
+
+	cmd.Dir = f.path
+
+	if err := cmd.Start(); err != nil {
+		return false, err
+	}
+
+	// The rest of the content is not needed.
+	defer func() {
+		cmd.Process.Kill()
+		cmd.Wait()
+	}()
+
+	return binary.IsBinary(stdout)
+}
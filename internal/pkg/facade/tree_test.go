@@ -0,0 +1,137 @@
+/*
+ * Copyright 2021 Meraj Sahebdar
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package facade
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestTree(t *testing.T) {
+	ctx := context.Background()
+	repo := createTestRepo(t, ctx)
+
+	commitTestFiles(t, repo, "main", "initial", map[string]string{
+		"README.md":         "hello\n",
+		"docs/guide.md":     "guide\n",
+		"docs/api/index.md": "api\n",
+		"logo.png":          "\x89PNG\x00\x01",
+		"large.txt":         strings.Repeat("a", MaxBlobSize+1),
+	})
+
+	t.Run("root", func(t *testing.T) {
+		entries, err := repo.GetTree("main", "")
+		if err != nil {
+			t.Fatalf("got an unexpected error: %s", err.Error())
+		}
+
+		var got []string
+		for _, entry := range entries {
+			got = append(got, entry.Path+":"+entry.Type)
+		}
+
+		if expected := "README.md:blob docs:tree large.txt:blob logo.png:blob"; strings.Join(got, " ") != expected {
+			t.Errorf("expected entries %s, got: %s", expected, strings.Join(got, " "))
+		}
+
+		if size := entries[0].Size; size == nil || *size != 6 {
+			t.Errorf("expected the size of README.md to be 6, got: %v", size)
+		}
+
+		if entries[1].Size != nil {
+			t.Errorf("expected no size for a directory, got: %d", *entries[1].Size)
+		}
+	})
+
+	t.Run("directory", func(t *testing.T) {
+		entries, err := repo.GetTree("main", "/docs/")
+		if err != nil {
+			t.Fatalf("got an unexpected error: %s", err.Error())
+		}
+
+		if len(entries) != 2 || entries[0].Path != "docs/api" || entries[1].Path != "docs/guide.md" {
+			t.Errorf("expected docs/api and docs/guide.md, got: %v", entries)
+		}
+	})
+
+	t.Run("file path", func(t *testing.T) {
+		if _, err := repo.GetTree("main", "README.md"); err != ErrPathNotFound {
+			t.Errorf("expected error: %v, got: %v", ErrPathNotFound, err)
+		}
+	})
+
+	t.Run("unknown revision", func(t *testing.T) {
+		if _, err := repo.GetTree("unknown", ""); err != ErrRevisionNotFound {
+			t.Errorf("expected error: %v, got: %v", ErrRevisionNotFound, err)
+		}
+	})
+}
+
+func TestBlob(t *testing.T) {
+	ctx := context.Background()
+	repo := createTestRepo(t, ctx)
+
+	commitTestFiles(t, repo, "main", "initial", map[string]string{
+		"docs/guide.md": "guide\n",
+		"logo.png":      "\x89PNG\x00\x01",
+		"large.txt":     strings.Repeat("a", MaxBlobSize+1),
+	})
+
+	t.Run("text", func(t *testing.T) {
+		blob, err := repo.GetBlob("main", "/docs/guide.md")
+		if err != nil {
+			t.Fatalf("got an unexpected error: %s", err.Error())
+		}
+
+		if blob.Name != "guide.md" || blob.Path != "docs/guide.md" || blob.Size != 6 {
+			t.Errorf("unexpected blob: %s %s %d", blob.Name, blob.Path, blob.Size)
+		}
+
+		if string(blob.Content) != "guide\n" || blob.IsBinary || blob.IsTooLarge {
+			t.Errorf("expected the full text content, got: %q", blob.Content)
+		}
+	})
+
+	t.Run("binary", func(t *testing.T) {
+		if blob, err := repo.GetBlob("main", "logo.png"); err != nil {
+			t.Errorf("got an unexpected error: %s", err.Error())
+		} else if !blob.IsBinary || string(blob.Content) != "\x89PNG\x00\x01" {
+			t.Errorf("expected the binary content, got: %q", blob.Content)
+		}
+	})
+
+	t.Run("too large", func(t *testing.T) {
+		if blob, err := repo.GetBlob("main", "large.txt"); err != nil {
+			t.Errorf("got an unexpected error: %s", err.Error())
+		} else if !blob.IsTooLarge || blob.Content != nil || blob.Size != MaxBlobSize+1 || blob.IsBinary {
+			t.Errorf("expected the content to be omitted, got: %d bytes of %d", len(blob.Content), blob.Size)
+		}
+	})
+
+	t.Run("directory path", func(t *testing.T) {
+		if _, err := repo.GetBlob("main", "docs"); err != ErrPathNotFound {
+			t.Errorf("expected error: %v, got: %v", ErrPathNotFound, err)
+		}
+	})
+
+	t.Run("unknown revision", func(t *testing.T) {
+		if _, err := repo.GetBlob("unknown", "logo.png"); err != ErrRevisionNotFound {
+			t.Errorf("expected error: %v, got: %v", ErrRevisionNotFound, err)
+		}
+	})
+}
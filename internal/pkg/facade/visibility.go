@@ -0,0 +1,88 @@
+/*
+ * Copyright 2021 Meraj Sahebdar
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package facade
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"bitban.io/server/internal/pkg/fault"
+	"bitban.io/server/internal/pkg/orm"
+)
+
+// Repository Visibilities
+const (
+	VisibilityPublic  = "public"
+	VisibilityPrivate = "private"
+)
+
+var (
+	// ErrInvalidVisibility
+	ErrInvalidVisibility = errors.New("the visibility must be either public or private")
+)
+
+// IsPublic Reports whether anyone may read the repository.
+func (f *Repo) IsPublic() bool {
+	return f.repositoryEntity.Visibility == VisibilityPublic
+}
+
+// SetVisibility
+//
+// Errors:
+//   - facade.ErrInvalidVisibility if the visibility is not supported
+func (f *Repo) SetVisibility(visibility string) error {
+	if visibility != VisibilityPublic && visibility != VisibilityPrivate {
+		return ErrInvalidVisibility
+	}
+
+	f.repositoryEntity.Visibility = visibility
+	f.repositoryEntity.UpdatedAt = time.Now()
+	if _, err := orm.GetBunInstance().
+		NewUpdate().
+		Model(f.repositoryEntity).
+		Column("updated_at", "visibility").
+		WherePK().
+		Exec(f.ctx); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// CheckRepoPermission Checks whether the account, or an anonymous user if it
+// is nil, is allowed to perform the action on the repository. Anyone may read
+// the public repositories.
+//
+// Errors:
+//   - fault.ErrUnauthenticated if an anonymous user needs to authenticate
+//   - fault.ErrForbidden if the account does not have access to the repository
+func CheckRepoPermission(account *Account, repo *Repo, act string) error {
+	if act == "read" && repo.IsPublic() {
+		return nil
+	}
+
+	if account == nil {
+		return fault.ErrUnauthenticated
+	}
+
+	return account.CheckPermissionIn(
+		repo.GetDomainAddress(),
+		fmt.Sprintf("/repositories/%d", repo.GetID()),
+		act,
+	)
+}
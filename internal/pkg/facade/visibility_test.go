@@ -0,0 +1,45 @@
+/*
+ * Copyright 2021 Meraj Sahebdar
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package facade
+
+import (
+	"testing"
+
+	"bitban.io/server/internal/pkg/fault"
+	"bitban.io/server/internal/pkg/orm/entity"
+)
+
+func TestCheckRepoPermission(t *testing.T) {
+	for _, tc := range []struct {
+		visibility string
+		act        string
+		expected   error
+	}{
+		{VisibilityPublic, "read", nil},
+		{VisibilityPublic, "write", fault.ErrUnauthenticated},
+		{VisibilityPrivate, "read", fault.ErrUnauthenticated},
+		{VisibilityPrivate, "write", fault.ErrUnauthenticated},
+	} {
+		repo := &Repo{
+			repositoryEntity: &entity.Repository{ID: 1, Visibility: tc.visibility},
+		}
+
+		if err := CheckRepoPermission(nil, repo, tc.act); err != tc.expected {
+			t.Errorf("expected anonymous %s on a %s repository to get: %v, got: %v", tc.act, tc.visibility, tc.expected, err)
+		}
+	}
+}
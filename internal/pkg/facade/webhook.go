@@ -0,0 +1,642 @@
+/*
+ * Copyright 2021 Meraj Sahebdar
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package facade
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-git/v5/plumbing/protocol/packp"
+	"github.com/uptrace/bun"
+	"github.com/volatiletech/null/v8"
+	"go.uber.org/zap"
+	"bitban.io/server/internal/cfg"
+	"bitban.io/server/internal/pkg/fault"
+	"bitban.io/server/internal/pkg/orm"
+	"bitban.io/server/internal/pkg/orm/entity"
+	"bitban.io/server/internal/pkg/util"
+)
+
+// Webhook Events
+const (
+	WebhookEventPush = "push"
+)
+
+// WebhookEvents The events which webhooks can subscribe to.
+var WebhookEvents = []string{
+	WebhookEventPush,
+}
+
+// Webhook Headers
+const (
+	WebhookEventHeader     = "X-Bitban-Event"
+	WebhookDeliveryHeader  = "X-Bitban-Delivery"
+	WebhookSignatureHeader = "X-Bitban-Signature-256"
+)
+
+var (
+	// WebhookTimeout The time to wait for a webhook to respond to an attempt.
+	WebhookTimeout = 10 * time.Second
+
+	// WebhookMaxElapsedTime The time to keep retrying a failed delivery.
+	WebhookMaxElapsedTime = 10 * time.Minute
+)
+
+// maxWebhookResponseSize The size of the response body kept for a delivery.
+const maxWebhookResponseSize = 64 << 10
+
+// maxPushPayloadCommits The number of commits listed in a push payload.
+const maxPushPayloadCommits = 20
+
+var (
+	// ErrInvalidWebhookURL
+	ErrInvalidWebhookURL = errors.New("the webhook url must be an absolute http or https url")
+
+	// ErrInvalidWebhookEvent
+	ErrInvalidWebhookEvent = errors.New("the webhook event is not supported")
+)
+
+// WebhookConfig
+type WebhookConfig struct {
+	URL      string
+	Secret   string
+	Events   []string
+	IsActive bool
+}
+
+// PushPayloadSignature
+type PushPayloadSignature struct {
+	Name  string    `json:"name"`
+	Email string    `json:"email"`
+	Date  time.Time `json:"date"`
+}
+
+// PushPayloadCommit
+type PushPayloadCommit struct {
+	ID        string               `json:"id"`
+	Message   string               `json:"message"`
+	Author    PushPayloadSignature `json:"author"`
+	Committer PushPayloadSignature `json:"committer"`
+}
+
+// PushPayloadRepository
+type PushPayloadRepository struct {
+	ID      int64  `json:"id"`
+	Domain  string `json:"domain"`
+	Address string `json:"address"`
+}
+
+// PushPayloadUser
+type PushPayloadUser struct {
+	ID      int64  `json:"id"`
+	Address string `json:"address"`
+}
+
+// PushPayload The body of the webhook requests delivering a push event.
+type PushPayload struct {
+	Ref        string                `json:"ref"`
+	Before     string                `json:"before"`
+	After      string                `json:"after"`
+	Created    bool                  `json:"created"`
+	Deleted    bool                  `json:"deleted"`
+	Forced     bool                  `json:"forced"`
+	Repository PushPayloadRepository `json:"repository"`
+	Pusher     *PushPayloadUser      `json:"pusher"`
+	Commits    []*PushPayloadCommit  `json:"commits"`
+}
+
+// SignWebhookPayload Returns the HMAC-SHA256 signature of the payload sent in
+// the `X-Bitban-Signature-256` header.
+func SignWebhookPayload(secret string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// checkWebhookConfig
+//
+// Errors:
+//   - facade.ErrInvalidWebhookURL if the url is not an absolute http(s) url
+//   - facade.ErrInvalidWebhookEvent if an event is not supported
+func checkWebhookConfig(config *WebhookConfig) error {
+	if u, err := url.Parse(config.URL); err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+		return ErrInvalidWebhookURL
+	}
+
+	for _, event := range config.Events {
+		supported := false
+		for _, e := range WebhookEvents {
+			if e == event {
+				supported = true
+			}
+		}
+
+		if !supported {
+			return ErrInvalidWebhookEvent
+		}
+	}
+
+	return nil
+}
+
+// GetWebhooks Returns the webhooks of the repository.
+func (f *Repo) GetWebhooks() ([]*entity.Webhook, error) {
+	var webhooks []*entity.Webhook
+	if err := orm.GetBunInstance().
+		NewSelect().
+		Model(&webhooks).
+		Where("? = ?", bun.Ident("webhook.repository_id"), f.GetID()).
+		Where("? IS NULL", bun.Ident("webhook.removed_at")).
+		Order("webhook.id").
+		Scan(f.ctx); err != nil {
+		return nil, err
+	}
+
+	return webhooks, nil
+}
+
+// getWebhook
+//
+// Errors:
+//   - fault.ErrResourceNotFound if the repository does not have such a webhook
+func (f *Repo) getWebhook(id int64) (*entity.Webhook, error) {
+	webhook := new(entity.Webhook)
+	if err := orm.GetBunInstance().
+		NewSelect().
+		Model(webhook).
+		Where("? = ?", bun.Ident("webhook.id"), id).
+		Where("? = ?", bun.Ident("webhook.repository_id"), f.GetID()).
+		Where("? IS NULL", bun.Ident("webhook.removed_at")).
+		Limit(1).
+		Scan(f.ctx); err != nil {
+		return nil, fault.ErrResourceNotFound
+	}
+
+	return webhook, nil
+}
+
+// AddWebhook
+//
+// ErrorsRef:
+//   - facade.checkWebhookConfig
+func (f *Repo) AddWebhook(config *WebhookConfig) (*entity.Webhook, error) {
+	if err := checkWebhookConfig(config); err != nil {
+		return nil, err
+	}
+
+	webhook := &entity.Webhook{
+		URL:          config.URL,
+		Events:       config.Events,
+		IsActive:     config.IsActive,
+		RepositoryID: null.Int64From(f.GetID()),
+	}
+
+	if webhook.Events == nil {
+		webhook.Events = []string{}
+	}
+
+	if config.Secret != "" {
+		if secret, err := util.Encrypt(cfg.Cog.Security.EncryptionKey, config.Secret); err != nil {
+			return nil, err
+		} else {
+			webhook.Secret = secret
+		}
+	}
+
+	if _, err := orm.GetBunInstance().
+		NewInsert().
+		Model(webhook).
+		Column("url", "secret", "events", "is_active", "repository_id").
+		Returning("id", "created_at", "updated_at").
+		Exec(f.ctx); err != nil {
+		return nil, err
+	}
+
+	return webhook, nil
+}
+
+// UpdateWebhook Updates the webhook, keeping its secret if the provided one is nil.
+//
+// ErrorsRef:
+//   - facade.checkWebhookConfig
+//   - facade.Repo.getWebhook
+func (f *Repo) UpdateWebhook(id int64, config *WebhookConfig, secret *string) (*entity.Webhook, error) {
+	if err := checkWebhookConfig(config); err != nil {
+		return nil, err
+	}
+
+	webhook, err := f.getWebhook(id)
+	if err != nil {
+		return nil, err
+	}
+
+	webhook.UpdatedAt = time.Now().In(time.UTC)
+	webhook.URL = config.URL
+	webhook.Events = config.Events
+	webhook.IsActive = config.IsActive
+
+	if webhook.Events == nil {
+		webhook.Events = []string{}
+	}
+
+	if secret != nil {
+		webhook.Secret = ""
+
+		if *secret != "" {
+			if webhook.Secret, err = util.Encrypt(cfg.Cog.Security.EncryptionKey, *secret); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if _, err := orm.GetBunInstance().
+		NewUpdate().
+		Model(webhook).
+		Column("updated_at", "url", "secret", "events", "is_active").
+		WherePK().
+		Exec(f.ctx); err != nil {
+		return nil, err
+	}
+
+	return webhook, nil
+}
+
+// RemoveWebhook
+//
+// ErrorsRef:
+//   - facade.Repo.getWebhook
+func (f *Repo) RemoveWebhook(id int64) (*entity.Webhook, error) {
+	webhook, err := f.getWebhook(id)
+	if err != nil {
+		return nil, err
+	}
+
+	webhook.RemovedAt = null.TimeFrom(time.Now().In(time.UTC))
+	if _, err := orm.GetBunInstance().
+		NewUpdate().
+		Model(webhook).
+		Column("removed_at").
+		WherePK().
+		Exec(f.ctx); err != nil {
+		return nil, err
+	}
+
+	return webhook, nil
+}
+
+// GetWebhookDeliveries Returns the latest deliveries of the webhook.
+//
+// ErrorsRef:
+//   - facade.Repo.getWebhook
+func (f *Repo) GetWebhookDeliveries(id int64, limit int) ([]*entity.WebhookDelivery, error) {
+	if _, err := f.getWebhook(id); err != nil {
+		return nil, err
+	}
+
+	var deliveries []*entity.WebhookDelivery
+	if err := orm.GetBunInstance().
+		NewSelect().
+		Model(&deliveries).
+		Where("? = ?", bun.Ident("webhook_delivery.webhook_id"), id).
+		Order("webhook_delivery.id DESC").
+		Limit(limit).
+		Scan(f.ctx); err != nil {
+		return nil, err
+	}
+
+	return deliveries, nil
+}
+
+// GetRepoByWebhookId Returns the repository of a webhook.
+//
+// Errors:
+//   - fault.ErrResourceNotFound if there is no such webhook
+// ErrorsRef:
+//   - facade.GetRepoById
+func GetRepoByWebhookId(ctx context.Context, id int64) (*Repo, error) {
+	webhook := new(entity.Webhook)
+	if err := orm.GetBunInstance().
+		NewSelect().
+		Model(webhook).
+		Where("? = ?", bun.Ident("webhook.id"), id).
+		Where("? IS NULL", bun.Ident("webhook.removed_at")).
+		Limit(1).
+		Scan(ctx); err != nil {
+		return nil, fault.ErrResourceNotFound
+	}
+
+	return GetRepoById(ctx, webhook.RepositoryID.Int64)
+}
+
+// getActiveWebhooks Returns the active webhooks subscribed to the event.
+func (f *Repo) getActiveWebhooks(ctx context.Context, event string) ([]*entity.Webhook, error) {
+	var webhooks []*entity.Webhook
+	if err := orm.GetBunInstance().
+		NewSelect().
+		Model(&webhooks).
+		Where("? = ?", bun.Ident("webhook.repository_id"), f.GetID()).
+		Where("? = ?", bun.Ident("webhook.is_active"), true).
+		Where("? = ANY(?)", event, bun.Ident("webhook.events")).
+		Where("? IS NULL", bun.Ident("webhook.removed_at")).
+		Order("webhook.id").
+		Scan(ctx); err != nil {
+		return nil, err
+	}
+
+	return webhooks, nil
+}
+
+// pushPayloadSignatureFrom
+func pushPayloadSignatureFrom(signature Signature) PushPayloadSignature {
+	return PushPayloadSignature{
+		Name:  signature.Name,
+		Email: signature.Email,
+		Date:  signature.When,
+	}
+}
+
+// PushPayloadFrom Returns the payload describing a reference update of a push.
+func (f *Repo) PushPayloadFrom(ctx context.Context, update *packp.Command) (*PushPayload, error) {
+	payload := &PushPayload{
+		Ref:     update.Name.String(),
+		Before:  update.Old.String(),
+		After:   update.New.String(),
+		Created: update.Action() == packp.Create,
+		Deleted: update.Action() == packp.Delete,
+		Repository: PushPayloadRepository{
+			ID:      f.GetID(),
+			Domain:  f.GetDomainAddress(),
+			Address: f.GetEntity().Address,
+		},
+		Commits: []*PushPayloadCommit{},
+	}
+
+	if pusherID := GetPusherID(ctx); pusherID != 0 {
+		if account, err := GetAccountByUserId(ctx, pusherID); err != nil {
+			return nil, err
+		} else {
+			payload.Pusher = &PushPayloadUser{
+				ID:      pusherID,
+				Address: account.GetDomain().Address,
+			}
+		}
+	}
+
+	if payload.Deleted {
+		return payload, nil
+	}
+
+	logConfig := &LogConfig{
+		Rev:   update.New.String(),
+		Limit: maxPushPayloadCommits,
+	}
+
+	if payload.Created {
+		logConfig.Limit = 1
+	} else {
+		if ok, err := f.isAncestor(update.Old, update.New); err != nil {
+			return nil, err
+		} else {
+			payload.Forced = !ok
+		}
+
+		logConfig.Exclude = update.Old.String()
+	}
+
+	commits, err := f.Log(logConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, commit := range commits {
+		payload.Commits = append(payload.Commits, &PushPayloadCommit{
+			ID:        commit.Hash,
+			Message:   commit.Message,
+			Author:    pushPayloadSignatureFrom(commit.Author),
+			Committer: pushPayloadSignatureFrom(commit.Committer),
+		})
+	}
+
+	return payload, nil
+}
+
+// postWebhook Makes a single attempt to deliver the payload.
+func postWebhook(client *http.Client, webhook *entity.Webhook, delivery *entity.WebhookDelivery) (int, string, error) {
+	req, err := http.NewRequest(http.MethodPost, webhook.URL, strings.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, "", err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "bitban-webhook")
+	req.Header.Set(WebhookEventHeader, delivery.Event)
+	req.Header.Set(WebhookDeliveryHeader, strconv.FormatInt(delivery.ID, 10))
+
+	if webhook.Secret != "" {
+		secret, err := util.Decrypt(cfg.Cog.Security.EncryptionKey, webhook.Secret)
+		if err != nil {
+			return 0, "", err
+		}
+
+		req.Header.Set(WebhookSignatureHeader, SignWebhookPayload(secret, []byte(delivery.Payload)))
+	}
+
+	res, err := client.Do(req)
+	if err != nil {
+		return 0, "", err
+	}
+	defer res.Body.Close()
+
+	body, _ := ioutil.ReadAll(io.LimitReader(res.Body, maxWebhookResponseSize))
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return res.StatusCode, string(body), fmt.Errorf("the webhook responded with status %d", res.StatusCode)
+	}
+
+	return res.StatusCode, string(body), nil
+}
+
+// queueWebhookDelivery Records a pending delivery of the payload, which is
+// attempted by facade.DeliverDueWebhooks.
+func queueWebhookDelivery(ctx context.Context, webhook *entity.Webhook, event string, payload []byte) (*entity.WebhookDelivery, error) {
+	delivery := &entity.WebhookDelivery{
+		Event:         event,
+		Payload:       string(payload),
+		NextAttemptAt: null.TimeFrom(time.Now().In(time.UTC)),
+		WebhookID:     null.Int64From(webhook.ID),
+	}
+
+	if _, err := orm.GetBunInstance().
+		NewInsert().
+		Model(delivery).
+		Column("event", "payload", "next_attempt_at", "webhook_id").
+		Returning("id", "created_at", "updated_at").
+		Exec(ctx); err != nil {
+		return nil, err
+	}
+
+	return delivery, nil
+}
+
+// retryIntervalFrom Returns the wait before the next attempt of an operation
+// retried in the background, which has been attempted for the given times.
+func retryIntervalFrom(attempts int) time.Duration {
+	b := util.NewExponentialBackoff(0)
+
+	interval := b.NextBackOff()
+	for i := 1; i < attempts; i++ {
+		interval = b.NextBackOff()
+	}
+
+	return interval
+}
+
+// attemptWebhookDelivery Makes an attempt to deliver the payload and records
+// it, scheduling the next attempt with an exponential backoff until the
+// delivery is older than facade.WebhookMaxElapsedTime.
+func attemptWebhookDelivery(ctx context.Context, client *http.Client, delivery *entity.WebhookDelivery) error {
+	statusCode, response, err := postWebhook(client, delivery.Webhook, delivery)
+
+	delivery.UpdatedAt = time.Now().In(time.UTC)
+	delivery.Attempts++
+	delivery.StatusCode = null.NewInt(statusCode, statusCode != 0)
+	delivery.Response = null.NewString(response, statusCode != 0)
+	delivery.Error = null.NewString("", false)
+	delivery.NextAttemptAt = null.NewTime(time.Time{}, false)
+
+	if err != nil {
+		delivery.Error = null.StringFrom(err.Error())
+
+		next := delivery.UpdatedAt.Add(retryIntervalFrom(delivery.Attempts))
+		if next.Sub(delivery.CreatedAt) < WebhookMaxElapsedTime {
+			delivery.NextAttemptAt = null.TimeFrom(next)
+		}
+	} else {
+		delivery.DeliveredAt = null.TimeFrom(delivery.UpdatedAt)
+	}
+
+	if _, err := orm.GetBunInstance().
+		NewUpdate().
+		Model(delivery).
+		Column("updated_at", "attempts", "status_code", "response", "error", "delivered_at", "next_attempt_at").
+		WherePK().
+		Exec(ctx); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// DeliverDueWebhooks Attempts the pending webhook deliveries which are due, and
+// returns how many of them failed.
+func DeliverDueWebhooks(ctx context.Context) (int, error) {
+	var deliveries []*entity.WebhookDelivery
+	if err := orm.
+		GetBunInstance().
+		NewSelect().
+		Model(&deliveries).
+		Relation("Webhook").
+		Where("? <= ?", bun.Ident("webhook_delivery.next_attempt_at"), time.Now().In(time.UTC)).
+		Where("? IS NULL", bun.Ident("webhook.removed_at")).
+		Order("webhook_delivery.id").
+		Scan(ctx); err != nil {
+		return 0, err
+	}
+
+	client := newOutboundHTTPClient(WebhookTimeout)
+
+	failed := 0
+	for _, delivery := range deliveries {
+		if ctx.Err() != nil {
+			break
+		}
+
+		if err := attemptWebhookDelivery(ctx, client, delivery); err != nil {
+			return failed, err
+		} else if delivery.Error.Valid {
+			failed++
+			cfg.Log.Warn(
+				"failed to deliver a webhook",
+				zap.Int64("webhookId", delivery.Webhook.ID),
+				zap.Int64("deliveryId", delivery.ID),
+				zap.String("error", delivery.Error.String),
+			)
+		}
+	}
+
+	return failed, nil
+}
+
+// webhookHook Queues the deliveries of the push events to the webhooks of the
+// repository.
+type webhookHook struct{}
+
+// PreReceive
+func (h *webhookHook) PreReceive(ctx context.Context, repo *Repo, commands []*packp.Command) error {
+	return nil
+}
+
+// PostReceive
+func (h *webhookHook) PostReceive(ctx context.Context, repo *Repo, updates []*packp.Command) error {
+	webhooks, err := repo.getActiveWebhooks(ctx, WebhookEventPush)
+	if err != nil || len(webhooks) == 0 {
+		return err
+	}
+
+	for _, update := range updates {
+		payload, err := repo.PushPayloadFrom(ctx, update)
+		if err != nil {
+			return err
+		}
+
+		var body bytes.Buffer
+		if err := json.NewEncoder(&body).Encode(payload); err != nil {
+			return err
+		}
+
+		for _, webhook := range webhooks {
+			if _, err := queueWebhookDelivery(ctx, webhook, WebhookEventPush, body.Bytes()); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// newWebhookHook
+func newWebhookHook() ReceiveHook {
+	return &webhookHook{}
+}
+
+// WebhookHookOpt Queues the deliveries of the push events to webhooks after
+// every push.
+var WebhookHookOpt = AsReceiveHook(newWebhookHook)
@@ -0,0 +1,226 @@
+/*
+ * Copyright 2021 Meraj Sahebdar
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package facade
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"strings"
+	"time"
+
+	"bitban.io/server/internal/cfg"
+)
+
+func TestSignWebhookPayload(t *testing.T) {
+	expected := "sha256=f7bc83f430538424b13298e6aa6fb143ef4d59a14946175997479dbc2d1a3cd8"
+	if got := SignWebhookPayload("key", []byte("The quick brown fox jumps over the lazy dog")); got != expected {
+		t.Errorf("expected signature: %s, got: %s", expected, got)
+	}
+}
+
+func TestWebhook(t *testing.T) {
+	t.Run("webhook", func(t *testing.T) {
+		ctx := context.Background()
+
+		repo := createTestRepo(t, ctx)
+
+		t.Run("invalid config", func(t *testing.T) {
+			if _, err := repo.AddWebhook(&WebhookConfig{URL: "ftp://example.com"}); err != ErrInvalidWebhookURL {
+				t.Errorf("expected error: %v, got: %v", ErrInvalidWebhookURL, err)
+			}
+
+			if _, err := repo.AddWebhook(&WebhookConfig{URL: "https://example.com", Events: []string{"star"}}); err != ErrInvalidWebhookEvent {
+				t.Errorf("expected error: %v, got: %v", ErrInvalidWebhookEvent, err)
+			}
+		})
+
+		t.Run("forbidden address", func(t *testing.T) {
+			var calls int32
+
+			receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				atomic.AddInt32(&calls, 1)
+			}))
+			defer receiver.Close()
+
+			webhook, err := repo.AddWebhook(&WebhookConfig{
+				URL:      receiver.URL,
+				Events:   []string{WebhookEventPush},
+				IsActive: true,
+			})
+			if err != nil {
+				t.Fatalf("failed to add the webhook, got error: %s", err.Error())
+			}
+
+			if _, err := queueWebhookDelivery(ctx, webhook, WebhookEventPush, []byte(`{}`)); err != nil {
+				t.Fatalf("failed to queue the delivery, got error: %s", err.Error())
+			}
+
+			if _, err := DeliverDueWebhooks(ctx); err != nil {
+				t.Fatalf("got an unexpected error: %s", err.Error())
+			}
+
+			if atomic.LoadInt32(&calls) != 0 {
+				t.Errorf("expected the webhook not to reach a loopback address")
+			}
+
+			if deliveries, err := repo.GetWebhookDeliveries(webhook.ID, 10); err != nil {
+				t.Errorf("got an unexpected error: %s", err.Error())
+			} else if len(deliveries) != 1 || !strings.Contains(deliveries[0].Error.String, ErrAddressForbidden.Error()) {
+				t.Errorf("expected the delivery to fail with: %v, got: %+v", ErrAddressForbidden, deliveries)
+			}
+
+			if _, err := repo.RemoveWebhook(webhook.ID); err != nil {
+				t.Fatalf("failed to remove the webhook, got error: %s", err.Error())
+			}
+		})
+
+		cfg.Cog.Security.AllowPrivateNetworks = true
+		defer func() { cfg.Cog.Security.AllowPrivateNetworks = false }()
+
+		t.Run("deliver", func(t *testing.T) {
+			var calls int32
+			var signature string
+
+			receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if atomic.AddInt32(&calls, 1) == 1 {
+					w.WriteHeader(http.StatusInternalServerError)
+					return
+				}
+
+				body, _ := ioutil.ReadAll(r.Body)
+				if r.Header.Get(WebhookEventHeader) != WebhookEventPush {
+					w.WriteHeader(http.StatusBadRequest)
+					return
+				}
+
+				signature = SignWebhookPayload("secret", body)
+				if r.Header.Get(WebhookSignatureHeader) != signature {
+					w.WriteHeader(http.StatusUnauthorized)
+					return
+				}
+
+				w.Write([]byte("accepted"))
+			}))
+			defer receiver.Close()
+
+			webhook, err := repo.AddWebhook(&WebhookConfig{
+				URL:      receiver.URL,
+				Secret:   "secret",
+				Events:   []string{WebhookEventPush},
+				IsActive: true,
+			})
+			if err != nil {
+				t.Fatalf("failed to add the webhook, got error: %s", err.Error())
+			}
+
+			if webhook.Secret == "" || webhook.Secret == "secret" {
+				t.Errorf("expected the secret to be stored encrypted, got: %s", webhook.Secret)
+			}
+
+			WebhookMaxElapsedTime = 30 * time.Second
+
+			delivery, err := queueWebhookDelivery(ctx, webhook, WebhookEventPush, []byte(`{"ref":"refs/heads/main"}`))
+			if err != nil {
+				t.Fatalf("failed to queue the delivery, got error: %s", err.Error())
+			}
+
+			if _, err := DeliverDueWebhooks(ctx); err != nil {
+				t.Fatalf("got an unexpected error: %s", err.Error())
+			}
+
+			var retryAt time.Time
+			if deliveries, err := repo.GetWebhookDeliveries(webhook.ID, 10); err != nil {
+				t.Errorf("got an unexpected error: %s", err.Error())
+			} else if len(deliveries) != 1 || deliveries[0].ID != delivery.ID {
+				t.Errorf("expected the queued delivery to be recorded")
+			} else if retryAt = deliveries[0].NextAttemptAt.Time;  deliveries[0].Attempts != 1 || deliveries[0].DeliveredAt.Valid || !deliveries[0].NextAttemptAt.Valid {
+				t.Errorf("expected a failed attempt to be retried later, got: %+v", deliveries[0])
+			} else if deliveries[0].StatusCode.Int != http.StatusInternalServerError {
+				t.Errorf("expected status code: %d, got: %v", http.StatusInternalServerError, deliveries[0].StatusCode)
+			}
+
+			if _, err := DeliverDueWebhooks(ctx); err != nil {
+				t.Fatalf("got an unexpected error: %s", err.Error())
+			}
+
+			if atomic.LoadInt32(&calls) != 1 {
+				t.Errorf("expected the delivery to wait for the next attempt")
+			}
+
+			time.Sleep(time.Until(retryAt))
+
+			if _, err := DeliverDueWebhooks(ctx); err != nil {
+				t.Fatalf("got an unexpected error: %s", err.Error())
+			}
+
+			if deliveries, err := repo.GetWebhookDeliveries(webhook.ID, 10); err != nil {
+				t.Errorf("got an unexpected error: %s", err.Error())
+			} else if len(deliveries) != 1 {
+				t.Errorf("expected the delivery to be recorded once")
+			} else {
+				if deliveries[0].Attempts != 2 {
+					t.Errorf("expected 2 attempts, got: %d", deliveries[0].Attempts)
+				}
+
+				if !deliveries[0].StatusCode.Valid || deliveries[0].StatusCode.Int != http.StatusOK {
+					t.Errorf("expected status code: %d, got: %v", http.StatusOK, deliveries[0].StatusCode)
+				}
+
+				if deliveries[0].Response.String != "accepted" || !deliveries[0].DeliveredAt.Valid || deliveries[0].NextAttemptAt.Valid {
+					t.Errorf("expected an accepted delivery, got: %s", deliveries[0].Response.String)
+				}
+			}
+		})
+
+		t.Run("give up", func(t *testing.T) {
+			receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusServiceUnavailable)
+			}))
+			defer receiver.Close()
+
+			webhook, err := repo.AddWebhook(&WebhookConfig{
+				URL:      receiver.URL,
+				Events:   []string{WebhookEventPush},
+				IsActive: true,
+			})
+			if err != nil {
+				t.Fatalf("failed to add the webhook, got error: %s", err.Error())
+			}
+
+			WebhookMaxElapsedTime = 0
+			defer func() { WebhookMaxElapsedTime = 10 * time.Minute }()
+
+			if _, err := queueWebhookDelivery(ctx, webhook, WebhookEventPush, []byte(`{}`)); err != nil {
+				t.Fatalf("failed to queue the delivery, got error: %s", err.Error())
+			}
+
+			if _, err := DeliverDueWebhooks(ctx); err != nil {
+				t.Fatalf("got an unexpected error: %s", err.Error())
+			}
+
+			if deliveries, err := repo.GetWebhookDeliveries(webhook.ID, 10); err != nil {
+				t.Errorf("got an unexpected error: %s", err.Error())
+			} else if len(deliveries) != 1 || deliveries[0].Attempts != 1 || deliveries[0].NextAttemptAt.Valid || !deliveries[0].Error.Valid {
+				t.Errorf("expected the delivery to be given up after an attempt")
+			}
+		})
+	})
+}
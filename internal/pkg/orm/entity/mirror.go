@@ -0,0 +1,56 @@
+/*
+ * Copyright 2021 Meraj Sahebdar
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package entity
+
+import (
+	"time"
+
+	"github.com/uptrace/bun"
+	"github.com/volatiletech/null/v8"
+)
+
+// PullMirror
+type PullMirror struct {
+	bun.BaseModel `bun:"pull_mirrors,select:pull_mirrors,alias:pull_mirror"`
+	ID            int64       `bun:"id"`
+	CreatedAt     time.Time   `bun:"created_at"`
+	UpdatedAt     time.Time   `bun:"updated_at"`
+	URL           string      `bun:"url"`
+	SyncedAt      null.Time   `bun:"synced_at"`
+	SyncError     null.String `bun:"sync_error"`
+	RepositoryID  null.Int64  `bun:"repository_id"`
+	Repository    *Repository `bun:"rel:belongs-to,join:repository_id=id"`
+}
+
+// PushMirror
+type PushMirror struct {
+	bun.BaseModel `bun:"push_mirrors,select:push_mirrors,alias:push_mirror"`
+	ID            int64       `bun:"id"`
+	CreatedAt     time.Time   `bun:"created_at"`
+	UpdatedAt     time.Time   `bun:"updated_at"`
+	URL           string      `bun:"url"`
+	Username      string      `bun:"username"`
+	Password      string      `bun:"password"`
+	IsActive      bool        `bun:"is_active"`
+	Attempts      int         `bun:"attempts"`
+	PushedAt      null.Time   `bun:"pushed_at"`
+	PushError     null.String `bun:"push_error"`
+	QueuedAt      null.Time   `bun:"queued_at"`
+	NextAttemptAt null.Time   `bun:"next_attempt_at"`
+	RepositoryID  null.Int64  `bun:"repository_id"`
+	Repository    *Repository `bun:"rel:belongs-to,join:repository_id=id"`
+}
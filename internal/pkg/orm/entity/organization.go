@@ -0,0 +1,41 @@
+/*
+ * Copyright 2021 Meraj Sahebdar
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package entity
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/uptrace/bun"
+	"github.com/volatiletech/null/v8"
+)
+
+// Organization
+type Organization struct {
+	bun.BaseModel `bun:"organizations,select:organizations,alias:organization"`
+	DomainID      int64     `bun:"domain_id,pk"`
+	DomainType    string    `bun:"domain_type"`
+	CreatedAt     time.Time `bun:"created_at"`
+	UpdatedAt     time.Time `bun:"updated_at"`
+	RemovedAt     null.Time `bun:"removed_at"`
+	Domain        *Domain   `bun:"rel:belongs-to,join:domain_id=id"`
+}
+
+// String
+func (e *Organization) String() string {
+	return fmt.Sprintf("Organization<%d>", e.DomainID)
+}
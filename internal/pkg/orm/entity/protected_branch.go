@@ -0,0 +1,40 @@
+/*
+ * Copyright 2021 Meraj Sahebdar
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package entity
+
+import (
+	"time"
+
+	"github.com/uptrace/bun"
+	"github.com/volatiletech/null/v8"
+)
+
+// ProtectedBranch
+type ProtectedBranch struct {
+	bun.BaseModel  `bun:"protected_branches,select:protected_branches,alias:protected_branch"`
+	ID             int64       `bun:"id"`
+	CreatedAt      time.Time   `bun:"created_at"`
+	UpdatedAt      time.Time   `bun:"updated_at"`
+	RemovedAt      null.Time   `bun:"removed_at"`
+	Pattern        string      `bun:"pattern"`
+	BlockForcePush bool        `bun:"block_force_push"`
+	BlockDeletion  bool        `bun:"block_deletion"`
+	RestrictPush   bool        `bun:"restrict_push"`
+	PusherIDs      []int64     `bun:"pusher_ids,array"`
+	RepositoryID   null.Int64  `bun:"repository_id"`
+	Repository     *Repository `bun:"rel:belongs-to,join:repository_id=id"`
+}
@@ -0,0 +1,51 @@
+/*
+ * Copyright 2021 Meraj Sahebdar
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package entity
+
+import (
+	"time"
+
+	"github.com/uptrace/bun"
+	"github.com/volatiletech/null/v8"
+)
+
+// PushEvent
+type PushEvent struct {
+	bun.BaseModel `bun:"push_events,select:push_events,alias:push_event"`
+	ID            int64            `bun:"id"`
+	CreatedAt     time.Time        `bun:"created_at"`
+	Protocol      string           `bun:"protocol"`
+	RemoteAddr    string           `bun:"remote_addr"`
+	PusherID      null.Int64       `bun:"pusher_id"`
+	Pusher        *User            `bun:"rel:belongs-to,join:pusher_id=domain_id"`
+	RepositoryID  null.Int64       `bun:"repository_id"`
+	Repository    *Repository      `bun:"rel:belongs-to,join:repository_id=id"`
+	RefUpdates    []*PushRefUpdate `bun:"rel:has-many,join:id=push_event_id"`
+}
+
+// PushRefUpdate
+type PushRefUpdate struct {
+	bun.BaseModel `bun:"push_ref_updates,select:push_ref_updates,alias:push_ref_update"`
+	ID            int64      `bun:"id"`
+	Ref           string     `bun:"ref"`
+	OldSha        string     `bun:"old_sha"`
+	NewSha        string     `bun:"new_sha"`
+	Action        string     `bun:"action"`
+	IsForced      bool       `bun:"is_forced"`
+	PushEventID   null.Int64 `bun:"push_event_id"`
+	PushEvent     *PushEvent `bun:"rel:belongs-to,join:push_event_id=id"`
+}
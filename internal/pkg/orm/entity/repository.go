@@ -31,6 +31,8 @@ type Repository struct {
 	UpdatedAt     time.Time  `bun:"updated_at"`
 	RemovedAt     null.Time  `bun:"removed_at"`
 	Address       string     `bun:"address"`
+	Visibility    string     `bun:"visibility"`
 	DomainID      null.Int64 `bun:"domain_id"`
 	Domain        *Domain    `bun:"rel:belongs-to,join:domain_id=id"`
+	ParentID      null.Int64 `bun:"parent_id"`
 }
@@ -0,0 +1,42 @@
+/*
+ * Copyright 2021 Meraj Sahebdar
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package entity
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/uptrace/bun"
+	"github.com/volatiletech/null/v8"
+)
+
+// Team
+type Team struct {
+	bun.BaseModel  `bun:"teams,select:teams,alias:team"`
+	ID             int64         `bun:"id"`
+	CreatedAt      time.Time     `bun:"created_at"`
+	UpdatedAt      time.Time     `bun:"updated_at"`
+	Name           string        `bun:"name"`
+	Description    string        `bun:"description"`
+	OrganizationID null.Int64    `bun:"organization_id"`
+	Organization   *Organization `bun:"rel:belongs-to,join:organization_id=domain_id"`
+}
+
+// String
+func (e *Team) String() string {
+	return fmt.Sprintf("Team<%d>", e.ID)
+}
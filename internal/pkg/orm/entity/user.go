@@ -38,6 +38,7 @@ type User struct {
 	Domain        *Domain     `bun:"rel:belongs-to,join:domain_id=id"`
 	Emails        []*Email    `bun:"rel:has-many,join:domain_id=user_id"`
 	Tokens        []*Token    `bun:"rel:has-many,join:domain_id=user_id"`
+	SshKeys       []*SshKey   `bun:"rel:has-many,join:domain_id=user_id"`
 }
 
 // String
@@ -0,0 +1,57 @@
+/*
+ * Copyright 2021 Meraj Sahebdar
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package entity
+
+import (
+	"time"
+
+	"github.com/uptrace/bun"
+	"github.com/volatiletech/null/v8"
+)
+
+// Webhook
+type Webhook struct {
+	bun.BaseModel `bun:"webhooks,select:webhooks,alias:webhook"`
+	ID            int64       `bun:"id"`
+	CreatedAt     time.Time   `bun:"created_at"`
+	UpdatedAt     time.Time   `bun:"updated_at"`
+	RemovedAt     null.Time   `bun:"removed_at"`
+	URL           string      `bun:"url"`
+	Secret        string      `bun:"secret"`
+	Events        []string    `bun:"events,array"`
+	IsActive      bool        `bun:"is_active"`
+	RepositoryID  null.Int64  `bun:"repository_id"`
+	Repository    *Repository `bun:"rel:belongs-to,join:repository_id=id"`
+}
+
+// WebhookDelivery
+type WebhookDelivery struct {
+	bun.BaseModel `bun:"webhook_deliveries,select:webhook_deliveries,alias:webhook_delivery"`
+	ID            int64       `bun:"id"`
+	CreatedAt     time.Time   `bun:"created_at"`
+	UpdatedAt     time.Time   `bun:"updated_at"`
+	Event         string      `bun:"event"`
+	Payload       string      `bun:"payload"`
+	Attempts      int         `bun:"attempts"`
+	StatusCode    null.Int    `bun:"status_code"`
+	Response      null.String `bun:"response"`
+	Error         null.String `bun:"error"`
+	DeliveredAt   null.Time   `bun:"delivered_at"`
+	NextAttemptAt null.Time   `bun:"next_attempt_at"`
+	WebhookID     null.Int64  `bun:"webhook_id"`
+	Webhook       *Webhook    `bun:"rel:belongs-to,join:webhook_id=id"`
+}
@@ -0,0 +1,34 @@
+-- +migrate Up
+CREATE TABLE "ssh_keys" (
+  "id" bigserial,
+  "created_at" timestamp with time zone NOT NULL DEFAULT NOW(),
+  "updated_at" timestamp with time zone NOT NULL DEFAULT NOW(),
+  "removed_at" timestamp with time zone DEFAULT NULL,
+  "title" varchar(250) NOT NULL,
+  "type" varchar(100) NOT NULL,
+  "fingerprint" varchar(250) NOT NULL,
+  "content" text NOT NULL,
+  "last_used_at" timestamp with time zone DEFAULT NULL,
+  "user_id" bigint DEFAULT NULL
+);
+
+ALTER TABLE "ssh_keys"
+  ADD CONSTRAINT ssh_keys_pkey PRIMARY KEY ("id");
+
+ALTER TABLE "ssh_keys"
+  ADD CONSTRAINT ssh_keys_user_fk FOREIGN KEY ("user_id") REFERENCES "users" ("domain_id") ON DELETE CASCADE;
+
+CREATE UNIQUE INDEX ssh_keys_fingerprint_unq ON "ssh_keys" ("fingerprint")
+WHERE
+  removed_at IS NULL;
+
+-- +migrate Down
+DROP INDEX ssh_keys_fingerprint_unq;
+
+ALTER TABLE "ssh_keys"
+  DROP CONSTRAINT ssh_keys_user_fk;
+
+ALTER TABLE "ssh_keys"
+  DROP CONSTRAINT ssh_keys_pkey;
+
+DROP TABLE "ssh_keys";
@@ -0,0 +1,34 @@
+-- +migrate Up
+CREATE TABLE "protected_branches" (
+  "id" bigserial,
+  "created_at" timestamp with time zone NOT NULL DEFAULT NOW(),
+  "updated_at" timestamp with time zone NOT NULL DEFAULT NOW(),
+  "removed_at" timestamp with time zone DEFAULT NULL,
+  "pattern" varchar(250) NOT NULL,
+  "block_force_push" boolean NOT NULL DEFAULT TRUE,
+  "block_deletion" boolean NOT NULL DEFAULT TRUE,
+  "restrict_push" boolean NOT NULL DEFAULT FALSE,
+  "pusher_ids" bigint[] NOT NULL DEFAULT '{}',
+  "repository_id" bigint DEFAULT NULL
+);
+
+ALTER TABLE "protected_branches"
+  ADD CONSTRAINT protected_branches_pkey PRIMARY KEY ("id");
+
+ALTER TABLE "protected_branches"
+  ADD CONSTRAINT protected_branches_repository_fk FOREIGN KEY ("repository_id") REFERENCES "repositories" ("id") ON DELETE CASCADE;
+
+CREATE UNIQUE INDEX protected_branches_repository_pattern_unq ON "protected_branches" ("repository_id", "pattern")
+WHERE
+  removed_at IS NULL;
+
+-- +migrate Down
+DROP INDEX protected_branches_repository_pattern_unq;
+
+ALTER TABLE "protected_branches"
+  DROP CONSTRAINT protected_branches_repository_fk;
+
+ALTER TABLE "protected_branches"
+  DROP CONSTRAINT protected_branches_pkey;
+
+DROP TABLE "protected_branches";
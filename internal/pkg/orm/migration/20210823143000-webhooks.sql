@@ -0,0 +1,55 @@
+-- +migrate Up
+CREATE TABLE "webhooks" (
+  "id" bigserial,
+  "created_at" timestamp with time zone NOT NULL DEFAULT NOW(),
+  "updated_at" timestamp with time zone NOT NULL DEFAULT NOW(),
+  "removed_at" timestamp with time zone DEFAULT NULL,
+  "url" varchar(2048) NOT NULL,
+  "secret" varchar(250) NOT NULL DEFAULT '',
+  "events" varchar(100)[] NOT NULL DEFAULT '{}',
+  "is_active" boolean NOT NULL DEFAULT TRUE,
+  "repository_id" bigint DEFAULT NULL
+);
+
+ALTER TABLE "webhooks"
+  ADD CONSTRAINT webhooks_pkey PRIMARY KEY ("id");
+
+ALTER TABLE "webhooks"
+  ADD CONSTRAINT webhooks_repository_fk FOREIGN KEY ("repository_id") REFERENCES "repositories" ("id") ON DELETE CASCADE;
+
+CREATE TABLE "webhook_deliveries" (
+  "id" bigserial,
+  "created_at" timestamp with time zone NOT NULL DEFAULT NOW(),
+  "updated_at" timestamp with time zone NOT NULL DEFAULT NOW(),
+  "event" varchar(100) NOT NULL,
+  "payload" text NOT NULL,
+  "attempts" integer NOT NULL DEFAULT 0,
+  "status_code" integer DEFAULT NULL,
+  "response" text DEFAULT NULL,
+  "error" text DEFAULT NULL,
+  "delivered_at" timestamp with time zone DEFAULT NULL,
+  "webhook_id" bigint DEFAULT NULL
+);
+
+ALTER TABLE "webhook_deliveries"
+  ADD CONSTRAINT webhook_deliveries_pkey PRIMARY KEY ("id");
+
+ALTER TABLE "webhook_deliveries"
+  ADD CONSTRAINT webhook_deliveries_webhook_fk FOREIGN KEY ("webhook_id") REFERENCES "webhooks" ("id") ON DELETE CASCADE;
+
+-- +migrate Down
+ALTER TABLE "webhook_deliveries"
+  DROP CONSTRAINT webhook_deliveries_webhook_fk;
+
+ALTER TABLE "webhook_deliveries"
+  DROP CONSTRAINT webhook_deliveries_pkey;
+
+DROP TABLE "webhook_deliveries";
+
+ALTER TABLE "webhooks"
+  DROP CONSTRAINT webhooks_repository_fk;
+
+ALTER TABLE "webhooks"
+  DROP CONSTRAINT webhooks_pkey;
+
+DROP TABLE "webhooks";
@@ -0,0 +1,66 @@
+-- +migrate Up
+CREATE TABLE "push_events" (
+  "id" bigserial,
+  "created_at" timestamp with time zone NOT NULL DEFAULT NOW(),
+  "protocol" varchar(10) NOT NULL,
+  "remote_addr" varchar(100) NOT NULL DEFAULT '',
+  "pusher_id" bigint DEFAULT NULL,
+  "repository_id" bigint DEFAULT NULL
+);
+
+ALTER TABLE "push_events"
+  ADD CONSTRAINT push_events_pkey PRIMARY KEY ("id");
+
+ALTER TABLE "push_events"
+  ADD CONSTRAINT push_events_pusher_fk FOREIGN KEY ("pusher_id") REFERENCES "users" ("domain_id") ON DELETE SET NULL;
+
+ALTER TABLE "push_events"
+  ADD CONSTRAINT push_events_repository_fk FOREIGN KEY ("repository_id") REFERENCES "repositories" ("id") ON DELETE CASCADE;
+
+CREATE INDEX push_events_repository_idx ON "push_events" ("repository_id", "id");
+
+CREATE INDEX push_events_pusher_idx ON "push_events" ("pusher_id", "id");
+
+CREATE TABLE "push_ref_updates" (
+  "id" bigserial,
+  "ref" varchar(250) NOT NULL,
+  "old_sha" varchar(40) NOT NULL,
+  "new_sha" varchar(40) NOT NULL,
+  "action" varchar(10) NOT NULL,
+  "is_forced" boolean NOT NULL DEFAULT FALSE,
+  "push_event_id" bigint DEFAULT NULL
+);
+
+ALTER TABLE "push_ref_updates"
+  ADD CONSTRAINT push_ref_updates_pkey PRIMARY KEY ("id");
+
+ALTER TABLE "push_ref_updates"
+  ADD CONSTRAINT push_ref_updates_push_event_fk FOREIGN KEY ("push_event_id") REFERENCES "push_events" ("id") ON DELETE CASCADE;
+
+CREATE INDEX push_ref_updates_push_event_idx ON "push_ref_updates" ("push_event_id");
+
+-- +migrate Down
+DROP INDEX push_ref_updates_push_event_idx;
+
+ALTER TABLE "push_ref_updates"
+  DROP CONSTRAINT push_ref_updates_push_event_fk;
+
+ALTER TABLE "push_ref_updates"
+  DROP CONSTRAINT push_ref_updates_pkey;
+
+DROP TABLE "push_ref_updates";
+
+DROP INDEX push_events_pusher_idx;
+
+DROP INDEX push_events_repository_idx;
+
+ALTER TABLE "push_events"
+  DROP CONSTRAINT push_events_repository_fk;
+
+ALTER TABLE "push_events"
+  DROP CONSTRAINT push_events_pusher_fk;
+
+ALTER TABLE "push_events"
+  DROP CONSTRAINT push_events_pkey;
+
+DROP TABLE "push_events";
@@ -0,0 +1,11 @@
+-- +migrate Up
+DROP INDEX repositories_address_unq;
+
+CREATE UNIQUE INDEX repositories_address_unq ON "repositories" ("domain_id", "address")
+WHERE
+  removed_at IS NULL;
+
+-- +migrate Down
+DROP INDEX repositories_address_unq;
+
+CREATE UNIQUE INDEX repositories_address_unq ON "repositories" ("domain_id", "address");
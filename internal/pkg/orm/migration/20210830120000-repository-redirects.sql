@@ -0,0 +1,34 @@
+-- +migrate Up
+CREATE TABLE "repository_redirects" (
+  "id" bigserial,
+  "created_at" timestamp with time zone NOT NULL DEFAULT NOW(),
+  "updated_at" timestamp with time zone NOT NULL DEFAULT NOW(),
+  "address" varchar(250) NOT NULL,
+  "domain_id" bigint DEFAULT NULL,
+  "repository_id" bigint DEFAULT NULL
+);
+
+ALTER TABLE "repository_redirects"
+  ADD CONSTRAINT repository_redirects_pkey PRIMARY KEY ("id");
+
+ALTER TABLE "repository_redirects"
+  ADD CONSTRAINT repository_redirects_domain_fk FOREIGN KEY ("domain_id") REFERENCES "domains" ("id") ON DELETE CASCADE;
+
+ALTER TABLE "repository_redirects"
+  ADD CONSTRAINT repository_redirects_repository_fk FOREIGN KEY ("repository_id") REFERENCES "repositories" ("id") ON DELETE CASCADE;
+
+CREATE UNIQUE INDEX repository_redirects_address_unq ON "repository_redirects" ("domain_id", "address");
+
+-- +migrate Down
+DROP INDEX repository_redirects_address_unq;
+
+ALTER TABLE "repository_redirects"
+  DROP CONSTRAINT repository_redirects_repository_fk;
+
+ALTER TABLE "repository_redirects"
+  DROP CONSTRAINT repository_redirects_domain_fk;
+
+ALTER TABLE "repository_redirects"
+  DROP CONSTRAINT repository_redirects_pkey;
+
+DROP TABLE "repository_redirects";
@@ -0,0 +1,7 @@
+-- +migrate Up
+ALTER TABLE "repositories"
+  ADD COLUMN "visibility" varchar(10) NOT NULL DEFAULT 'private';
+
+-- +migrate Down
+ALTER TABLE "repositories"
+  DROP COLUMN "visibility";
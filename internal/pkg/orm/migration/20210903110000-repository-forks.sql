@@ -0,0 +1,17 @@
+-- +migrate Up
+ALTER TABLE "repositories"
+  ADD COLUMN "parent_id" bigint DEFAULT NULL;
+
+ALTER TABLE "repositories"
+  ADD CONSTRAINT repositories_parent_fk FOREIGN KEY ("parent_id") REFERENCES "repositories" ("id") ON DELETE SET NULL;
+
+CREATE INDEX repositories_parent_idx ON "repositories" ("parent_id");
+
+-- +migrate Down
+DROP INDEX repositories_parent_idx;
+
+ALTER TABLE "repositories"
+  DROP CONSTRAINT repositories_parent_fk;
+
+ALTER TABLE "repositories"
+  DROP COLUMN "parent_id";
@@ -0,0 +1,33 @@
+-- +migrate Up
+CREATE TABLE "pull_mirrors" (
+  "id" bigserial,
+  "created_at" timestamp with time zone NOT NULL DEFAULT NOW(),
+  "updated_at" timestamp with time zone NOT NULL DEFAULT NOW(),
+  "url" varchar(2048) NOT NULL,
+  "synced_at" timestamp with time zone DEFAULT NULL,
+  "sync_error" text DEFAULT NULL,
+  "repository_id" bigint DEFAULT NULL
+);
+
+ALTER TABLE "pull_mirrors"
+  ADD CONSTRAINT pull_mirrors_pkey PRIMARY KEY ("id");
+
+ALTER TABLE "pull_mirrors"
+  ADD CONSTRAINT pull_mirrors_repository_fk FOREIGN KEY ("repository_id") REFERENCES "repositories" ("id") ON DELETE CASCADE;
+
+CREATE UNIQUE INDEX pull_mirrors_repository_unq ON "pull_mirrors" ("repository_id");
+
+CREATE INDEX pull_mirrors_synced_at_idx ON "pull_mirrors" ("synced_at");
+
+-- +migrate Down
+DROP INDEX pull_mirrors_synced_at_idx;
+
+DROP INDEX pull_mirrors_repository_unq;
+
+ALTER TABLE "pull_mirrors"
+  DROP CONSTRAINT pull_mirrors_repository_fk;
+
+ALTER TABLE "pull_mirrors"
+  DROP CONSTRAINT pull_mirrors_pkey;
+
+DROP TABLE "pull_mirrors";
@@ -0,0 +1,33 @@
+-- +migrate Up
+CREATE TABLE "push_mirrors" (
+  "id" bigserial,
+  "created_at" timestamp with time zone NOT NULL DEFAULT NOW(),
+  "updated_at" timestamp with time zone NOT NULL DEFAULT NOW(),
+  "url" varchar(2048) NOT NULL,
+  "username" varchar(250) NOT NULL DEFAULT '',
+  "password" text NOT NULL DEFAULT '',
+  "is_active" boolean NOT NULL DEFAULT TRUE,
+  "attempts" integer NOT NULL DEFAULT 0,
+  "pushed_at" timestamp with time zone DEFAULT NULL,
+  "push_error" text DEFAULT NULL,
+  "repository_id" bigint DEFAULT NULL
+);
+
+ALTER TABLE "push_mirrors"
+  ADD CONSTRAINT push_mirrors_pkey PRIMARY KEY ("id");
+
+ALTER TABLE "push_mirrors"
+  ADD CONSTRAINT push_mirrors_repository_fk FOREIGN KEY ("repository_id") REFERENCES "repositories" ("id") ON DELETE CASCADE;
+
+CREATE INDEX push_mirrors_repository_idx ON "push_mirrors" ("repository_id");
+
+-- +migrate Down
+DROP INDEX push_mirrors_repository_idx;
+
+ALTER TABLE "push_mirrors"
+  DROP CONSTRAINT push_mirrors_repository_fk;
+
+ALTER TABLE "push_mirrors"
+  DROP CONSTRAINT push_mirrors_pkey;
+
+DROP TABLE "push_mirrors";
@@ -0,0 +1,29 @@
+-- +migrate Up
+CREATE TABLE "organizations" (
+  "domain_id" bigint NOT NULL,
+  "domain_type" varchar(100) NOT NULL,
+  "created_at" timestamp with time zone NOT NULL DEFAULT NOW(),
+  "updated_at" timestamp with time zone NOT NULL DEFAULT NOW(),
+  "removed_at" timestamp with time zone DEFAULT NULL
+);
+
+ALTER TABLE "organizations"
+  ADD CONSTRAINT organizations_pkey PRIMARY KEY ("domain_id");
+
+ALTER TABLE "organizations"
+  ADD CONSTRAINT organization_domain_check CHECK ("domain_type" = 'organization');
+
+ALTER TABLE "organizations"
+  ADD CONSTRAINT organization_domain_fk FOREIGN KEY ("domain_id", "domain_type") REFERENCES "domains" ("id", "type") ON DELETE CASCADE;
+
+-- +migrate Down
+ALTER TABLE "organizations"
+  DROP CONSTRAINT organization_domain_fk;
+
+ALTER TABLE "organizations"
+  DROP CONSTRAINT organization_domain_check;
+
+ALTER TABLE "organizations"
+  DROP CONSTRAINT organizations_pkey;
+
+DROP TABLE "organizations";
@@ -0,0 +1,28 @@
+-- +migrate Up
+CREATE TABLE "teams" (
+  "id" bigserial,
+  "created_at" timestamp with time zone NOT NULL DEFAULT NOW(),
+  "updated_at" timestamp with time zone NOT NULL DEFAULT NOW(),
+  "name" varchar(250) NOT NULL,
+  "description" text NOT NULL DEFAULT '',
+  "organization_id" bigint NOT NULL
+);
+
+ALTER TABLE "teams"
+  ADD CONSTRAINT teams_pkey PRIMARY KEY ("id");
+
+ALTER TABLE "teams"
+  ADD CONSTRAINT teams_organization_fk FOREIGN KEY ("organization_id") REFERENCES "organizations" ("domain_id") ON DELETE CASCADE;
+
+CREATE UNIQUE INDEX teams_organization_name_unq ON "teams" ("organization_id", "name");
+
+-- +migrate Down
+DROP INDEX teams_organization_name_unq;
+
+ALTER TABLE "teams"
+  DROP CONSTRAINT teams_organization_fk;
+
+ALTER TABLE "teams"
+  DROP CONSTRAINT teams_pkey;
+
+DROP TABLE "teams";
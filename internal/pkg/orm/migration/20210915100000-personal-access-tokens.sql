@@ -0,0 +1,32 @@
+-- +migrate Up
+CREATE TABLE "personal_access_tokens" (
+  "id" bigserial,
+  "created_at" timestamp with time zone NOT NULL DEFAULT NOW(),
+  "updated_at" timestamp with time zone NOT NULL DEFAULT NOW(),
+  "removed_at" timestamp with time zone DEFAULT NULL,
+  "name" varchar(250) NOT NULL,
+  "token_hash" varchar(64) NOT NULL,
+  "scopes" varchar(20)[] NOT NULL DEFAULT '{}',
+  "expires_at" timestamp with time zone DEFAULT NULL,
+  "last_used_at" timestamp with time zone DEFAULT NULL,
+  "user_id" bigint DEFAULT NULL
+);
+
+ALTER TABLE "personal_access_tokens"
+  ADD CONSTRAINT personal_access_tokens_pkey PRIMARY KEY ("id");
+
+ALTER TABLE "personal_access_tokens"
+  ADD CONSTRAINT personal_access_tokens_user_fk FOREIGN KEY ("user_id") REFERENCES "users" ("domain_id") ON DELETE CASCADE;
+
+CREATE UNIQUE INDEX personal_access_tokens_token_hash_unq ON "personal_access_tokens" ("token_hash");
+
+-- +migrate Down
+DROP INDEX personal_access_tokens_token_hash_unq;
+
+ALTER TABLE "personal_access_tokens"
+  DROP CONSTRAINT personal_access_tokens_user_fk;
+
+ALTER TABLE "personal_access_tokens"
+  DROP CONSTRAINT personal_access_tokens_pkey;
+
+DROP TABLE "personal_access_tokens";
@@ -0,0 +1,13 @@
+-- +migrate Up
+ALTER TABLE "webhook_deliveries"
+  ADD COLUMN "next_attempt_at" timestamp with time zone DEFAULT NULL;
+
+CREATE INDEX webhook_deliveries_next_attempt_at_idx ON "webhook_deliveries" ("next_attempt_at")
+WHERE
+  next_attempt_at IS NOT NULL;
+
+-- +migrate Down
+DROP INDEX webhook_deliveries_next_attempt_at_idx;
+
+ALTER TABLE "webhook_deliveries"
+  DROP COLUMN "next_attempt_at";
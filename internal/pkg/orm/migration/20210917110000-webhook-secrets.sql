@@ -0,0 +1,7 @@
+-- +migrate Up
+ALTER TABLE "webhooks"
+  ALTER COLUMN "secret" TYPE text;
+
+-- +migrate Down
+ALTER TABLE "webhooks"
+  ALTER COLUMN "secret" TYPE varchar(250);
@@ -0,0 +1,19 @@
+-- +migrate Up
+ALTER TABLE "push_mirrors"
+  ADD COLUMN "queued_at" timestamp with time zone DEFAULT NULL;
+
+ALTER TABLE "push_mirrors"
+  ADD COLUMN "next_attempt_at" timestamp with time zone DEFAULT NULL;
+
+CREATE INDEX push_mirrors_next_attempt_at_idx ON "push_mirrors" ("next_attempt_at")
+WHERE
+  next_attempt_at IS NOT NULL;
+
+-- +migrate Down
+DROP INDEX push_mirrors_next_attempt_at_idx;
+
+ALTER TABLE "push_mirrors"
+  DROP COLUMN "next_attempt_at";
+
+ALTER TABLE "push_mirrors"
+  DROP COLUMN "queued_at";
@@ -22,6 +22,7 @@ import (
 	"net"
 
 	gossh "golang.org/x/crypto/ssh"
+	"bitban.io/server/internal/pkg/facade"
 )
 
 // srvContextKey
@@ -39,6 +40,9 @@ type cmdContextKey struct{}
 // chContextKey
 type chContextKey struct{}
 
+// accountContextKey
+type accountContextKey struct{}
+
 // newContext
 func newContext(srv *Server, netConn net.Conn) (nextCtx context.Context, cancel context.CancelFunc) {
 	nextCtx, cancel = context.WithCancel(context.Background())
@@ -68,6 +72,17 @@ func GetContextEnvs(ctx context.Context) []requestEnv {
 	return ctx.Value(envsContextKey{}).([]requestEnv)
 }
 
+// GetContextEnv Returns the value of an environment variable sent by the client.
+func GetContextEnv(ctx context.Context, key string) string {
+	for _, env := range GetContextEnvs(ctx) {
+		if env.Key == key {
+			return env.Value
+		}
+	}
+
+	return ""
+}
+
 // withContextCh
 func withContextCh(ctx context.Context, ch gossh.Channel) context.Context {
 	return context.WithValue(
@@ -108,3 +123,21 @@ func withContextCmd(ctx context.Context, cmd RequestCmd) context.Context {
 func GetContextCmd(ctx context.Context) RequestCmd {
 	return ctx.Value(cmdContextKey{}).(RequestCmd)
 }
+
+// withContextAccount
+func withContextAccount(ctx context.Context, account *facade.Account) context.Context {
+	return context.WithValue(
+		ctx,
+		accountContextKey{},
+		account,
+	)
+}
+
+// GetContextAccount Returns the account authenticated by its public key.
+func GetContextAccount(ctx context.Context) (*facade.Account, error) {
+	if account, ok := ctx.Value(accountContextKey{}).(*facade.Account); ok {
+		return account, nil
+	} else {
+		return nil, errors.New("no authenticated account")
+	}
+}
@@ -27,10 +27,21 @@ import (
 // commandParserRegex
 var commandParserRegex = regexp.MustCompile(`^(git[-|\s]upload-pack|git[-|\s]upload-archive|git[-|\s]receive-pack) '(.*)'$`)
 
+// lfsCommandParserRegex Matches the command git lfs runs over ssh to obtain
+// the credentials of its http api, where the path may be left unquoted.
+var lfsCommandParserRegex = regexp.MustCompile(`^(git-lfs-authenticate) '?([^' ]+)'? (upload|download)$`)
+
 // parseExecCommand
 func parseExecCommand(p []byte, cmd *RequestCmd) error {
 	gossh.Unmarshal(p, cmd)
 
+	if matches := lfsCommandParserRegex.FindAllStringSubmatch(cmd.Line, 1); len(matches) > 0 {
+		cmd.Name = matches[0][1]
+		cmd.Args = strings.TrimPrefix(matches[0][2], "/") + " " + matches[0][3]
+
+		return nil
+	}
+
 	if matches := commandParserRegex.FindAllStringSubmatch(cmd.Line, 1); len(matches) == 0 {
 		return errors.New("the requested command is wrong")
 	} else {
@@ -25,8 +25,12 @@ import (
 	"go.uber.org/zap"
 	gossh "golang.org/x/crypto/ssh"
 	"bitban.io/server/internal/cfg"
+	"bitban.io/server/internal/pkg/facade"
 )
 
+// fingerprintExtension
+const fingerprintExtension = "fingerprint"
+
 // HandlerFunc
 type HandlerFunc func(ctx context.Context) error
 
@@ -67,6 +71,18 @@ func (srv *Server) ListenAndServe(listener net.Listener) {
 						srv.log.Error("error on handshaking", zap.Error(err))
 					}
 				} else {
+					if fingerprint, ok := sshConn.Permissions.Extensions[fingerprintExtension]; ok {
+						if account, err := facade.GetAccountBySshKey(ctx, fingerprint); err != nil {
+							srv.log.Error("failed to resolve the authenticated account", zap.Error(err))
+						} else {
+							ctx = withContextAccount(ctx, account)
+
+							if err := facade.TouchSshKey(ctx, fingerprint); err != nil {
+								srv.log.Error("failed to track the public key usage", zap.Error(err))
+							}
+						}
+					}
+
 					go gossh.DiscardRequests(reqs)
 					for ch := range chans {
 						newSession(srv, sshConn, ctx, ch)
@@ -77,6 +93,27 @@ func (srv *Server) ListenAndServe(listener net.Listener) {
 	}
 }
 
+// publicKeyCallback Accepts the offered public key only if it is owned by an account.
+func publicKeyCallback(connMeta gossh.ConnMetadata, key gossh.PublicKey) (*gossh.Permissions, error) {
+	fingerprint := gossh.FingerprintSHA256(key)
+
+	if _, err := facade.GetAccountBySshKey(context.Background(), fingerprint); err != nil {
+		return nil, err
+	}
+
+	return &gossh.Permissions{
+		Extensions: map[string]string{
+			fingerprintExtension: fingerprint,
+		},
+	}, nil
+}
+
+// keyboardInteractiveCallback Lets clients without a registered public key in
+// as an anonymous session, so handlers can reject them with a clear message.
+func keyboardInteractiveCallback(connMeta gossh.ConnMetadata, challenge gossh.KeyboardInteractiveChallenge) (*gossh.Permissions, error) {
+	return &gossh.Permissions{}, nil
+}
+
 // NewServer
 func NewServer(logScope string) *Server {
 	var err error
@@ -98,8 +135,10 @@ func NewServer(logScope string) *Server {
 		log.Fatal("failed to parse private key")
 	}
 
-	sshConfig := &gossh.ServerConfig{}
-	sshConfig.NoClientAuth = true
+	sshConfig := &gossh.ServerConfig{
+		PublicKeyCallback:           publicKeyCallback,
+		KeyboardInteractiveCallback: keyboardInteractiveCallback,
+	}
 	sshConfig.AddHostKey(privateKey)
 
 	return &Server{
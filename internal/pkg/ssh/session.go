@@ -107,8 +107,12 @@ func (sess *session) track() {
 						nextCtx = withContextCmd(sess.ctx, cmd)
 						nextCtx = withContextEnvs(nextCtx, envs)
 						nextCtx = withContextCh(nextCtx, sess.Channel)
-						handler(nextCtx)
-						sess.exit([]byte{0, 0, 0, 0})
+						if err := handler(nextCtx); err != nil {
+							fmt.Fprintf(sess.Stderr(), "bitban: %s\n", err.Error())
+							sess.exit([]byte{0, 0, 0, 1})
+						} else {
+							sess.exit([]byte{0, 0, 0, 0})
+						}
 					} else {
 						sess.Error(
 							"no handler was found for the requested command",
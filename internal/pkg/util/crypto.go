@@ -0,0 +1,104 @@
+/*
+ * Copyright 2021 Meraj Sahebdar
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package util
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"io"
+)
+
+var (
+	// ErrMissingEncryptionKey
+	ErrMissingEncryptionKey = errors.New("the encryption key is not configured")
+
+	// ErrMalformedCiphertext
+	ErrMalformedCiphertext = errors.New("the ciphertext is malformed")
+)
+
+// newGCM Returns the AES-256-GCM cipher keyed by the SHA-256 of the key.
+func newGCM(key string) (cipher.AEAD, error) {
+	if key == "" {
+		return nil, ErrMissingEncryptionKey
+	}
+
+	sum := sha256.Sum256([]byte(key))
+
+	block, err := aes.NewCipher(sum[:])
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
+
+// Encrypt Seals the plaintext by AES-256-GCM, and returns the nonce along with
+// the ciphertext in base64.
+func Encrypt(key string, plaintext string) (string, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", err
+	}
+
+	return base64.StdEncoding.EncodeToString(gcm.Seal(nonce, nonce, []byte(plaintext), nil)), nil
+}
+
+// Decrypt Opens a ciphertext sealed by `Encrypt`.
+func Decrypt(key string, ciphertext string) (string, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return "", err
+	}
+
+	byt, err := base64.StdEncoding.DecodeString(ciphertext)
+	if err != nil || len(byt) < gcm.NonceSize() {
+		return "", ErrMalformedCiphertext
+	}
+
+	if plaintext, err := gcm.Open(nil, byt[:gcm.NonceSize()], byt[gcm.NonceSize():], nil); err != nil {
+		return "", ErrMalformedCiphertext
+	} else {
+		return string(plaintext), nil
+	}
+}
+
+// RandomHex Returns n random bytes in hex.
+func RandomHex(n int) (string, error) {
+	byt := make([]byte, n)
+	if _, err := io.ReadFull(rand.Reader, byt); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(byt), nil
+}
+
+// HashToken Returns the SHA-256 of a random token in hex. Unlike the
+// passwords, the tokens carry enough entropy to be looked up by their digest.
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
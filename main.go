@@ -17,6 +17,8 @@
 package main
 
 import (
+	"context"
+	"fmt"
 	"os"
 
 	"github.com/alecthomas/kong"
@@ -24,8 +26,10 @@ import (
 	"go.uber.org/zap"
 	"bitban.io/server/internal/app/api"
 	"bitban.io/server/internal/app/controller"
+	"bitban.io/server/internal/app/job"
 	"bitban.io/server/internal/app/resolver"
 	"bitban.io/server/internal/cfg"
+	"bitban.io/server/internal/pkg/facade"
 	"bitban.io/server/internal/pkg/orm"
 )
 
@@ -75,11 +79,18 @@ func (cmd *RunCmd) Run() error {
 		// Controllers
 		controller.AccountOpt,
 		controller.RepoOpt,
+		controller.OrganizationOpt,
 		// Resolvers
 		resolver.ConfigOpt,
+		// Hooks
+		fx.Options(hookOpts...),
 		// APIs
 		api.EchoOpt,
 		api.SshOpt,
+		// Jobs
+		job.PurgeOpt,
+		job.MirrorOpt,
+		job.WebhookOpt,
 	}
 
 	// Provide fx.NopLogger if it is not running in verbose mode.
@@ -95,12 +106,72 @@ func (cmd *RunCmd) Run() error {
 	return nil
 }
 
+// HookUpdateCmd
+type HookUpdateCmd struct {
+	Ref string `arg:"" help:"The updated reference."`
+	Old string `arg:"" help:"The old object of the reference."`
+	New string `arg:"" help:"The new object of the reference."`
+}
+
+// Run Checks a reference update of a push, rejecting it by a non-zero exit code.
+func (cmd *HookUpdateCmd) Run() error {
+	if err := facade.RunUpdateHook(context.Background(), cmd.Ref, cmd.Old, cmd.New); err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+
+	return nil
+}
+
+// HookPreReceiveCmd
+type HookPreReceiveCmd struct{}
+
+// Run Runs the pre-receive hooks, rejecting the push by a non-zero exit code.
+func (cmd *HookPreReceiveCmd) Run() error {
+	if err := fx.New(fx.Options(hookOpts...), fx.NopLogger).Err(); err != nil {
+		return err
+	}
+
+	if err := facade.RunPreReceiveHook(context.Background(), os.Stdin); err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+
+	return nil
+}
+
+// HookPostReceiveCmd
+type HookPostReceiveCmd struct{}
+
+// Run Runs the post-receive hooks.
+func (cmd *HookPostReceiveCmd) Run() error {
+	if err := fx.New(fx.Options(hookOpts...), fx.NopLogger).Err(); err != nil {
+		return err
+	}
+
+	return facade.RunPostReceiveHook(context.Background(), os.Stdin)
+}
+
+// hookOpts Provides the receive hooks to both the app and the git hooks.
+var hookOpts = []fx.Option{
+	facade.MirrorHookOpt,
+	facade.PushEventHookOpt,
+	facade.WebhookHookOpt,
+	facade.PushMirrorHookOpt,
+	facade.ReceiveHookOpt,
+}
+
 // CLI
 var CLI struct {
 	Migrate struct {
 		Up   MigrateUpCmd   `cmd:"up" help:"Apply all migrations."`
 		Down MigrateDownCmd `cmd:"down" help:"Drop migrations."`
 	} `cmd:"migrate" help:"Run the migrator."`
+	Hook struct {
+		PreReceive  HookPreReceiveCmd  `cmd:"pre-receive" help:"Run the pre-receive hook."`
+		Update      HookUpdateCmd      `cmd:"update" help:"Run the update hook."`
+		PostReceive HookPostReceiveCmd `cmd:"post-receive" help:"Run the post-receive hook."`
+	} `cmd:"hook" hidden:"" help:"Run a git hook of the git binary."`
 	Run RunCmd `cmd:"run" help:"Run the app."`
 }
 